@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/models/pat"
+	"backend/models/session"
+	"backend/models/user"
+)
+
+type contextKey int
+
+const (
+	currentUserKey contextKey = iota
+	currentSessionKey
+)
+
+// Authenticator resolves the "Authorization: Bearer" header of a request into a user. The token is either
+// a session JWT or a personal access token.
+type Authenticator struct {
+	Tokens         *TokenManager
+	Users          user.UserModel
+	Sessions       session.SessionModel
+	PersonalTokens pat.TokenModel
+}
+
+func NewAuthenticator(tokens *TokenManager, users user.UserModel, sessions session.SessionModel, personalTokens pat.TokenModel) *Authenticator {
+	return &Authenticator{Tokens: tokens, Users: users, Sessions: sessions, PersonalTokens: personalTokens}
+}
+
+// Middleware rejects requests without a valid session bearer token and stores the authenticated user in the
+// request context. Personal access tokens are not accepted here; see RequireScope.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr, ok := bearerToken(r)
+		if !ok {
+			unauthorized(w, "Missing bearer token")
+			return
+		}
+
+		if strings.HasPrefix(tokenStr, pat.Prefix) {
+			log.Println("Rejected personal access token on session-only route")
+			forbidden(w, "This endpoint requires a login session", "")
+			return
+		}
+
+		ctx, ok := a.authenticateSession(r.Context(), tokenStr)
+		if !ok {
+			unauthorized(w, "Invalid or expired token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Require wraps a single handler with Middleware.
+func (a *Authenticator) Require(handler http.HandlerFunc) http.Handler {
+	return a.Middleware(handler)
+}
+
+// RequireScope wraps handler so it accepts login sessions, which may do anything, and personal access
+// tokens that were granted scope.
+func (a *Authenticator) RequireScope(scope string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr, ok := bearerToken(r)
+		if !ok {
+			unauthorized(w, "Missing bearer token")
+			return
+		}
+
+		if !strings.HasPrefix(tokenStr, pat.Prefix) {
+			ctx, ok := a.authenticateSession(r.Context(), tokenStr)
+			if !ok {
+				unauthorized(w, "Invalid or expired token")
+				return
+			}
+			handler.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		token, exists := a.PersonalTokens.GetTokenByHash(HashSecret(tokenStr))
+		if !exists || token.IsExpired(time.Now()) {
+			log.Println("Rejected unknown or expired personal access token")
+			unauthorized(w, "Invalid or expired token")
+			return
+		}
+		if !token.HasScope(scope) {
+			log.Printf("Personal access token id=%d lacks scope %s", token.ID, scope)
+			forbidden(w, "Token is missing the "+scope+" scope", scope)
+			return
+		}
+
+		u, exists := a.Users.GetUserByID(token.UserID)
+		if !exists {
+			log.Printf("Token owner no longer exists: user_id=%d", token.UserID)
+			unauthorized(w, "Invalid or expired token")
+			return
+		}
+
+		a.PersonalTokens.MarkTokenUsed(token.ID)
+		handler.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+	})
+}
+
+// authenticateSession verifies a session JWT and returns ctx carrying its user and session.
+func (a *Authenticator) authenticateSession(ctx context.Context, tokenStr string) (context.Context, bool) {
+	claims, err := a.Tokens.ParseAccessToken(tokenStr)
+	if err != nil {
+		log.Printf("Rejected access token: %v", err)
+		return nil, false
+	}
+
+	// Access tokens die with their session so logout takes effect before the token expires
+	s, exists := a.Sessions.GetSessionByID(claims.SessionID)
+	if !exists || s.UserID != claims.UserID || !s.IsActive(time.Now()) {
+		log.Printf("Token session is no longer active: session_id=%d", claims.SessionID)
+		return nil, false
+	}
+
+	u, exists := a.Users.GetUserByID(claims.UserID)
+	if !exists {
+		log.Printf("Token subject no longer exists: user_id=%d", claims.UserID)
+		return nil, false
+	}
+
+	return context.WithValue(WithUser(ctx, u), currentSessionKey, s.ID), true
+}
+
+func WithUser(ctx context.Context, u user.User) context.Context {
+	return context.WithValue(ctx, currentUserKey, u)
+}
+
+// CurrentUser returns the user stored by Middleware, if any.
+func CurrentUser(r *http.Request) (user.User, bool) {
+	u, ok := r.Context().Value(currentUserKey).(user.User)
+	return u, ok
+}
+
+// CurrentSessionID returns the ID of the session the request was authenticated with, if any.
+func CurrentSessionID(r *http.Request) (int, bool) {
+	id, ok := r.Context().Value(currentSessionKey).(int)
+	return id, ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="codeck"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+func forbidden(w http.ResponseWriter, message, scope string) {
+	challenge := `Bearer realm="codeck", error="insufficient_scope"`
+	if scope != "" {
+		challenge += `, scope="` + scope + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, message, http.StatusForbidden)
+}
@@ -0,0 +1,231 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// Identity is what an external provider tells us about the user who signed in.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an OAuth2 authorization-code login provider.
+type Provider interface {
+	Name() string
+	// AuthCodeURL returns the URL to send the browser to. verifier is the PKCE code verifier kept for Exchange.
+	AuthCodeURL(state, verifier string) string
+	// Exchange trades the authorization code for a token and loads the identity of the signed in user.
+	Exchange(ctx context.Context, code, verifier string) (Identity, error)
+}
+
+// Endpoints are the URLs a provider is reached at. They can be overridden to point at a mock server.
+type Endpoints struct {
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+}
+
+// oauthProvider implements the authorization-code flow with PKCE and reads the identity through fetch.
+type oauthProvider struct {
+	name   string
+	config oauth2.Config
+	fetch  func(ctx context.Context, client *http.Client) (Identity, error)
+}
+
+func (p *oauthProvider) Name() string {
+	return p.name
+}
+
+func (p *oauthProvider) AuthCodeURL(state, verifier string) string {
+	return p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+func (p *oauthProvider) Exchange(ctx context.Context, code, verifier string) (Identity, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return Identity{}, fmt.Errorf("exchange code: %w", err)
+	}
+	return p.fetch(ctx, p.config.Client(ctx, token))
+}
+
+// NewOIDCProvider reads the identity from the standard OpenID Connect userinfo endpoint.
+func NewOIDCProvider(name, clientID, clientSecret, redirectURL string, endpoints Endpoints) Provider {
+	return &oauthProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+			Endpoint:     oauth2.Endpoint{AuthURL: endpoints.AuthURL, TokenURL: endpoints.TokenURL},
+		},
+		fetch: func(ctx context.Context, client *http.Client) (Identity, error) {
+			var info struct {
+				Subject       string `json:"sub"`
+				Email         string `json:"email"`
+				EmailVerified bool   `json:"email_verified"`
+				Name          string `json:"name"`
+			}
+			if err := getJSON(client, endpoints.UserInfoURL, &info); err != nil {
+				return Identity{}, err
+			}
+			if info.Subject == "" {
+				return Identity{}, errors.New("userinfo has no subject")
+			}
+			return Identity{Subject: info.Subject, Email: info.Email, EmailVerified: info.EmailVerified, Name: info.Name}, nil
+		},
+	}
+}
+
+// NewGitHubProvider uses the GitHub REST API, which is not OIDC: the profile comes from /user and
+// the verified primary address from /user/emails.
+func NewGitHubProvider(clientID, clientSecret, redirectURL string, endpoints Endpoints) Provider {
+	return &oauthProvider{
+		name: "github",
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     oauth2.Endpoint{AuthURL: endpoints.AuthURL, TokenURL: endpoints.TokenURL},
+		},
+		fetch: func(ctx context.Context, client *http.Client) (Identity, error) {
+			var profile struct {
+				ID    int64  `json:"id"`
+				Login string `json:"login"`
+				Name  string `json:"name"`
+			}
+			if err := getJSON(client, endpoints.UserInfoURL, &profile); err != nil {
+				return Identity{}, err
+			}
+			if profile.ID == 0 {
+				return Identity{}, errors.New("github user has no id")
+			}
+
+			var emails []struct {
+				Email    string `json:"email"`
+				Primary  bool   `json:"primary"`
+				Verified bool   `json:"verified"`
+			}
+			if err := getJSON(client, strings.TrimSuffix(endpoints.UserInfoURL, "/")+"/emails", &emails); err != nil {
+				return Identity{}, err
+			}
+
+			identity := Identity{Subject: strconv.FormatInt(profile.ID, 10), Name: profile.Name}
+			if identity.Name == "" {
+				identity.Name = profile.Login
+			}
+			for _, e := range emails {
+				if e.Primary {
+					identity.Email = e.Email
+					identity.EmailVerified = e.Verified
+				}
+			}
+			return identity, nil
+		},
+	}
+}
+
+func getJSON(client *http.Client, url string, dst interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// Registry holds the configured providers by name.
+type Registry map[string]Provider
+
+func NewRegistry(providers ...Provider) Registry {
+	registry := Registry{}
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
+	return registry
+}
+
+// Names returns the configured provider names in alphabetical order.
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegistryFromEnv enables each provider whose <NAME>_CLIENT_ID is set. Callbacks are built from
+// OAUTH_REDIRECT_BASE_URL as <base>/auth/<name>/callback. Endpoints default to the public ones and can be
+// overridden with <NAME>_AUTH_URL, <NAME>_TOKEN_URL and <NAME>_USERINFO_URL. A generic OpenID Connect
+// provider named "oidc" is enabled with OIDC_CLIENT_ID and requires all three URLs.
+func RegistryFromEnv() (Registry, error) {
+	base := os.Getenv("OAUTH_REDIRECT_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	redirect := func(name string) string {
+		return strings.TrimSuffix(base, "/") + "/auth/" + name + "/callback"
+	}
+
+	registry := Registry{}
+	if clientID := os.Getenv("GITHUB_CLIENT_ID"); clientID != "" {
+		endpoints := endpointsFromEnv("GITHUB", Endpoints{
+			AuthURL:     "https://github.com/login/oauth/authorize",
+			TokenURL:    "https://github.com/login/oauth/access_token",
+			UserInfoURL: "https://api.github.com/user",
+		})
+		registry["github"] = NewGitHubProvider(clientID, os.Getenv("GITHUB_CLIENT_SECRET"), redirect("github"), endpoints)
+	}
+	if clientID := os.Getenv("GOOGLE_CLIENT_ID"); clientID != "" {
+		endpoints := endpointsFromEnv("GOOGLE", Endpoints{
+			AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+			TokenURL:    "https://oauth2.googleapis.com/token",
+			UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+		})
+		registry["google"] = NewOIDCProvider("google", clientID, os.Getenv("GOOGLE_CLIENT_SECRET"), redirect("google"), endpoints)
+	}
+	if clientID := os.Getenv("OIDC_CLIENT_ID"); clientID != "" {
+		endpoints := endpointsFromEnv("OIDC", Endpoints{})
+		if endpoints.AuthURL == "" || endpoints.TokenURL == "" || endpoints.UserInfoURL == "" {
+			return nil, errors.New("OIDC_AUTH_URL, OIDC_TOKEN_URL and OIDC_USERINFO_URL are required with OIDC_CLIENT_ID")
+		}
+		registry["oidc"] = NewOIDCProvider("oidc", clientID, os.Getenv("OIDC_CLIENT_SECRET"), redirect("oidc"), endpoints)
+	}
+	return registry, nil
+}
+
+func endpointsFromEnv(prefix string, defaults Endpoints) Endpoints {
+	if v := os.Getenv(prefix + "_AUTH_URL"); v != "" {
+		defaults.AuthURL = v
+	}
+	if v := os.Getenv(prefix + "_TOKEN_URL"); v != "" {
+		defaults.TokenURL = v
+	}
+	if v := os.Getenv(prefix + "_USERINFO_URL"); v != "" {
+		defaults.UserInfoURL = v
+	}
+	return defaults
+}
+
+// GenerateVerifier returns a new PKCE code verifier.
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultIssuer          = "codeck"
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	minSecretLength        = 32
+	challengeTTL           = 5 * time.Minute
+	challengeAudience      = "codeck-2fa"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// accessClaims are the JWT claims of an access token. The subject is the user ID and sid the session it belongs to.
+type accessClaims struct {
+	SessionID int `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// AccessToken is the verified content of an access token.
+type AccessToken struct {
+	UserID    int
+	SessionID int
+	ExpiresAt time.Time
+}
+
+// TokenManager issues and verifies HS256 signed access tokens and knows how long refresh tokens live.
+type TokenManager struct {
+	secret     []byte
+	issuer     string
+	ttl        time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenManager(secret []byte, issuer string, ttl, refreshTTL time.Duration) *TokenManager {
+	return &TokenManager{secret: secret, issuer: issuer, ttl: ttl, refreshTTL: refreshTTL}
+}
+
+// NewTokenManagerFromEnv reads JWT_SECRET (required), JWT_ISSUER, JWT_TTL and REFRESH_TOKEN_TTL (time.Duration strings).
+func NewTokenManagerFromEnv() (*TokenManager, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("JWT_SECRET must be at least %d characters long", minSecretLength)
+	}
+
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		issuer = defaultIssuer
+	}
+
+	ttl, err := durationFromEnv("JWT_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL, err := durationFromEnv("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokenManager([]byte(secret), issuer, ttl, refreshTTL), nil
+}
+
+func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return parsed, nil
+}
+
+// RefreshTokenExpiry returns when a refresh token issued now stops being accepted.
+func (tm *TokenManager) RefreshTokenExpiry() time.Time {
+	return time.Now().Add(tm.refreshTTL)
+}
+
+// IssueAccessToken returns a signed token for userID in sessionID along with its expiry time.
+func (tm *TokenManager) IssueAccessToken(userID, sessionID int) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(tm.ttl)
+	claims := accessClaims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tm.issuer,
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken validates the signature, issuer and expiry of tokenStr and returns who it was issued for.
+func (tm *TokenManager) ParseAccessToken(tokenStr string) (AccessToken, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		return tm.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tm.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return AccessToken{}, fmt.Errorf("%w: bad subject %q", ErrInvalidToken, claims.Subject)
+	}
+	if claims.SessionID <= 0 {
+		return AccessToken{}, fmt.Errorf("%w: missing session", ErrInvalidToken)
+	}
+	return AccessToken{UserID: userID, SessionID: claims.SessionID, ExpiresAt: claims.ExpiresAt.Time}, nil
+}
+
+// IssueChallenge returns a short-lived token proving that userID passed the password step of a two-step
+// login. It is not an access token: it has no session and is only accepted by ParseChallenge.
+func (tm *TokenManager) IssueChallenge(userID int) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(challengeTTL)
+	claims := jwt.RegisteredClaims{
+		Issuer:    tm.issuer,
+		Subject:   strconv.Itoa(userID),
+		Audience:  jwt.ClaimStrings{challengeAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseChallenge validates a token from IssueChallenge and returns the user ID it was issued for.
+func (tm *TokenManager) ParseChallenge(tokenStr string) (int, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		return tm.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tm.issuer),
+		jwt.WithAudience(challengeAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("%w: bad subject %q", ErrInvalidToken, claims.Subject)
+	}
+	return userID, nil
+}
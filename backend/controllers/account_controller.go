@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"backend/auth"
+	"backend/mail"
+	"backend/models/responses"
+	"backend/models/session"
+	"backend/models/user"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 24 * time.Hour
+)
+
+// AccountController handles the mailed-token flows: password reset and email verification.
+type AccountController struct {
+	Model    user.UserModel
+	Sessions session.SessionModel
+	Mailer   mail.Mailer
+	// AppURL is the frontend base URL used to build the links in mails
+	AppURL string
+}
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewAccountController(model user.UserModel, sessions session.SessionModel, mailer mail.Mailer, appURL string) *AccountController {
+	return &AccountController{Model: model, Sessions: sessions, Mailer: mailer, AppURL: appURL}
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Mail a single-use password reset link to the address. The response is the same whether or not an account exists for it
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body responses.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} responses.SuccessResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /password/forgot [post]
+func (ac *AccountController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode forgot password payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.Email == "" {
+		http.Error(w, "Email is required", http.StatusBadRequest)
+		return
+	}
+
+	// Unknown addresses get the same answer so the endpoint cannot be used to find accounts
+	if u, exists := ac.Model.GetUserByEmail(request.Email); exists {
+		message := "Someone asked to reset the password of your CODECK account.\n\n" +
+			"Open this link within an hour to choose a new password:\n%s\n\n" +
+			"If it wasn't you, you can ignore this email."
+		if err := ac.sendToken(u, user.TokenPurposePasswordReset, passwordResetTTL, "Reset your CODECK password", message, "/reset-password"); err != nil {
+			log.Printf("Failed to send password reset for user_id=%d: %v", u.ID, err)
+		}
+	} else {
+		log.Printf("Password reset requested for unknown email: %s", request.Email)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "If an account exists for this email, a reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with a token from a password reset mail. All sessions of the account are logged out
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body responses.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} responses.SuccessResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /password/reset [post]
+func (ac *AccountController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode reset password payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.Token == "" || request.Password == "" {
+		http.Error(w, "Token and password are required", http.StatusBadRequest)
+		return
+	}
+
+	if len(request.Password) > 72 {
+		http.Error(w, "Password cannot be longer than 72 bytes", http.StatusBadRequest)
+		return
+	}
+
+	token, valid := ac.Model.ConsumeUserToken(user.TokenPurposePasswordReset, auth.HashSecret(request.Token))
+	if !valid {
+		log.Println("Invalid or expired password reset token")
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+
+	if !ac.Model.UpdatePassword(token.UserID, request.Password) {
+		log.Printf("Failed to update password for user_id=%d", token.UserID)
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	// Whoever knew the old password should not stay logged in
+	revoked := ac.Sessions.RevokeUserSessions(token.UserID)
+	log.Printf("Password reset for user_id=%d, revoked %d sessions", token.UserID, revoked)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password has been reset",
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Without a token, mail a verification link to the authenticated user's address. With a token from that mail, mark the address as verified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body responses.VerifyEmailRequest false "Verification token"
+// @Success 200 {object} user.User
+// @Success 202 {object} responses.SuccessResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/verify-email [post]
+func (ac *AccountController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Token *string `json:"token,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.Token == nil {
+		if requester.EmailVerifiedAt != nil {
+			http.Error(w, "Email is already verified", http.StatusBadRequest)
+			return
+		}
+
+		message := "Confirm that this address belongs to your CODECK account by opening this link:\n%s"
+		if err := ac.sendToken(requester, user.TokenPurposeEmailVerification, emailVerificationTTL, "Verify your CODECK email", message, "/verify-email"); err != nil {
+			log.Printf("Failed to send email verification for user_id=%d: %v", requester.ID, err)
+			http.Error(w, "Failed to send verification email", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Verification email sent",
+		})
+		return
+	}
+
+	// Scoped to the requester, so presenting someone else's token does not use it up
+	if _, valid := ac.Model.ConsumeUserTokenOf(requester.ID, user.TokenPurposeEmailVerification, auth.HashSecret(*request.Token)); !valid {
+		log.Printf("Invalid or expired email verification token for user_id=%d", requester.ID)
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+
+	verified, ok := ac.Model.MarkEmailVerified(requester.ID)
+	if !ok {
+		log.Printf("Failed to mark email verified for user_id=%d", requester.ID)
+		http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(verified)
+}
+
+// sendToken stores a new token for u and mails it. body must contain a single %s where the link goes.
+func (ac *AccountController) sendToken(u user.User, purpose string, ttl time.Duration, subject, body, path string) error {
+	token, hash, err := auth.NewSecret()
+	if err != nil {
+		return err
+	}
+
+	if !ac.Model.CreateUserToken(user.UserToken{
+		UserID:    u.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	}) {
+		return errors.New("failed to store token")
+	}
+
+	link := ac.AppURL + path + "?token=" + url.QueryEscape(token)
+	return ac.Mailer.Send(mail.Message{
+		To:      u.Email,
+		Subject: subject,
+		Body:    fmt.Sprintf(body, link),
+	})
+}
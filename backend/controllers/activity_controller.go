@@ -2,18 +2,26 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
+	"backend/auth"
+	"backend/judge"
 	"backend/models/activity"
+	"backend/models/group"
+	"backend/models/problem"
 	"backend/models/responses"
 
 	"github.com/gorilla/mux"
 )
 
 type ActivityController struct {
-	Model activity.ActivityModel
+	Model        activity.ActivityModel
+	GroupModel   group.GroupModel
+	ProblemModel problem.ProblemModel
 }
 
 // swagger imports (used in annotations)
@@ -21,8 +29,8 @@ var (
 	_ = responses.ErrorResponse{}
 )
 
-func NewActivityController(model activity.ActivityModel) *ActivityController {
-	return &ActivityController{Model: model}
+func NewActivityController(model activity.ActivityModel, groupModel group.GroupModel, problemModel problem.ProblemModel) *ActivityController {
+	return &ActivityController{Model: model, GroupModel: groupModel, ProblemModel: problemModel}
 }
 
 // GetActivity godoc
@@ -57,15 +65,26 @@ func (ac *ActivityController) GetActivity(w http.ResponseWriter, r *http.Request
 
 // CreateActivity godoc
 // @Summary Create a new activity
-// @Description Create a new activity with title, date, and optional image/description
+// @Description Create a new activity with title, date, and optional image/description, owned by the requester. The solved problem can be described with judge, problem id and URL, rating or difficulty, tags, verdict, language and time spent. A problem URL from a known judge fills in the judge and problem id and is stored in canonical form, with a problem_key shared by all activities on that problem, and linked to the problem catalog through catalog_problem_id. It can be posted right away to groups the requester is a member of
 // @Tags activities
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param activity body responses.ActivityCreateRequest true "Activity creation data"
 // @Success 201 {object} activity.Activity
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
 // @Router /activities [post]
 func (ac *ActivityController) CreateActivity(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var raw map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
 		log.Printf("Failed to decode request payload: %v", err)
@@ -89,7 +108,56 @@ func (ac *ActivityController) CreateActivity(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	createdActivity := ac.Model.CreateActivity(activity)
+	activity.Normalize()
+	if err := identifyProblem(&activity); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := activity.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var links struct {
+		GroupIDs []int `json:"group_ids"`
+	}
+	if err := json.Unmarshal(fixed, &links); err != nil {
+		log.Printf("Failed to decode group_ids: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Check every group before creating anything, so a bad group id does not leave a half-posted activity
+	groupIDs := []int{}
+	seen := map[int]bool{}
+	for _, groupID := range links.GroupIDs {
+		if seen[groupID] {
+			continue
+		}
+		seen[groupID] = true
+		if _, exists := ac.GroupModel.GetGroupByID(groupID); !exists {
+			log.Printf("Group not found: id=%d", groupID)
+			http.Error(w, "Group not found", http.StatusNotFound)
+			return
+		}
+		if !ac.GroupModel.IsUserInGroup(groupID, requester.ID) {
+			log.Printf("Forbidden: requester_id=%d is not a member of group_id=%d", requester.ID, groupID)
+			http.Error(w, "Forbidden: You can only post activities to groups you are a member of", http.StatusForbidden)
+			return
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+
+	activity.CreatorID = requester.ID
+	// Only the importer records the submission an activity came from
+	activity.SubmissionID = nil
+	problem.Link(ac.ProblemModel, &activity)
+	createdActivity, ok := ac.GroupModel.CreatePostedActivity(activity, groupIDs)
+	if !ok {
+		log.Printf("Failed to create activity: creator_id=%d, group_ids=%v", requester.ID, groupIDs)
+		http.Error(w, "Failed to create activity", http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdActivity)
@@ -97,14 +165,17 @@ func (ac *ActivityController) CreateActivity(w http.ResponseWriter, r *http.Requ
 
 // UpdateActivity godoc
 // @Summary Update an existing activity
-// @Description Update activity information (title cannot be updated)
+// @Description Update activity information, including the problem metadata (title cannot be updated, only creator can update)
 // @Tags activities
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Activity ID"
 // @Param activity body responses.ActivityUpdateRequest true "Activity update data"
 // @Success 200 {object} activity.Activity
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /activities/{id} [put]
 func (ac *ActivityController) UpdateActivity(w http.ResponseWriter, r *http.Request) {
@@ -117,6 +188,19 @@ func (ac *ActivityController) UpdateActivity(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	existingActivity, exists := ac.Model.GetActivityByID(activityID)
+	if !exists {
+		log.Printf("Activity not found: id=%d", activityID)
+		http.Error(w, "Activity not found", http.StatusNotFound)
+		return
+	}
+
 	var updates map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
 		log.Printf("Failed to decode request payload: %v", err)
@@ -130,6 +214,59 @@ func (ac *ActivityController) UpdateActivity(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if _, ok := updates["creator_id"]; ok {
+		log.Println("creator_id field cannot be updated")
+		http.Error(w, "creator_id field cannot be updated", http.StatusBadRequest)
+		return
+	}
+
+	if requester.ID != existingActivity.CreatorID {
+		log.Printf("Forbidden: requester_id=%d does not match activity.CreatorID=%d", requester.ID, existingActivity.CreatorID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if dateStr, ok := updates["date"].(string); ok && len(dateStr) == 10 {
+		updates["date"] = dateStr + "T00:00:00Z"
+	}
+
+	// Validate the activity as it will be after the update, then store the normalized metadata
+	merged, err := mergeActivity(existingActivity, updates)
+	if err != nil {
+		log.Printf("Failed to decode request payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	merged.Normalize()
+	if err := identifyProblem(&merged); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := merged.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// These are set by the server
+	delete(updates, "problem_key")
+	delete(updates, "submission_id")
+	delete(updates, "catalog_problem_id")
+	for _, column := range []string{"judge", "problem_id", "problem_url"} {
+		if _, ok := updates[column]; ok {
+			// Identifying the problem may fill in or canonicalise any of these and change its catalog entry.
+			// The problem is described by the user now, even on an imported activity.
+			merged.SubmissionID = nil
+			problem.Link(ac.ProblemModel, &merged)
+			updates["judge"], updates["problem_id"], updates["problem_url"] = nil, nil, nil
+			updates["problem_key"], updates["catalog_problem_id"] = merged.ProblemKey, merged.CatalogProblemID
+			break
+		}
+	}
+	for column, value := range merged.Metadata() {
+		if _, ok := updates[column]; ok {
+			updates[column] = value
+		}
+	}
+
 	updatedActivity, exists := ac.Model.UpdateActivity(activityID, updates)
 	if !exists {
 		log.Printf("Activity not found: id=%d", activityID)
@@ -141,74 +278,304 @@ func (ac *ActivityController) UpdateActivity(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(updatedActivity)
 }
 
+// identifyProblem fills in the judge and problem ID of a recognised problem URL and canonicalises them,
+// so that the problem key matches other activities on the same problem. Unrecognised URLs are kept, and
+// the judge and problem ID given with them are canonicalised instead.
+func identifyProblem(a *activity.Activity) error {
+	a.ProblemKey = nil
+
+	var problem, parsed judge.Problem
+	recognised := false
+	if a.ProblemURL != nil {
+		var err error
+		parsed, err = judge.ParseURL(*a.ProblemURL)
+		recognised = err == nil
+	}
+	if recognised {
+		if a.Judge != nil && *a.Judge != parsed.Judge {
+			return fmt.Errorf("problem_url is a %s problem, not %s", parsed.Judge, *a.Judge)
+		}
+		if a.ProblemID != nil {
+			if given, err := judge.Canonical(parsed.Judge, *a.ProblemID); err != nil || given.ID != parsed.ID {
+				return errors.New("problem_id does not match problem_url")
+			}
+		}
+		problem = parsed
+	} else if a.Judge != nil && a.ProblemID != nil {
+		canonical, err := judge.Canonical(*a.Judge, *a.ProblemID)
+		if errors.Is(err, judge.ErrNoCanonicalForm) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		problem = canonical
+		if a.ProblemURL != nil {
+			problem.URL = *a.ProblemURL
+		}
+	} else {
+		return nil
+	}
+
+	key := problem.Key()
+	a.Judge, a.ProblemID, a.ProblemURL, a.ProblemKey = &problem.Judge, &problem.ID, &problem.URL, &key
+	return nil
+}
+
+// mergeActivity applies JSON updates to a copy of a.
+func mergeActivity(a activity.Activity, updates map[string]interface{}) (activity.Activity, error) {
+	current, err := json.Marshal(a)
+	if err != nil {
+		return activity.Activity{}, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(current, &fields); err != nil {
+		return activity.Activity{}, err
+	}
+	for key, value := range updates {
+		fields[key] = value
+	}
+
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return activity.Activity{}, err
+	}
+	var result activity.Activity
+	err = json.Unmarshal(merged, &result)
+	return result, err
+}
+
 // DeleteActivity godoc
 // @Summary Delete an activity
 // @Description Delete an activity (only creator can delete)
 // @Tags activities
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Activity ID"
-// @Param request body responses.ActivityDeleteRequest true "Delete request with creator_id"
 // @Success 204 "No Content"
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /activities/{id} [delete]
-// DeleteActivity godoc
-// @Summary Delete an activity
-// @Description Delete an activity (only creator can delete)
+func (ac *ActivityController) DeleteActivity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	activityIDStr := vars["id"]
+	activityID, err := strconv.Atoi(activityIDStr)
+	if err != nil {
+		log.Printf("Invalid activity id: %v", err)
+		http.Error(w, "Invalid activity id", http.StatusBadRequest)
+		return
+	}
+	activity, exists := ac.Model.GetActivityByID(activityID)
+	if !exists {
+		log.Printf("Activity not found: id=%d", activityID)
+		http.Error(w, "Activity not found", http.StatusNotFound)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if requester.ID != activity.CreatorID {
+		log.Printf("Forbidden: requester_id=%d does not match activity.CreatorID=%d", requester.ID, activity.CreatorID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if !ac.Model.DeleteActivity(activityID) {
+		log.Printf("Activity not found for delete: id=%d", activityID)
+		http.Error(w, "Activity not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetActivityGroups godoc
+// @Summary Get activity groups
+// @Description List the ids of the groups an activity is posted to
+// @Tags activities
+// @Produce json
+// @Param id path string true "Activity ID"
+// @Success 200 {object} responses.ActivityGroupsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /activities/{id}/groups [get]
+func (ac *ActivityController) GetActivityGroups(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	activityID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid activity id: %v", err)
+		http.Error(w, "Invalid activity id", http.StatusBadRequest)
+		return
+	}
+
+	if _, exists := ac.Model.GetActivityByID(activityID); !exists {
+		log.Printf("Activity not found: id=%d", activityID)
+		http.Error(w, "Activity not found", http.StatusNotFound)
+		return
+	}
+
+	groupIDs := ac.GroupModel.GetActivityGroupIDs(activityID)
+	if groupIDs == nil {
+		groupIDs = []int{}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"activity_id": activityID,
+		"group_ids":   groupIDs,
+	})
+}
+
+// AddActivityToGroup godoc
+// @Summary Post activity to group
+// @Description Post an existing activity to another group (only the activity creator, and only to groups they are a member of)
 // @Tags activities
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Activity ID"
-// @Param request body responses.ActivityDeleteRequest true "Delete request with creator_id"
-// @Success 204 "No Content"
+// @Param request body responses.ActivityGroupRequest true "Group to post to"
+// @Success 201 {object} responses.ActivityGroupResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
-// @Router /activities/{id} [delete]
-func (ac *ActivityController) DeleteActivity(w http.ResponseWriter, r *http.Request) {
+// @Failure 409 {object} responses.ErrorResponse
+// @Router /activities/{id}/groups [post]
+func (ac *ActivityController) AddActivityToGroup(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	activityIDStr := vars["id"]
-	activityID, err := strconv.Atoi(activityIDStr)
+	activityID, err := strconv.Atoi(vars["id"])
 	if err != nil {
 		log.Printf("Invalid activity id: %v", err)
 		http.Error(w, "Invalid activity id", http.StatusBadRequest)
 		return
 	}
-	activity, exists := ac.Model.GetActivityByID(activityID)
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	existingActivity, exists := ac.Model.GetActivityByID(activityID)
 	if !exists {
 		log.Printf("Activity not found: id=%d", activityID)
 		http.Error(w, "Activity not found", http.StatusNotFound)
 		return
 	}
 
-	var request map[string]interface{}
+	var request struct {
+		GroupID int `json:"group_id"`
+	}
+
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("Failed to decode request payload")
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	creatorIDFloat, ok := request["creator_id"].(float64)
-	if !ok {
-		log.Println("Missing creator_id in delete activity request")
-		http.Error(w, "Missing creator_id", http.StatusBadRequest)
+	if request.GroupID == 0 {
+		http.Error(w, "Missing group_id", http.StatusBadRequest)
 		return
 	}
-	creatorID := int(creatorIDFloat)
 
-	if creatorID != activity.CreatorID {
-		log.Printf("Forbidden: creator_id=%d does not match activity.CreatorID=%d", creatorID, activity.CreatorID)
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	if requester.ID != existingActivity.CreatorID {
+		log.Printf("Forbidden: requester_id=%d does not match activity.CreatorID=%d", requester.ID, existingActivity.CreatorID)
+		http.Error(w, "Forbidden: Only the activity creator can post it to groups", http.StatusForbidden)
 		return
 	}
 
-	if !ac.Model.DeleteActivity(activityID) {
-		log.Printf("Activity not found for delete: id=%d", activityID)
+	if _, exists := ac.GroupModel.GetGroupByID(request.GroupID); !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	if !ac.GroupModel.IsUserInGroup(request.GroupID, requester.ID) {
+		log.Printf("Forbidden: requester_id=%d is not a member of group_id=%d", requester.ID, request.GroupID)
+		http.Error(w, "Forbidden: You can only post activities to groups you are a member of", http.StatusForbidden)
+		return
+	}
+
+	for _, groupID := range ac.GroupModel.GetActivityGroupIDs(activityID) {
+		if groupID == request.GroupID {
+			http.Error(w, "Activity is already posted to this group", http.StatusConflict)
+			return
+		}
+	}
+
+	if !ac.GroupModel.AddActivityToGroup(request.GroupID, activityID) {
+		log.Printf("Failed to post activity to group: activity_id=%d, group_id=%d", activityID, request.GroupID)
+		http.Error(w, "Failed to post activity to group", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Activity posted to group successfully",
+		"activity_id": activityID,
+		"group_id":    request.GroupID,
+	})
+}
+
+// RemoveActivityFromGroup godoc
+// @Summary Remove activity from group
+// @Description Take an activity out of a group. The activity itself is kept. Allowed for the activity creator and for group moderators and above
+// @Tags activities
+// @Security BearerAuth
+// @Param id path string true "Activity ID"
+// @Param group_id path string true "Group ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /activities/{id}/groups/{group_id} [delete]
+func (ac *ActivityController) RemoveActivityFromGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	activityID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid activity id: %v", err)
+		http.Error(w, "Invalid activity id", http.StatusBadRequest)
+		return
+	}
+	groupID, err := strconv.Atoi(vars["group_id"])
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	existingActivity, exists := ac.Model.GetActivityByID(activityID)
+	if !exists {
+		log.Printf("Activity not found: id=%d", activityID)
 		http.Error(w, "Activity not found", http.StatusNotFound)
 		return
 	}
 
+	if requester.ID != existingActivity.CreatorID {
+		member, isMember := ac.GroupModel.GetMember(groupID, requester.ID)
+		if !isMember || !member.Can(group.PermissionRemoveActivities) {
+			log.Printf("Forbidden: requester_id=%d is not allowed to remove activity_id=%d from group_id=%d", requester.ID, activityID, groupID)
+			http.Error(w, "Forbidden: Only the activity creator or group moderators can remove it from the group", http.StatusForbidden)
+			return
+		}
+	}
+
+	if !ac.GroupModel.RemoveActivityFromGroup(groupID, activityID) {
+		http.Error(w, "Activity is not posted to this group", http.StatusNotFound)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
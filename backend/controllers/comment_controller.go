@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"backend/auth"
 	"backend/models/activity"
 	"backend/models/comment"
 	"backend/models/group"
@@ -72,14 +73,16 @@ func (cc *CommentController) GetCommentsByActivity(w http.ResponseWriter, r *htt
 
 // CreateComment godoc
 // @Summary Create a new comment
-// @Description Create a new comment on an activity
+// @Description Create a new comment on an activity as the requester
 // @Tags comments
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param activity_id path string true "Activity ID"
 // @Param comment body responses.CommentCreateRequest true "Comment creation data"
 // @Success 201 {object} comment.Comment
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /activities/{activity_id}/comments [post]
 func (cc *CommentController) CreateComment(w http.ResponseWriter, r *http.Request) {
@@ -99,8 +102,13 @@ func (cc *CommentController) CreateComment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		UserID  int    `json:"user_id"`
 		Content string `json:"content"`
 	}
 
@@ -110,15 +118,15 @@ func (cc *CommentController) CreateComment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if request.UserID == 0 || request.Content == "" {
-		log.Println("Missing required fields (user_id, content)")
-		http.Error(w, "Missing required fields (user_id, content)", http.StatusBadRequest)
+	if request.Content == "" {
+		log.Println("Missing required fields (content)")
+		http.Error(w, "Missing required fields (content)", http.StatusBadRequest)
 		return
 	}
 
 	newComment := comment.Comment{
 		ActivityID: activityID,
-		UserID:     request.UserID,
+		UserID:     requester.ID,
 		Content:    request.Content,
 	}
 
@@ -130,14 +138,15 @@ func (cc *CommentController) CreateComment(w http.ResponseWriter, r *http.Reques
 
 // DeleteComment godoc
 // @Summary Delete a comment
-// @Description Delete a comment (only comment author or activity creator can delete)
+// @Description Delete a comment (only comment author, activity creator or a moderator of a group the activity is posted to can delete)
 // @Tags comments
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param comment_id path string true "Comment ID"
-// @Param request body responses.CommentDeleteRequest true "Delete request with requester_id"
 // @Success 200 {object} responses.CommentDeleteResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /comments/{comment_id} [delete]
@@ -163,22 +172,14 @@ func (cc *CommentController) DeleteComment(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var request struct {
-		RequesterID int `json:"requester_id"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
-		return
-	}
-
-	if request.RequesterID == 0 {
-		http.Error(w, "Missing requester_id", http.StatusBadRequest)
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	if request.RequesterID != existingComment.UserID && request.RequesterID != targetActivity.CreatorID {
-		http.Error(w, "Forbidden: Only comment author or activity creator can delete comments", http.StatusForbidden)
+	if requester.ID != existingComment.UserID && requester.ID != targetActivity.CreatorID && !cc.moderates(requester.ID, targetActivity.ID) {
+		http.Error(w, "Forbidden: Only comment author, activity creator or group moderators can delete comments", http.StatusForbidden)
 		return
 	}
 
@@ -194,3 +195,13 @@ func (cc *CommentController) DeleteComment(w http.ResponseWriter, r *http.Reques
 		"comment_id": commentID,
 	})
 }
+
+// moderates reports whether userID may delete comments in a group the activity is posted to.
+func (cc *CommentController) moderates(userID, activityID int) bool {
+	for _, groupID := range cc.GroupModel.GetActivityGroupIDs(activityID) {
+		if member, ok := cc.GroupModel.GetMember(groupID, userID); ok && member.Can(group.PermissionDeleteComments) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,19 +2,30 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
+	"backend/auth"
 	"backend/models/activity"
 	"backend/models/group"
+	"backend/models/reaction"
 	"backend/models/responses"
 
 	"github.com/gorilla/mux"
 )
 
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 50
+)
+
 type GroupController struct {
-	Model group.GroupModel
+	Model         group.GroupModel
+	ReactionModel reaction.ReactionModel
 }
 
 // swagger imports (used in annotations)
@@ -23,8 +34,8 @@ var (
 	_ = responses.ErrorResponse{}
 )
 
-func NewGroupController(model group.GroupModel) *GroupController {
-	return &GroupController{Model: model}
+func NewGroupController(model group.GroupModel, reactionModel reaction.ReactionModel) *GroupController {
+	return &GroupController{Model: model, ReactionModel: reactionModel}
 }
 
 // GetGroup godoc
@@ -33,10 +44,11 @@ func NewGroupController(model group.GroupModel) *GroupController {
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Group ID"
-// @Param requester_id query int true "Requester User ID"
 // @Success 200 {object} group.Group
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id} [get]
@@ -50,11 +62,9 @@ func (gc *GroupController) GetGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	requesterIDStr := r.URL.Query().Get("requester_id")
-	requesterID, err := strconv.Atoi(requesterIDStr)
-	if err != nil {
-		log.Printf("Invalid requester_id: %v", err)
-		http.Error(w, "Invalid requester_id", http.StatusBadRequest)
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -65,8 +75,8 @@ func (gc *GroupController) GetGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !gc.Model.IsUserInGroup(groupID, requesterID) {
-		log.Printf("Forbidden: requester_id=%d is not a member of group_id=%d", requesterID, groupID)
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		log.Printf("Forbidden: requester_id=%d is not a member of group_id=%d", requester.ID, groupID)
 		http.Error(w, "Forbidden: Only group members can view group details", http.StatusForbidden)
 		return
 	}
@@ -77,15 +87,23 @@ func (gc *GroupController) GetGroup(w http.ResponseWriter, r *http.Request) {
 
 // CreateGroup godoc
 // @Summary Create a new group
-// @Description Create a new group with name, end date, and optional image/description
+// @Description Create a new group with name, end date, and optional image/description. The requester becomes its creator and first member.
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param group body responses.GroupCreateRequest true "Group creation data"
 // @Success 201 {object} group.Group
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Router /groups [post]
 func (gc *GroupController) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var raw map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
 		log.Printf("Failed to decode request payload: %v", err)
@@ -96,20 +114,40 @@ func (gc *GroupController) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		raw["end_date"] = dateStr + "T00:00:00Z"
 	}
 	fixed, _ := json.Marshal(raw)
-	var group group.Group
-	if err := json.Unmarshal(fixed, &group); err != nil {
+	var newGroup group.Group
+	if err := json.Unmarshal(fixed, &newGroup); err != nil {
 		log.Printf("Failed to decode request payload: %v", err)
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	if group.Name == "" || group.EndDate.IsZero() {
+	if newGroup.Name == "" || newGroup.EndDate.IsZero() {
 		log.Println("Missing required fields in group creation")
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
 	}
 
-	createdGroup := gc.Model.CreateGroup(group)
+	if newGroup.ScoringMode == "" {
+		newGroup.ScoringMode = group.ScoringActivities
+	}
+	if !group.ValidScoringMode(newGroup.ScoringMode) {
+		http.Error(w, "Invalid scoring_mode", http.StatusBadRequest)
+		return
+	}
+
+	if goal, invalid := newGroup.InvalidGoal(); invalid {
+		http.Error(w, "Invalid goal_"+goal, http.StatusBadRequest)
+		return
+	}
+
+	newGroup.CreatorID = requester.ID
+	createdGroup := gc.Model.CreateGroup(newGroup)
+
+	if !gc.Model.AddUserToGroup(createdGroup.ID, requester.ID) {
+		log.Printf("Failed to add creator to new group: group_id=%d, user_id=%d", createdGroup.ID, requester.ID)
+		http.Error(w, "Failed to create group", http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdGroup)
@@ -117,14 +155,17 @@ func (gc *GroupController) CreateGroup(w http.ResponseWriter, r *http.Request) {
 
 // UpdateGroup godoc
 // @Summary Update an existing group
-// @Description Update group information (name cannot be updated)
+// @Description Update group information (name cannot be updated, only the owner can update)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param group body responses.GroupUpdateRequest true "Group update data"
 // @Success 200 {object} group.Group
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id} [put]
 func (gc *GroupController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
@@ -137,6 +178,19 @@ func (gc *GroupController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		log.Printf("Group not found: id=%d", groupID)
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
 	var updates map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
 		log.Printf("Failed to decode request payload: %v", err)
@@ -150,6 +204,38 @@ func (gc *GroupController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := updates["creator_id"]; ok {
+		log.Println("creator_id field cannot be updated")
+		http.Error(w, "creator_id field cannot be updated", http.StatusBadRequest)
+		return
+	}
+
+	if mode, ok := updates["scoring_mode"]; ok {
+		if modeStr, isString := mode.(string); !isString || !group.ValidScoringMode(modeStr) {
+			http.Error(w, "Invalid scoring_mode", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// A goal set to null is removed
+	for _, goal := range []string{group.GoalTotalActivities, group.GoalActivitiesPerMember, group.GoalActiveDaysPerWeek} {
+		value, ok := updates["goal_"+goal]
+		if !ok || value == nil {
+			continue
+		}
+		target, isNumber := value.(float64)
+		if !isNumber || target != float64(int(target)) || !group.ValidGoalTarget(goal, int(target)) {
+			http.Error(w, "Invalid goal_"+goal, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if !gc.memberCan(groupID, requester.ID, group.PermissionUpdateGroup) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to update group_id=%d", requester.ID, groupID)
+		http.Error(w, "Forbidden: Only the group owner can update the group", http.StatusForbidden)
+		return
+	}
+
 	updatedGroup, exists := gc.Model.UpdateGroup(groupID, updates)
 	if !exists {
 		log.Printf("Group not found: id=%d", groupID)
@@ -163,14 +249,15 @@ func (gc *GroupController) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 
 // DeleteGroup godoc
 // @Summary Delete a group
-// @Description Delete a group (only creator can delete)
+// @Description Delete a group (only the owner can delete)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
-// @Param request body responses.GroupDeleteRequest true "Delete request with creator_id"
 // @Success 204 "No Content"
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id} [delete]
@@ -183,29 +270,21 @@ func (gc *GroupController) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid group id", http.StatusBadRequest)
 		return
 	}
-	group, exists := gc.Model.GetGroupByID(groupID)
+	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		log.Printf("Group not found: id=%d", groupID)
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
-	var request map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("Failed to decode request payload")
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
-		return
-	}
-
-	creatorID, ok := request["creator_id"].(float64)
+	requester, ok := auth.CurrentUser(r)
 	if !ok {
-		log.Println("Missing creator_id in delete group request")
-		http.Error(w, "Missing creator_id", http.StatusBadRequest)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	if int(creatorID) != group.CreatorID {
-		log.Printf("Forbidden: creator_id=%d does not match group.CreatorID=%d", int(creatorID), group.CreatorID)
+	if !gc.memberCan(groupID, requester.ID, group.PermissionDeleteGroup) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to delete group_id=%d", requester.ID, groupID)
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
@@ -221,14 +300,17 @@ func (gc *GroupController) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 
 // AddUserToGroup godoc
 // @Summary Add user to group
-// @Description Add a user to a group
+// @Description Add a user to a group (only the owner and admins can add members directly)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body responses.AddUserToGroupRequest true "Add user request"
 // @Success 201 {object} responses.AddUserToGroupResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
 // @Router /groups/{id}/members [post]
@@ -247,6 +329,12 @@ func (gc *GroupController) AddUserToGroup(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
 		UserID int `json:"user_id"`
 	}
@@ -262,6 +350,12 @@ func (gc *GroupController) AddUserToGroup(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !gc.memberCan(groupID, requester.ID, group.PermissionManageMembers) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to add members to group_id=%d", requester.ID, groupID)
+		http.Error(w, "Forbidden: Only group owner or admins can add members", http.StatusForbidden)
+		return
+	}
+
 	if gc.Model.IsUserInGroup(groupID, request.UserID) {
 		log.Printf("User is already a member of group: group_id=%d, user_id=%d", groupID, request.UserID)
 		http.Error(w, "User is already a member of this group", http.StatusConflict)
@@ -287,14 +381,16 @@ func (gc *GroupController) AddUserToGroup(w http.ResponseWriter, r *http.Request
 
 // RemoveUserFromGroup godoc
 // @Summary Remove user from group
-// @Description Remove a user from a group (only group creator or the user themselves can remove)
+// @Description Remove a user from a group (the user themselves, or the owner or an admin who outranks them). The owner cannot be removed
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body responses.RemoveUserFromGroupRequest true "Remove user request"
 // @Success 200 {object} responses.SuccessResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/members [delete]
@@ -307,15 +403,20 @@ func (gc *GroupController) RemoveUserFromGroup(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	group, exists := gc.Model.GetGroupByID(groupID)
+	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		UserID      int `json:"user_id"`
-		RequesterID int `json:"requester_id"`
+		UserID int `json:"user_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -323,24 +424,38 @@ func (gc *GroupController) RemoveUserFromGroup(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if request.UserID == 0 || request.RequesterID == 0 {
-		log.Println("Missing user_id or requester_id in remove user from group request")
-		http.Error(w, "Missing user_id or requester_id", http.StatusBadRequest)
+	if request.UserID == 0 {
+		log.Println("Missing user_id in remove user from group request")
+		http.Error(w, "Missing user_id", http.StatusBadRequest)
 		return
 	}
 
-	if request.RequesterID != group.CreatorID && request.RequesterID != request.UserID {
-		log.Printf("Forbidden: requester_id=%d is not allowed to remove user_id=%d from group_id=%d", request.RequesterID, request.UserID, groupID)
-		http.Error(w, "Forbidden: Only group creator or the user themselves can remove membership", http.StatusForbidden)
+	requesterMember, _ := gc.Model.GetMember(groupID, requester.ID)
+	if requester.ID != request.UserID && !requesterMember.Can(group.PermissionManageMembers) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to remove user_id=%d from group_id=%d", requester.ID, request.UserID, groupID)
+		http.Error(w, "Forbidden: Only group owner, admins or the user themselves can remove membership", http.StatusForbidden)
 		return
 	}
 
-	if !gc.Model.IsUserInGroup(groupID, request.UserID) {
+	target, isMember := gc.Model.GetMember(groupID, request.UserID)
+	if !isMember {
 		log.Printf("User is not a member of group: group_id=%d, user_id=%d", groupID, request.UserID)
 		http.Error(w, "User is not a member of this group", http.StatusNotFound)
 		return
 	}
 
+	if target.Role == group.RoleOwner {
+		log.Printf("Forbidden: owner user_id=%d cannot leave group_id=%d", request.UserID, groupID)
+		http.Error(w, "Forbidden: The group owner cannot be removed, transfer ownership first", http.StatusForbidden)
+		return
+	}
+
+	if requester.ID != request.UserID && !group.Outranks(requesterMember.Role, target.Role) {
+		log.Printf("Forbidden: requester_id=%d (%s) does not outrank user_id=%d (%s) in group_id=%d", requester.ID, requesterMember.Role, request.UserID, target.Role, groupID)
+		http.Error(w, "Forbidden: You can only remove members below your role", http.StatusForbidden)
+		return
+	}
+
 	success := gc.Model.RemoveUserFromGroup(groupID, request.UserID)
 	if !success {
 		log.Printf("Failed to remove user from group: group_id=%d, user_id=%d", groupID, request.UserID)
@@ -362,10 +477,11 @@ func (gc *GroupController) RemoveUserFromGroup(w http.ResponseWriter, r *http.Re
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Group ID"
-// @Param requester_id query int true "Requester User ID"
 // @Success 200 {object} responses.GroupMembersResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/members [get]
@@ -378,10 +494,9 @@ func (gc *GroupController) GetGroupMembers(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	requesterIDStr := r.URL.Query().Get("requester_id")
-	requesterID, err := strconv.Atoi(requesterIDStr)
-	if err != nil {
-		http.Error(w, "Invalid requester_id", http.StatusBadRequest)
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -391,7 +506,7 @@ func (gc *GroupController) GetGroupMembers(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if !gc.Model.IsUserInGroup(groupID, requesterID) {
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
 		http.Error(w, "Forbidden: Only group members can view group members", http.StatusForbidden)
 		return
 	}
@@ -412,13 +527,16 @@ func (gc *GroupController) GetGroupMembers(w http.ResponseWriter, r *http.Reques
 
 // CreateInviteLink godoc
 // @Summary Create group invite link
-// @Description Create an invite link for the group (only group creator can create invites)
+// @Description Create an invite link for the group (only the owner and admins can create invites)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
-// @Param request body responses.CreateInviteRequest true "Create invite request"
+// @Param request body responses.CreateInviteRequest false "Create invite request"
 // @Success 201 {object} group.GroupInvite
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/invites [post]
@@ -431,37 +549,36 @@ func (gc *GroupController) CreateInviteLink(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	group, exists := gc.Model.GetGroupByID(groupID)
+	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		CreatorID int     `json:"creator_id"`
 		ExpiresAt *string `json:"expires_at,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	if request.CreatorID == 0 {
-		log.Println("Missing creator_id in create invite link request")
-		http.Error(w, "Missing creator_id", http.StatusBadRequest)
+	if !gc.memberCan(groupID, requester.ID, group.PermissionManageInvites) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to create invites for group_id=%d", requester.ID, groupID)
+		http.Error(w, "Forbidden: Only group owner or admins can create invite links", http.StatusForbidden)
 		return
 	}
 
-	if request.CreatorID != group.CreatorID {
-		log.Printf("Forbidden: creator_id=%d is not group creator (group.CreatorID=%d)", request.CreatorID, group.CreatorID)
-		http.Error(w, "Forbidden: Only group creator can create invite links", http.StatusForbidden)
-		return
-	}
-
-	invite, success := gc.Model.CreateInviteLink(groupID, request.CreatorID, request.ExpiresAt)
+	invite, success := gc.Model.CreateInviteLink(groupID, requester.ID, request.ExpiresAt)
 	if !success {
-		log.Printf("Failed to create invite link for group_id=%d by creator_id=%d", groupID, request.CreatorID)
+		log.Printf("Failed to create invite link for group_id=%d by user_id=%d", groupID, requester.ID)
 		http.Error(w, "Failed to create invite link", http.StatusInternalServerError)
 		return
 	}
@@ -472,13 +589,17 @@ func (gc *GroupController) CreateInviteLink(w http.ResponseWriter, r *http.Reque
 
 // JoinGroupByInvite godoc
 // @Summary Join group by invite code
-// @Description Join a group using an invite code
+// @Description Join a group as the authenticated user using an invite code
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param invite_code path string true "Invite Code"
-// @Param request body responses.JoinGroupRequest true "Join group request"
+// @Param request body responses.JoinGroupRequest false "Join group request"
 // @Success 200 {object} responses.SuccessResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
 // @Router /invites/{invite_code}/join [post]
@@ -486,22 +607,21 @@ func (gc *GroupController) JoinGroupByInvite(w http.ResponseWriter, r *http.Requ
 	vars := mux.Vars(r)
 	inviteCode := vars["invite_code"]
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		UserID   int     `json:"user_id"`
 		Nickname *string `json:"nickname,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	if request.UserID == 0 {
-		log.Println("Missing user_id in join group by invite request")
-		http.Error(w, "Missing user_id", http.StatusBadRequest)
-		return
-	}
-
 	invite, exists := gc.Model.GetInviteByCode(inviteCode)
 	if !exists || !invite.IsActive {
 		log.Printf("Invalid or expired invite code: %s", inviteCode)
@@ -509,21 +629,27 @@ func (gc *GroupController) JoinGroupByInvite(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	_, exists = gc.Model.GetGroupByID(invite.GroupID)
+	group, exists := gc.Model.GetGroupByID(invite.GroupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
-	if gc.Model.IsUserInGroup(invite.GroupID, request.UserID) {
-		log.Printf("User is already a member of group: group_id=%d, user_id=%d", invite.GroupID, request.UserID)
+	if group.RequireVerifiedEmail && requester.EmailVerifiedAt == nil {
+		log.Printf("Forbidden: user_id=%d has no verified email, required by group_id=%d", requester.ID, group.ID)
+		http.Error(w, "Forbidden: This group requires a verified email address", http.StatusForbidden)
+		return
+	}
+
+	if gc.Model.IsUserInGroup(invite.GroupID, requester.ID) {
+		log.Printf("User is already a member of group: group_id=%d, user_id=%d", invite.GroupID, requester.ID)
 		http.Error(w, "User is already a member of this group", http.StatusConflict)
 		return
 	}
 
-	success := gc.Model.AddUserToGroup(invite.GroupID, request.UserID)
+	success := gc.Model.AddUserToGroup(invite.GroupID, requester.ID)
 	if !success {
-		log.Printf("Failed to join group: group_id=%d, user_id=%d", invite.GroupID, request.UserID)
+		log.Printf("Failed to join group: group_id=%d, user_id=%d", invite.GroupID, requester.ID)
 		http.Error(w, "Failed to join group", http.StatusInternalServerError)
 		return
 	}
@@ -531,13 +657,13 @@ func (gc *GroupController) JoinGroupByInvite(w http.ResponseWriter, r *http.Requ
 	// If nickname is provided, set it for the user
 	if request.Nickname != nil {
 		if len(*request.Nickname) > 50 {
-			log.Printf("Nickname too long: user_id=%d, group_id=%d", request.UserID, invite.GroupID)
+			log.Printf("Nickname too long: user_id=%d, group_id=%d", requester.ID, invite.GroupID)
 			http.Error(w, "Nickname cannot be longer than 50 characters", http.StatusBadRequest)
 			return
 		}
-		nickSuccess := gc.Model.SetUserNickname(invite.GroupID, request.UserID, request.Nickname)
+		nickSuccess := gc.Model.SetUserNickname(invite.GroupID, requester.ID, request.Nickname)
 		if !nickSuccess {
-			log.Printf("Failed to set nickname: group_id=%d, user_id=%d", invite.GroupID, request.UserID)
+			log.Printf("Failed to set nickname: group_id=%d, user_id=%d", invite.GroupID, requester.ID)
 			http.Error(w, "Failed to set nickname", http.StatusInternalServerError)
 			return
 		}
@@ -547,7 +673,7 @@ func (gc *GroupController) JoinGroupByInvite(w http.ResponseWriter, r *http.Requ
 	response := map[string]interface{}{
 		"message":     "Successfully joined group",
 		"group_id":    invite.GroupID,
-		"user_id":     request.UserID,
+		"user_id":     requester.ID,
 		"nickname":    request.Nickname,
 		"invite_code": inviteCode,
 	}
@@ -557,12 +683,15 @@ func (gc *GroupController) JoinGroupByInvite(w http.ResponseWriter, r *http.Requ
 
 // GetGroupInvites godoc
 // @Summary Get group invites
-// @Description Get all invites for a group
+// @Description Get all active invites for a group (members only)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Success 200 {array} group.GroupInvite
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/invites [get]
 func (gc *GroupController) GetGroupInvites(w http.ResponseWriter, r *http.Request) {
@@ -574,12 +703,23 @@ func (gc *GroupController) GetGroupInvites(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "Forbidden: Only group members can view group invites", http.StatusForbidden)
+		return
+	}
+
 	invites := gc.Model.GetActiveInvites(groupID)
 
 	w.WriteHeader(http.StatusOK)
@@ -592,13 +732,14 @@ func (gc *GroupController) GetGroupInvites(w http.ResponseWriter, r *http.Reques
 
 // DeactivateInvite godoc
 // @Summary Deactivate group invite
-// @Description Deactivate an invite link (only group creator can deactivate)
+// @Description Deactivate an invite link (only its creator, the group owner or admins can deactivate)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param invite_code path string true "Invite Code"
-// @Param request body responses.DeactivateInviteRequest true "Deactivate invite request"
 // @Success 200 {object} responses.SuccessResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /invites/{invite_code}/deactivate [delete]
@@ -606,18 +747,9 @@ func (gc *GroupController) DeactivateInvite(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	inviteCode := vars["invite_code"]
 
-	var request struct {
-		RequesterID int `json:"requester_id"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
-		return
-	}
-
-	if request.RequesterID == 0 {
-		log.Println("Missing requester_id in deactivate invite request")
-		http.Error(w, "Missing requester_id", http.StatusBadRequest)
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -628,15 +760,15 @@ func (gc *GroupController) DeactivateInvite(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	group, exists := gc.Model.GetGroupByID(invite.GroupID)
+	_, exists = gc.Model.GetGroupByID(invite.GroupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
-	if request.RequesterID != group.CreatorID && request.RequesterID != invite.CreatedBy {
-		log.Printf("Forbidden: requester_id=%d is not allowed to deactivate invite_code=%s", request.RequesterID, inviteCode)
-		http.Error(w, "Forbidden: Only group creator or invite creator can deactivate invite", http.StatusForbidden)
+	if requester.ID != invite.CreatedBy && !gc.memberCan(invite.GroupID, requester.ID, group.PermissionManageInvites) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to deactivate invite_code=%s", requester.ID, inviteCode)
+		http.Error(w, "Forbidden: Only group owner, admins or invite creator can deactivate invite", http.StatusForbidden)
 		return
 	}
 
@@ -656,14 +788,16 @@ func (gc *GroupController) DeactivateInvite(w http.ResponseWriter, r *http.Reque
 
 // SetUserNickname godoc
 // @Summary Set user nickname in group
-// @Description Set or update a user's nickname in a group
+// @Description Set or update a user's nickname in a group (user_id defaults to the requester)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body responses.SetNicknameRequest true "Set nickname request"
 // @Success 200 {object} responses.SuccessResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/members/nickname [put]
@@ -676,16 +810,21 @@ func (gc *GroupController) SetUserNickname(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	group, exists := gc.Model.GetGroupByID(groupID)
+	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		UserID      int     `json:"user_id"`
-		RequesterID int     `json:"requester_id"`
-		Nickname    *string `json:"nickname"`
+		UserID   int     `json:"user_id"`
+		Nickname *string `json:"nickname"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -693,15 +832,13 @@ func (gc *GroupController) SetUserNickname(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if request.UserID == 0 || request.RequesterID == 0 {
-		log.Println("Missing user_id or requester_id in set nickname request")
-		http.Error(w, "Missing user_id or requester_id", http.StatusBadRequest)
-		return
+	if request.UserID == 0 {
+		request.UserID = requester.ID
 	}
 
-	if request.RequesterID != request.UserID && request.RequesterID != group.CreatorID {
-		log.Printf("Forbidden: requester_id=%d is not allowed to set nickname for user_id=%d in group_id=%d", request.RequesterID, request.UserID, groupID)
-		http.Error(w, "Forbidden: Only the user themselves or group creator can set nickname", http.StatusForbidden)
+	if requester.ID != request.UserID && !gc.memberCan(groupID, requester.ID, group.PermissionManageMembers) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to set nickname for user_id=%d in group_id=%d", requester.ID, request.UserID, groupID)
+		http.Error(w, "Forbidden: Only the user themselves, group owner or admins can set nickname", http.StatusForbidden)
 		return
 	}
 
@@ -740,13 +877,16 @@ func (gc *GroupController) SetUserNickname(w http.ResponseWriter, r *http.Reques
 
 // DeleteUserNickname godoc
 // @Summary Delete user nickname in group
-// @Description Remove a user's nickname in a group
+// @Description Remove a user's nickname in a group (user_id defaults to the requester)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Group ID"
-// @Param request body responses.DeleteNicknameRequest true "Delete nickname request"
+// @Param request body responses.DeleteNicknameRequest false "Delete nickname request"
 // @Success 200 {object} responses.SuccessResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/members/nickname [delete]
@@ -759,31 +899,34 @@ func (gc *GroupController) DeleteUserNickname(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	group, exists := gc.Model.GetGroupByID(groupID)
+	_, exists := gc.Model.GetGroupByID(groupID)
 	if !exists {
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
 
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var request struct {
-		UserID      int `json:"user_id"`
-		RequesterID int `json:"requester_id"`
+		UserID int `json:"user_id"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	if request.UserID == 0 || request.RequesterID == 0 {
-		log.Println("Missing user_id or requester_id in delete nickname request")
-		http.Error(w, "Missing user_id or requester_id", http.StatusBadRequest)
-		return
+	if request.UserID == 0 {
+		request.UserID = requester.ID
 	}
 
-	if request.RequesterID != request.UserID && request.RequesterID != group.CreatorID {
-		log.Printf("Forbidden: requester_id=%d is not allowed to delete nickname for user_id=%d in group_id=%d", request.RequesterID, request.UserID, groupID)
-		http.Error(w, "Forbidden: Only the user themselves or group creator can delete nickname", http.StatusForbidden)
+	if requester.ID != request.UserID && !gc.memberCan(groupID, requester.ID, group.PermissionManageMembers) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to delete nickname for user_id=%d in group_id=%d", requester.ID, request.UserID, groupID)
+		http.Error(w, "Forbidden: Only the user themselves, group owner or admins can delete nickname", http.StatusForbidden)
 		return
 	}
 
@@ -809,16 +952,364 @@ func (gc *GroupController) DeleteUserNickname(w http.ResponseWriter, r *http.Req
 	})
 }
 
+// SetMemberRole godoc
+// @Summary Set member role
+// @Description Promote or demote a group member. The owner can appoint admins, moderators and members; admins can only move members below them between moderator and member. The owner role cannot be assigned
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body responses.SetMemberRoleRequest true "Set role request"
+// @Success 200 {object} responses.SetMemberRoleResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/members/role [put]
+func (gc *GroupController) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	_, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		UserID int    `json:"user_id"`
+		Role   string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.UserID == 0 || request.Role == "" {
+		log.Println("Missing user_id or role in set member role request")
+		http.Error(w, "Missing user_id or role", http.StatusBadRequest)
+		return
+	}
+
+	if !group.ValidRole(request.Role) || request.Role == group.RoleOwner {
+		log.Printf("Invalid role in set member role request: %s", request.Role)
+		http.Error(w, "Role must be admin, moderator or member", http.StatusBadRequest)
+		return
+	}
+
+	requesterMember, _ := gc.Model.GetMember(groupID, requester.ID)
+	if !requesterMember.Can(group.PermissionManageRoles) {
+		log.Printf("Forbidden: requester_id=%d is not allowed to manage roles in group_id=%d", requester.ID, groupID)
+		http.Error(w, "Forbidden: Only group owner or admins can change roles", http.StatusForbidden)
+		return
+	}
+
+	target, isMember := gc.Model.GetMember(groupID, request.UserID)
+	if !isMember {
+		log.Printf("User is not a member of group: group_id=%d, user_id=%d", groupID, request.UserID)
+		http.Error(w, "User is not a member of this group", http.StatusNotFound)
+		return
+	}
+
+	// Nobody can touch the owner, and nobody can grant a role as high as their own
+	if !group.Outranks(requesterMember.Role, target.Role) || !group.Outranks(requesterMember.Role, request.Role) {
+		log.Printf("Forbidden: requester_id=%d (%s) cannot change user_id=%d from %s to %s in group_id=%d", requester.ID, requesterMember.Role, request.UserID, target.Role, request.Role, groupID)
+		http.Error(w, "Forbidden: You can only assign roles below your own to members below you", http.StatusForbidden)
+		return
+	}
+
+	if !gc.Model.SetMemberRole(groupID, request.UserID, request.Role) {
+		log.Printf("Failed to set role: group_id=%d, user_id=%d", groupID, request.UserID)
+		http.Error(w, "Failed to set role", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Role updated successfully",
+		"group_id": groupID,
+		"user_id":  request.UserID,
+		"role":     request.Role,
+	})
+}
+
+// TransferOwnership godoc
+// @Summary Nominate a new owner
+// @Description Offer ownership of the group to another member (owner only). The member becomes owner once they accept; a newer nomination replaces a pending one
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body responses.TransferOwnershipRequest true "Nominee"
+// @Success 201 {object} group.OwnershipTransfer
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/transfer [post]
+func (gc *GroupController) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	_, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		UserID int `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.UserID == 0 {
+		log.Println("Missing user_id in transfer ownership request")
+		http.Error(w, "Missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	requesterMember, _ := gc.Model.GetMember(groupID, requester.ID)
+	if requesterMember.Role != group.RoleOwner {
+		log.Printf("Forbidden: requester_id=%d is not the owner of group_id=%d", requester.ID, groupID)
+		http.Error(w, "Forbidden: Only the group owner can transfer ownership", http.StatusForbidden)
+		return
+	}
+
+	if request.UserID == requester.ID {
+		http.Error(w, "You already own this group", http.StatusBadRequest)
+		return
+	}
+
+	if !gc.Model.IsUserInGroup(groupID, request.UserID) {
+		log.Printf("User is not a member of group: group_id=%d, user_id=%d", groupID, request.UserID)
+		http.Error(w, "User is not a member of this group", http.StatusNotFound)
+		return
+	}
+
+	transfer, success := gc.Model.CreateOwnershipTransfer(groupID, requester.ID, request.UserID)
+	if !success {
+		log.Printf("Failed to create ownership transfer: group_id=%d, to_user_id=%d", groupID, request.UserID)
+		http.Error(w, "Failed to transfer ownership", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Ownership transfer %d: group_id=%d offered by user_id=%d to user_id=%d", transfer.ID, groupID, requester.ID, request.UserID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// AcceptOwnershipTransfer godoc
+// @Summary Accept group ownership
+// @Description Accept a pending ownership nomination. The nominee becomes the owner and the previous owner stays on as an admin
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} group.Group
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Router /groups/{id}/transfer/accept [post]
+func (gc *GroupController) AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	transfer, exists := gc.Model.GetPendingOwnershipTransfer(groupID)
+	if !exists {
+		http.Error(w, "No pending ownership transfer", http.StatusNotFound)
+		return
+	}
+
+	if requester.ID != transfer.ToUserID {
+		log.Printf("Forbidden: requester_id=%d is not the nominee of transfer %d", requester.ID, transfer.ID)
+		http.Error(w, "Forbidden: Only the nominated member can accept ownership", http.StatusForbidden)
+		return
+	}
+
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "You are no longer a member of this group", http.StatusConflict)
+		return
+	}
+
+	if !gc.Model.AcceptOwnershipTransfer(transfer.ID) {
+		log.Printf("Failed to accept ownership transfer %d", transfer.ID)
+		http.Error(w, "Ownership transfer is no longer pending", http.StatusConflict)
+		return
+	}
+	log.Printf("Ownership transfer %d accepted: group_id=%d now owned by user_id=%d", transfer.ID, groupID, requester.ID)
+
+	updatedGroup, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updatedGroup)
+}
+
+// CancelOwnershipTransfer godoc
+// @Summary Cancel or decline group ownership transfer
+// @Description The owner cancels their pending nomination, or the nominee declines it
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} group.OwnershipTransfer
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/transfer [delete]
+func (gc *GroupController) CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	transfer, exists := gc.Model.GetPendingOwnershipTransfer(groupID)
+	if !exists {
+		http.Error(w, "No pending ownership transfer", http.StatusNotFound)
+		return
+	}
+
+	var status string
+	switch requester.ID {
+	case transfer.FromUserID:
+		status = group.TransferCancelled
+	case transfer.ToUserID:
+		status = group.TransferDeclined
+	default:
+		log.Printf("Forbidden: requester_id=%d is not part of transfer %d", requester.ID, transfer.ID)
+		http.Error(w, "Forbidden: Only the owner or the nominee can cancel the transfer", http.StatusForbidden)
+		return
+	}
+
+	if !gc.Model.CloseOwnershipTransfer(transfer.ID, status) {
+		http.Error(w, "No pending ownership transfer", http.StatusNotFound)
+		return
+	}
+	log.Printf("Ownership transfer %d %s by user_id=%d", transfer.ID, status, requester.ID)
+
+	transfer.Status = status
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// GetOwnershipTransfers godoc
+// @Summary Get group ownership history
+// @Description List all ownership transfers of a group, newest first (members only)
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} responses.OwnershipTransfersResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/transfers [get]
+func (gc *GroupController) GetOwnershipTransfers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "Forbidden: Only group members can view ownership transfers", http.StatusForbidden)
+		return
+	}
+
+	transfers := gc.Model.GetOwnershipTransfers(groupID)
+	if transfers == nil {
+		transfers = []group.OwnershipTransfer{}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group_id":       groupID,
+		"transfers":      transfers,
+		"transfer_count": len(transfers),
+	})
+}
+
 // GetGroupActivities godoc
 // @Summary Get group activities
-// @Description Get all activities for a group (members only)
+// @Description Get the activities posted to a group and dated between its start and end dates, newest first, with author and comment count (members only)
 // @Tags groups
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Group ID"
-// @Param requester_id query int true "Requester User ID"
-// @Success 200 {array} activity.Activity
+// @Success 200 {object} responses.GroupActivitiesResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
 // @Failure 403 {object} responses.ErrorResponse
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /groups/{id}/activities [get]
@@ -831,10 +1322,9 @@ func (gc *GroupController) GetGroupActivities(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	requesterIDStr := r.URL.Query().Get("requester_id")
-	requesterID, err := strconv.Atoi(requesterIDStr)
-	if err != nil {
-		http.Error(w, "Invalid requester_id", http.StatusBadRequest)
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -844,7 +1334,7 @@ func (gc *GroupController) GetGroupActivities(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if !gc.Model.IsUserInGroup(groupID, requesterID) {
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
 		http.Error(w, "Forbidden: Only group members can view group activities", http.StatusForbidden)
 		return
 	}
@@ -854,6 +1344,9 @@ func (gc *GroupController) GetGroupActivities(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Group not found", http.StatusNotFound)
 		return
 	}
+	if activities == nil {
+		activities = []group.PostedActivity{}
+	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -862,3 +1355,201 @@ func (gc *GroupController) GetGroupActivities(w http.ResponseWriter, r *http.Req
 		"activity_count": len(activities),
 	})
 }
+
+// GetLeaderboard godoc
+// @Summary Get group leaderboard
+// @Description Rank the current members by their activities posted to the group between its start and end dates that solved their problem (accepted or without a verdict), using the group's scoring mode unless another is given. Members show up under their group nickname when set; equal scores share a rank (members only)
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Group ID"
+// @Param mode query string false "Scoring mode: activities, active_days or difficulty"
+// @Success 200 {object} responses.LeaderboardResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/leaderboard [get]
+func (gc *GroupController) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	g, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "Forbidden: Only group members can view the leaderboard", http.StatusForbidden)
+		return
+	}
+
+	mode := g.ScoringMode
+	if m := r.URL.Query().Get("mode"); m != "" {
+		mode = m
+	}
+	if !group.ValidScoringMode(mode) {
+		http.Error(w, "Invalid scoring mode", http.StatusBadRequest)
+		return
+	}
+
+	activities, exists := gc.Model.GetGroupSolves(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	entries := group.BuildLeaderboard(gc.Model.GetMemberProfiles(groupID), activities, mode)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group_id":     groupID,
+		"scoring_mode": mode,
+		"start_date":   g.StartDate,
+		"end_date":     g.EndDate,
+		"entries":      entries,
+	})
+}
+
+// GetGroupProgress godoc
+// @Summary Get group goal progress
+// @Description Get the group's progress towards its goals, counting the posted activities that solved their problem (accepted or without a verdict): overall and per-member completion, how much of the date window has passed by the requester's time zone and, for each goal, when it will be reached at the current pace
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} responses.GroupProgressResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/progress [get]
+func (gc *GroupController) GetGroupProgress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupIDStr := vars["id"]
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	g, exists := gc.Model.GetGroupByID(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	if !gc.Model.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "Forbidden: Only group members can view the group's progress", http.StatusForbidden)
+		return
+	}
+
+	activities, exists := gc.Model.GetGroupSolves(groupID)
+	if !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	// The window passes by the requester's days, like their streak
+	today := activity.Today(time.Now(), requester.Location())
+	progress := group.BuildProgress(g, gc.Model.GetMemberProfiles(groupID), activities, today)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group_id":   groupID,
+		"start_date": g.StartDate,
+		"end_date":   g.EndDate,
+		"today":      today,
+		"progress":   progress,
+	})
+}
+
+// GetFeed godoc
+// @Summary Get home feed
+// @Description Get the activities posted to any of the requester's groups, newest first, with their author, groups, comment count and reactions. Pass next_cursor back as cursor to get the following page
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, 20 by default and at most 50"
+// @Success 200 {object} responses.FeedResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /users/me/feed [get]
+func (gc *GroupController) GetFeed(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultFeedLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxFeedLimit {
+			http.Error(w, "limit must be between 1 and 50", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	var after *group.FeedCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		cursor, valid := group.ParseFeedCursor(value)
+		if !valid {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		after = &cursor
+	}
+
+	// One extra item tells whether there is a next page
+	items := gc.Model.GetFeed(requester.ID, after, limit+1)
+	var nextCursor *string
+	if len(items) > limit {
+		items = items[:limit]
+		cursor := items[limit-1].Cursor().Encode()
+		nextCursor = &cursor
+	}
+
+	activityIDs := make([]int, len(items))
+	for i, item := range items {
+		activityIDs[i] = item.ID
+	}
+	summaries := gc.ReactionModel.GetSummaries(activityIDs, requester.ID)
+	for i := range items {
+		if s, ok := summaries[items[i].ID]; ok {
+			items[i].Reactions = s
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items":       items,
+		"next_cursor": nextCursor,
+	})
+}
+
+// memberCan reports whether userID is a member of groupID whose role grants permission.
+func (gc *GroupController) memberCan(groupID, userID int, permission group.Permission) bool {
+	member, ok := gc.Model.GetMember(groupID, userID)
+	return ok && member.Can(permission)
+}
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/auth"
+	"backend/judge"
+	"backend/models/group"
+	"backend/models/linkedaccount"
+	"backend/models/responses"
+
+	"github.com/gorilla/mux"
+)
+
+type LinkedAccountController struct {
+	Model      linkedaccount.LinkedAccountModel
+	GroupModel group.GroupModel
+	Importer   *judge.Importer
+	Scheduler  *judge.Scheduler
+}
+
+const (
+	maxHandleLength = 50
+	// resolveHandleTimeout bounds the call to the judge while the user waits for the response
+	resolveHandleTimeout = 10 * time.Second
+)
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewLinkedAccountController(model linkedaccount.LinkedAccountModel, groupModel group.GroupModel, importer *judge.Importer, scheduler *judge.Scheduler) *LinkedAccountController {
+	return &LinkedAccountController{Model: model, GroupModel: groupModel, Importer: importer, Scheduler: scheduler}
+}
+
+// GetUserAccounts godoc
+// @Summary List linked judge accounts
+// @Description List the online judge handles a user has linked
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} responses.LinkedAccountsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /users/{id}/accounts [get]
+func (lc *LinkedAccountController) GetUserAccounts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid user id: %v", err)
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"user_id":  userID,
+		"accounts": lc.Model.GetAccountsByUserID(userID),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// LinkAccount godoc
+// @Summary Link a judge account
+// @Description Link the authenticated user's handle on an online judge, replacing the handle linked before; relinking the same handle only changes the groups and keeps its import position. Accepted submissions made from now on are imported as activities in the background, see /users/me/sync-status and posted to the given groups. Codeforces, AtCoder and LeetCode accounts can be linked; AtCoder submissions come from AtCoder Problems, with its difficulty estimates as ratings, and LeetCode only lists a user's 20 most recent accepted submissions
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param judge path string true "Judge" Enums(codeforces, atcoder, leetcode)
+// @Param request body responses.LinkAccountRequest true "Handle and the groups to post imported activities to"
+// @Success 200 {object} linkedaccount.LinkedAccount
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 502 {object} responses.ErrorResponse
+// @Router /users/me/accounts/{judge} [put]
+func (lc *LinkedAccountController) LinkAccount(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	judgeName := mux.Vars(r)["judge"]
+	fetcher, ok := lc.Importer.Fetcher(judgeName)
+	if !ok {
+		http.Error(w, "Accounts on "+judgeName+" cannot be linked", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Handle   string `json:"handle"`
+		GroupIDs []int  `json:"group_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode linked account payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	request.Handle = strings.TrimSpace(request.Handle)
+	if request.Handle == "" || len(request.Handle) > maxHandleLength || strings.ContainsAny(request.Handle, " \t\n/?#&") {
+		http.Error(w, "Invalid handle", http.StatusBadRequest)
+		return
+	}
+
+	groupIDs := linkedaccount.IDs{}
+	seen := map[int]bool{}
+	for _, groupID := range request.GroupIDs {
+		if seen[groupID] {
+			continue
+		}
+		seen[groupID] = true
+		if _, exists := lc.GroupModel.GetGroupByID(groupID); !exists {
+			log.Printf("Group not found: id=%d", groupID)
+			http.Error(w, "Group not found", http.StatusNotFound)
+			return
+		}
+		if !lc.GroupModel.IsUserInGroup(groupID, requester.ID) {
+			log.Printf("Forbidden: requester_id=%d is not a member of group_id=%d", requester.ID, groupID)
+			http.Error(w, "Forbidden: You can only post activities to groups you are a member of", http.StatusForbidden)
+			return
+		}
+		groupIDs = append(groupIDs, groupID)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), resolveHandleTimeout)
+	defer cancel()
+	handle, err := fetcher.ResolveHandle(ctx, request.Handle)
+	if errors.Is(err, judge.ErrHandleNotFound) {
+		http.Error(w, "Unknown "+judgeName+" handle: "+request.Handle, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to look up %s handle %q: %v", judgeName, request.Handle, err)
+		http.Error(w, "Could not reach "+judgeName, http.StatusBadGateway)
+		return
+	}
+
+	if owner, exists := lc.Model.GetAccountByHandle(judgeName, handle); exists && owner.UserID != requester.ID {
+		http.Error(w, "This handle is already linked to another user", http.StatusConflict)
+		return
+	}
+
+	// Due right away, so that the first import marks where later ones start from. Relinking the same
+	// handle, e.g. to change the groups, keeps the import position
+	now := time.Now()
+	account := linkedaccount.LinkedAccount{UserID: requester.ID, Judge: judgeName, Handle: handle, GroupIDs: groupIDs, NextSyncAt: &now}
+	saved, ok := lc.Model.SaveAccount(account)
+	if !ok {
+		log.Printf("Failed to link %s account for user_id=%d", judgeName, requester.ID)
+		http.Error(w, "Failed to link account", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// UnlinkAccount godoc
+// @Summary Unlink a judge account
+// @Description Stop importing from the authenticated user's account on an online judge. Activities imported so far are kept
+// @Tags users
+// @Security BearerAuth
+// @Param judge path string true "Judge"
+// @Success 204 "No Content"
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/me/accounts/{judge} [delete]
+func (lc *LinkedAccountController) UnlinkAccount(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	judgeName := mux.Vars(r)["judge"]
+	if !lc.Model.DeleteAccount(requester.ID, judgeName) {
+		http.Error(w, "No "+judgeName+" account linked", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TriggerSync godoc
+// @Summary Sync linked judge accounts now
+// @Description Import the authenticated user's linked judge accounts right away instead of waiting for their next scheduled import. Accounts imported within the last minute are left alone. The import runs in the background
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} responses.SyncStatusResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/me/sync [post]
+func (lc *LinkedAccountController) TriggerSync(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	accounts := lc.Scheduler.TriggerSync(requester.ID)
+	if len(accounts) == 0 {
+		http.Error(w, "No judge accounts linked", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"accounts": lc.syncStatuses(accounts)})
+}
+
+// GetSyncStatus godoc
+// @Summary Get judge sync status
+// @Description Show, per linked judge account of the authenticated user, when it was last imported successfully, the last error, how many imports failed in a row and when the next import is due
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} responses.SyncStatusResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /users/me/sync-status [get]
+func (lc *LinkedAccountController) GetSyncStatus(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	accounts := lc.Model.GetAccountsByUserID(requester.ID)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"accounts": lc.syncStatuses(accounts)})
+}
+
+func (lc *LinkedAccountController) syncStatuses(accounts []linkedaccount.LinkedAccount) []linkedaccount.SyncStatus {
+	statuses := make([]linkedaccount.SyncStatus, len(accounts))
+	for i, account := range accounts {
+		statuses[i] = account.SyncStatus(lc.Scheduler.Syncing(account.ID))
+	}
+	return statuses
+}
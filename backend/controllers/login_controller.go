@@ -2,16 +2,26 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
+	"time"
 
+	"backend/auth"
 	"backend/models/responses"
+	"backend/models/session"
 	"backend/models/user"
+	"backend/ratelimit"
+
+	"github.com/gorilla/mux"
 )
 
 type LoginController struct {
-	Model user.UserModel
+	Model    user.UserModel
+	Sessions session.SessionModel
+	Tokens   *auth.TokenManager
 }
 
 // swagger imports (used in annotations)
@@ -19,20 +29,23 @@ var (
 	_ = responses.ErrorResponse{}
 )
 
-func NewLoginController(model user.UserModel) *LoginController {
-	return &LoginController{Model: model}
+func NewLoginController(model user.UserModel, sessions session.SessionModel, tokens *auth.TokenManager) *LoginController {
+	return &LoginController{Model: model, Sessions: sessions, Tokens: tokens}
 }
 
 // Login godoc
 // @Summary Authenticate user
-// @Description Authenticate user with email and password, returns user data and token
+// @Description Authenticate user with email and password, starts a session and returns user data, a signed access token and a refresh token. Users with two-factor login get a challenge for /login/2fa instead
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param login body responses.LoginRequest true "Login credentials"
 // @Success 200 {object} responses.LoginResponse
+// @Success 202 {object} responses.LoginChallengeResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
 // @Router /login [post]
 func (lc *LoginController) Login(w http.ResponseWriter, r *http.Request) {
 	var loginRequest struct {
@@ -52,6 +65,13 @@ func (lc *LoginController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A locked account is refused even with the right password, so guessing gains nothing until it unlocks
+	if lockedUntil, locked := lc.Model.LockedUntil(loginRequest.Email); locked {
+		log.Printf("Login attempt for locked account: %s", loginRequest.Email)
+		ratelimit.TooManyRequests(w, time.Until(lockedUntil))
+		return
+	}
+
 	user, valid := lc.Model.ValidateCredentials(loginRequest.Email, loginRequest.Password)
 	if !valid {
 		log.Printf("Invalid credentials for email: %s", loginRequest.Email)
@@ -59,11 +79,236 @@ func (lc *LoginController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lc.finishLogin(w, r, user)
+}
+
+// finishLogin responds to a successful first login step for u: with a session, or with a challenge for
+// /login/2fa when u has two-factor login enabled.
+func (lc *LoginController) finishLogin(w http.ResponseWriter, r *http.Request, u user.User) {
+	if u.TOTPEnabled() {
+		challenge, expiresAt, err := lc.Tokens.IssueChallenge(u.ID)
+		if err != nil {
+			log.Printf("Failed to issue login challenge for user_id=%d: %v", u.ID, err)
+			http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"two_factor_required": true,
+			"challenge":           challenge,
+			"expires_at":          expiresAt,
+		})
+		return
+	}
+
+	response, err := lc.startSession(r, u)
+	if err != nil {
+		log.Printf("Failed to issue token for user_id=%d: %v", u.ID, err)
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// startSession creates a session for u and returns the login response with its access and refresh tokens.
+func (lc *LoginController) startSession(r *http.Request, u user.User) (map[string]interface{}, error) {
+	refreshToken, refreshHash, err := auth.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	newSession := session.Session{
+		UserID:    u.ID,
+		UserAgent: r.UserAgent(),
+		IPAddress: clientIP(r),
+		ExpiresAt: lc.Tokens.RefreshTokenExpiry(),
+	}
+	createdSession, ok := lc.Sessions.CreateSession(newSession, refreshHash)
+	if !ok {
+		return nil, errors.New("failed to create session")
+	}
+
+	token, expiresAt, err := lc.Tokens.IssueAccessToken(u.ID, createdSession.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"user":          u,
+		"token":         token,
+		"token_type":    "Bearer",
+		"expires_at":    expiresAt,
+		"refresh_token": refreshToken,
+	}, nil
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; presenting a used one revokes its session
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body responses.RefreshRequest true "Refresh token"
+// @Success 200 {object} responses.TokenResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/refresh [post]
+func (lc *LoginController) Refresh(w http.ResponseWriter, r *http.Request) {
+	var refreshRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil {
+		log.Printf("Failed to decode refresh request payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if refreshRequest.RefreshToken == "" {
+		log.Println("Missing refresh_token in refresh request")
+		http.Error(w, "refresh_token is required", http.StatusBadRequest)
+		return
+	}
+
+	refreshToken, refreshHash, err := auth.NewSecret()
+	if err != nil {
+		log.Printf("Failed to generate refresh token: %v", err)
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	refreshedSession, err := lc.Sessions.RotateRefreshToken(auth.HashSecret(refreshRequest.RefreshToken), refreshHash, lc.Tokens.RefreshTokenExpiry())
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrRefreshTokenReused):
+			log.Printf("Refresh token reuse detected, session revoked")
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		case errors.Is(err, session.ErrRefreshTokenNotFound), errors.Is(err, session.ErrSessionInactive):
+			log.Printf("Rejected refresh token: %v", err)
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		default:
+			log.Printf("Failed to rotate refresh token: %v", err)
+			http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	token, expiresAt, err := lc.Tokens.IssueAccessToken(refreshedSession.UserID, refreshedSession.ID)
+	if err != nil {
+		log.Printf("Failed to issue token for user_id=%d: %v", refreshedSession.UserID, err)
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"token":         token,
+		"token_type":    "Bearer",
+		"expires_at":    expiresAt,
+		"refresh_token": refreshToken,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the session of the current access token. The access token and its refresh token stop working immediately
+// @Tags authentication
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /logout [post]
+func (lc *LoginController) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := auth.CurrentSessionID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lc.Sessions.RevokeSession(sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSessions godoc
+// @Summary List sessions
+// @Description List the active sessions of the authenticated user. The session of the current access token is marked as current
+// @Tags authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} responses.SessionsResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /users/me/sessions [get]
+func (lc *LoginController) GetSessions(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	currentSessionID, _ := auth.CurrentSessionID(r)
+
+	sessions := lc.Sessions.GetActiveSessionsByUserID(requester.ID)
+	if sessions == nil {
+		sessions = []session.Session{}
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
 	response := map[string]interface{}{
-		"user":  user,
-		"token": "dummy-jwt-token-" + strconv.Itoa(user.ID),
+		"sessions": sessions,
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// RevokeSession godoc
+// @Summary Revoke session
+// @Description Log out one of the authenticated user's sessions, e.g. a lost device
+// @Tags authentication
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/me/sessions/{id} [delete]
+func (lc *LoginController) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid session id: %v", err)
+		http.Error(w, "Invalid session id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Other users' sessions are reported as missing so their IDs cannot be probed
+	s, exists := lc.Sessions.GetSessionByID(sessionID)
+	if !exists || s.UserID != requester.ID || !s.IsActive(time.Now()) {
+		log.Printf("Session not found: id=%d user_id=%d", sessionID, requester.ID)
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	lc.Sessions.RevokeSession(sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
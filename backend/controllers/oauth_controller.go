@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/auth"
+	"backend/auth/oauth"
+	"backend/models/identity"
+	"backend/models/responses"
+	"backend/models/user"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	oauthStateCookie = "codeck_oauth_state"
+	oauthStateTTL    = 10 * time.Minute
+)
+
+// OAuthController signs users in through external providers and then issues a normal session via LoginController.
+type OAuthController struct {
+	Providers  oauth.Registry
+	Identities identity.IdentityModel
+	Users      user.UserModel
+	Login      *LoginController
+}
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewOAuthController(providers oauth.Registry, identities identity.IdentityModel, users user.UserModel, login *LoginController) *OAuthController {
+	return &OAuthController{Providers: providers, Identities: identities, Users: users, Login: login}
+}
+
+// GetProviders godoc
+// @Summary List login providers
+// @Description List the names of the configured external login providers
+// @Tags authentication
+// @Produce json
+// @Success 200 {object} responses.ProvidersResponse
+// @Router /auth/providers [get]
+func (oc *OAuthController) GetProviders(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"providers": oc.Providers.Names(),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// StartLogin godoc
+// @Summary Start external login
+// @Description Redirect to the provider's consent page. The state is bound to the browser with a cookie and the code exchange is protected with PKCE
+// @Tags authentication
+// @Param provider path string true "Provider name, e.g. github or google"
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/{provider}/login [get]
+func (oc *OAuthController) StartLogin(w http.ResponseWriter, r *http.Request) {
+	providerName := mux.Vars(r)["provider"]
+	provider, exists := oc.Providers[providerName]
+	if !exists {
+		http.Error(w, "Unknown login provider", http.StatusNotFound)
+		return
+	}
+
+	state, _, err := auth.NewSecret()
+	if err != nil {
+		log.Printf("Failed to generate oauth state: %v", err)
+		http.Error(w, "Failed to start login", http.StatusInternalServerError)
+		return
+	}
+	verifier := oauth.GenerateVerifier()
+
+	if !oc.Identities.CreateLoginState(identity.LoginState{
+		State:     state,
+		Provider:  providerName,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(oauthStateTTL),
+	}) {
+		log.Printf("Failed to store oauth state for provider=%s", providerName)
+		http.Error(w, "Failed to start login", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/auth/" + providerName,
+		MaxAge:   int(oauthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, provider.AuthCodeURL(state, verifier), http.StatusFound)
+}
+
+// Callback godoc
+// @Summary Finish external login
+// @Description Handle the provider's redirect: exchange the code, then sign in the linked user. Unlinked identities with a verified email are linked to the account with the same email if that account verified it too, or a new account is created. An existing account that has not verified the email must sign in with its password and verify it first
+// @Tags authentication
+// @Produce json
+// @Param provider path string true "Provider name, e.g. github or google"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} responses.LoginResponse
+// @Success 202 {object} responses.LoginChallengeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/{provider}/callback [get]
+func (oc *OAuthController) Callback(w http.ResponseWriter, r *http.Request) {
+	providerName := mux.Vars(r)["provider"]
+	provider, exists := oc.Providers[providerName]
+	if !exists {
+		http.Error(w, "Unknown login provider", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		log.Printf("Provider %s denied login: %s", providerName, providerErr)
+		http.Error(w, "Login was denied by the provider", http.StatusUnauthorized)
+		return
+	}
+
+	state, code := query.Get("state"), query.Get("code")
+	if state == "" || code == "" {
+		http.Error(w, "code and state are required", http.StatusBadRequest)
+		return
+	}
+
+	// The cookie proves the callback comes from the browser that started the login
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		log.Printf("OAuth state does not match cookie for provider=%s", providerName)
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookie, Value: "", Path: "/auth/" + providerName, MaxAge: -1})
+
+	loginState, valid := oc.Identities.ConsumeLoginState(state)
+	if !valid || loginState.Provider != providerName {
+		log.Printf("Unknown or expired oauth state for provider=%s", providerName)
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	external, err := provider.Exchange(r.Context(), code, loginState.Verifier)
+	if err != nil {
+		log.Printf("OAuth exchange with provider=%s failed: %v", providerName, err)
+		http.Error(w, "Login with provider failed", http.StatusUnauthorized)
+		return
+	}
+
+	u, status, message := oc.resolveUser(providerName, external)
+	if status != http.StatusOK {
+		http.Error(w, message, status)
+		return
+	}
+
+	oc.Login.finishLogin(w, r, u)
+}
+
+// resolveUser finds the user linked to an external identity, linking or creating one on first login.
+// Anything but http.StatusOK is an error to report with message.
+func (oc *OAuthController) resolveUser(providerName string, external oauth.Identity) (user.User, int, string) {
+	if linked, exists := oc.Identities.GetIdentity(providerName, external.Subject); exists {
+		u, exists := oc.Users.GetUserByID(linked.UserID)
+		if !exists {
+			log.Printf("Identity links to missing user: provider=%s user_id=%d", providerName, linked.UserID)
+			return user.User{}, http.StatusUnauthorized, "Linked account no longer exists"
+		}
+		return u, http.StatusOK, ""
+	}
+
+	// Without a verified address anyone could claim an existing account by setting its email at the provider
+	if external.Email == "" || !external.EmailVerified {
+		log.Printf("Provider %s returned no verified email for subject=%s", providerName, external.Subject)
+		return user.User{}, http.StatusForbidden, "A verified email address is required to sign in with this provider"
+	}
+
+	u, exists := oc.Users.GetUserByEmail(external.Email)
+	if exists {
+		// Whoever signed up with an unverified address may not own it, and could still sign in with their password
+		if u.EmailVerifiedAt == nil {
+			log.Printf("Refusing to link provider=%s to unverified user_id=%d", providerName, u.ID)
+			return user.User{}, http.StatusConflict, "An account with this email already exists. Sign in with its password and verify the email to sign in with this provider"
+		}
+	} else {
+		password, _, err := auth.NewSecret()
+		if err != nil {
+			log.Printf("Failed to generate password for new oauth user: %v", err)
+			return user.User{}, http.StatusInternalServerError, "Failed to create user"
+		}
+		name := external.Name
+		if name == "" {
+			name, _, _ = strings.Cut(external.Email, "@")
+		}
+		now := time.Now()
+		u = oc.Users.CreateUser(user.User{
+			Email:           external.Email,
+			Name:            name,
+			Password:        password,
+			EmailVerifiedAt: &now,
+		})
+		if u.ID == 0 {
+			log.Printf("Failed to create user for oauth email=%s", external.Email)
+			return user.User{}, http.StatusInternalServerError, "Failed to create user"
+		}
+	}
+
+	if _, ok := oc.Identities.CreateIdentity(identity.Identity{
+		UserID:   u.ID,
+		Provider: providerName,
+		Subject:  external.Subject,
+		Email:    external.Email,
+	}); !ok {
+		log.Printf("Failed to link identity: provider=%s user_id=%d", providerName, u.ID)
+		return user.User{}, http.StatusInternalServerError, "Failed to link account"
+	}
+	return u, http.StatusOK, ""
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/auth"
+	"backend/models/activity"
+	"backend/models/group"
+	"backend/models/problem"
+	"backend/models/responses"
+
+	"github.com/gorilla/mux"
+)
+
+type ProblemController struct {
+	Model         problem.ProblemModel
+	GroupModel    group.GroupModel
+	ActivityModel activity.ActivityModel
+}
+
+const (
+	defaultRecommendations = 10
+	maxRecommendations     = 50
+)
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewProblemController(model problem.ProblemModel, groupModel group.GroupModel, activityModel activity.ActivityModel) *ProblemController {
+	return &ProblemController{Model: model, GroupModel: groupModel, ActivityModel: activityModel}
+}
+
+// GetProblem godoc
+// @Summary Get a problem
+// @Description Get a problem from the catalog with the requester and members of the requester's groups who solved it, in the order they first solved it. Activities with an accepted verdict or without a verdict count as solves
+// @Tags problems
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Problem ID"
+// @Success 200 {object} responses.ProblemResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /problems/{id} [get]
+func (pc *ProblemController) GetProblem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	problemID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid problem id: %v", err)
+		http.Error(w, "Invalid problem id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	p, exists := pc.Model.GetProblemByID(problemID)
+	if !exists {
+		log.Printf("Problem not found: id=%d", problemID)
+		http.Error(w, "Problem not found", http.StatusNotFound)
+		return
+	}
+
+	solvers := pc.Model.GetSolvers(problemID, requester.ID)
+	var firstSolver *problem.Solver
+	if len(solvers) > 0 {
+		firstSolver = &solvers[0]
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"problem":      p,
+		"solve_count":  len(solvers),
+		"first_solver": firstSolver,
+		"solvers":      solvers,
+	})
+}
+
+// GetGroupProblems godoc
+// @Summary Get problems solved in a group
+// @Description List the problems the group's current members solved between the group's start and end dates, with how many of them solved each and who solved it first, most solved first. Members show up under their group nickname when set (members only)
+// @Tags problems
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Group ID"
+// @Success 200 {object} responses.GroupProblemsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /groups/{id}/problems [get]
+func (pc *ProblemController) GetGroupProblems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, exists := pc.GroupModel.GetGroupByID(groupID); !exists {
+		http.Error(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	if !pc.GroupModel.IsUserInGroup(groupID, requester.ID) {
+		http.Error(w, "Forbidden: Only group members can view the group's problems", http.StatusForbidden)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group_id": groupID,
+		"problems": pc.Model.GetGroupProblems(groupID),
+	})
+}
+
+// GetRecommendations godoc
+// @Summary Get recommended problems
+// @Description Suggest rated catalog problems the requester has not solved, near the rating estimated from their hardest solves. Problems slightly above that rating, with tags the requester solved few problems with, and solved by many of the requester's groupmates come first. The same seed gives the same recommendations; without one, the recommendations change daily
+// @Tags problems
+// @Produce json
+// @Security BearerAuth
+// @Param count query int false "Number of recommendations, 1 to 50 (default 10)"
+// @Param tags query string false "Comma-separated tags; only problems with at least one of them are recommended, e.g. dp,greedy"
+// @Param seed query int false "Seed for the random part of the scores"
+// @Success 200 {object} responses.RecommendationsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /users/me/recommendations [get]
+func (pc *ProblemController) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	count := defaultRecommendations
+	if value := query.Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRecommendations {
+			http.Error(w, "count must be a number between 1 and "+strconv.Itoa(maxRecommendations), http.StatusBadRequest)
+			return
+		}
+		count = parsed
+	}
+
+	var tags []string
+	if value := query.Get("tags"); value != "" {
+		for _, tag := range strings.Split(value, ",") {
+			tag = activity.NormalizeTag(tag)
+			if !activity.ValidTag(tag) {
+				http.Error(w, "Invalid tag", http.StatusBadRequest)
+				return
+			}
+			tags = append(tags, tag)
+		}
+		if len(tags) > activity.MaxTags {
+			http.Error(w, "At most "+strconv.Itoa(activity.MaxTags)+" tags can be given", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The day number, so recommendations stay put for a day
+	seed := time.Now().UTC().Unix() / int64(24*time.Hour/time.Second)
+	if value := query.Get("seed"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "seed must be a number", http.StatusBadRequest)
+			return
+		}
+		seed = parsed
+	}
+
+	history := pc.ActivityModel.GetActivitiesByCreatorID(requester.ID)
+	rating := problem.EstimateRating(history)
+	candidates := pc.Model.GetRatedProblems(rating-problem.RecommendBelow, rating+problem.RecommendAbove, tags)
+	recommendations := problem.Recommend(history, candidates, pc.Model.CountGroupmateSolves(requester.ID), count, seed)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"estimated_rating": rating,
+		"seed":             seed,
+		"recommendations":  recommendations,
+	})
+}
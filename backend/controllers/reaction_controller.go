@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"backend/auth"
+	"backend/models/activity"
+	"backend/models/reaction"
+	"backend/models/responses"
+
+	"github.com/gorilla/mux"
+)
+
+type ReactionController struct {
+	Model         reaction.ReactionModel
+	ActivityModel activity.ActivityModel
+}
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewReactionController(model reaction.ReactionModel, activityModel activity.ActivityModel) *ReactionController {
+	return &ReactionController{Model: model, ActivityModel: activityModel}
+}
+
+// GetReactions godoc
+// @Summary Get reactions of an activity
+// @Description Get how many reactions of each kind an activity has
+// @Tags reactions
+// @Produce json
+// @Param activity_id path string true "Activity ID"
+// @Success 200 {object} responses.ReactionsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /activities/{activity_id}/reactions [get]
+func (rc *ReactionController) GetReactions(w http.ResponseWriter, r *http.Request) {
+	activityID, ok := rc.activityID(w, r)
+	if !ok {
+		return
+	}
+
+	rc.writeSummary(w, activityID, 0)
+}
+
+// AddReaction godoc
+// @Summary React to an activity
+// @Description Leave a reaction of the given kind (like, fire, clap or mind_blown) on an activity. Reacting twice with the same kind has no further effect
+// @Tags reactions
+// @Produce json
+// @Security BearerAuth
+// @Param activity_id path string true "Activity ID"
+// @Param kind path string true "Reaction kind"
+// @Success 200 {object} responses.ReactionsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /activities/{activity_id}/reactions/{kind} [put]
+func (rc *ReactionController) AddReaction(w http.ResponseWriter, r *http.Request) {
+	activityID, ok := rc.activityID(w, r)
+	if !ok {
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	kind := mux.Vars(r)["kind"]
+	if !reaction.ValidKind(kind) {
+		http.Error(w, "Unknown reaction kind: "+kind, http.StatusBadRequest)
+		return
+	}
+
+	// Fails when the reaction already exists, which leaves things as the requester wants them
+	if !rc.Model.AddReaction(reaction.Reaction{ActivityID: activityID, UserID: requester.ID, Kind: kind}) {
+		log.Printf("Reaction not added: activity_id=%d, user_id=%d, kind=%s", activityID, requester.ID, kind)
+	}
+
+	rc.writeSummary(w, activityID, requester.ID)
+}
+
+// RemoveReaction godoc
+// @Summary Remove a reaction
+// @Description Take back the requester's reaction of the given kind on an activity
+// @Tags reactions
+// @Produce json
+// @Security BearerAuth
+// @Param activity_id path string true "Activity ID"
+// @Param kind path string true "Reaction kind"
+// @Success 200 {object} responses.ReactionsResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /activities/{activity_id}/reactions/{kind} [delete]
+func (rc *ReactionController) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	activityID, ok := rc.activityID(w, r)
+	if !ok {
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	kind := mux.Vars(r)["kind"]
+	if !rc.Model.RemoveReaction(activityID, requester.ID, kind) {
+		http.Error(w, "Reaction not found", http.StatusNotFound)
+		return
+	}
+
+	rc.writeSummary(w, activityID, requester.ID)
+}
+
+// activityID reads the activity from the path and answers with an error if it does not exist.
+func (rc *ReactionController) activityID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	activityID, err := strconv.Atoi(mux.Vars(r)["activity_id"])
+	if err != nil {
+		log.Printf("Invalid activity_id: %v", err)
+		http.Error(w, "Invalid activity_id", http.StatusBadRequest)
+		return 0, false
+	}
+
+	if _, exists := rc.ActivityModel.GetActivityByID(activityID); !exists {
+		log.Printf("Activity not found: id=%d", activityID)
+		http.Error(w, "Activity not found", http.StatusNotFound)
+		return 0, false
+	}
+	return activityID, true
+}
+
+func (rc *ReactionController) writeSummary(w http.ResponseWriter, activityID, viewerID int) {
+	summaries := rc.Model.GetSummaries([]int{activityID}, viewerID)[activityID]
+	if summaries == nil {
+		summaries = []reaction.Summary{}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"activity_id": activityID,
+		"reactions":   summaries,
+	})
+}
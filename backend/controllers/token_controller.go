@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/auth"
+	"backend/models/pat"
+	"backend/models/responses"
+
+	"github.com/gorilla/mux"
+)
+
+type TokenController struct {
+	Model pat.TokenModel
+}
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewTokenController(model pat.TokenModel) *TokenController {
+	return &TokenController{Model: model}
+}
+
+// GetTokens godoc
+// @Summary List personal access tokens
+// @Description List the authenticated user's personal access tokens. The secrets themselves are never returned again
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} responses.TokensResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Router /users/me/tokens [get]
+func (tc *TokenController) GetTokens(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokens := tc.Model.GetTokensByUserID(requester.ID)
+	if tokens == nil {
+		tokens = []pat.PersonalAccessToken{}
+	}
+
+	response := map[string]interface{}{
+		"tokens": tokens,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateToken godoc
+// @Summary Create personal access token
+// @Description Create a named token with the given scopes for scripts and plugins. The token is only shown in this response
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body responses.TokenCreateRequest true "Token name, scopes and optional expiry"
+// @Success 201 {object} responses.TokenCreateResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/tokens [post]
+func (tc *TokenController) CreateToken(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode token request payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.Name == "" || len(request.Scopes) == 0 {
+		http.Error(w, "Name and at least one scope are required", http.StatusBadRequest)
+		return
+	}
+
+	if len(request.Name) > 100 {
+		http.Error(w, "Name cannot be longer than 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range request.Scopes {
+		if !pat.ValidScope(scope) {
+			http.Error(w, "Unknown scope: "+scope, http.StatusBadRequest)
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		http.Error(w, "expires_at must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	secret, _, err := auth.NewSecret()
+	if err != nil {
+		log.Printf("Failed to generate personal access token: %v", err)
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+	secret = pat.Prefix + secret
+
+	created, ok := tc.Model.CreateToken(pat.PersonalAccessToken{
+		UserID:    requester.ID,
+		Name:      request.Name,
+		Scopes:    scopes,
+		TokenHash: auth.HashSecret(secret),
+		ExpiresAt: request.ExpiresAt,
+	})
+	if !ok {
+		log.Printf("Failed to store personal access token for user_id=%d", requester.ID)
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"token":   secret,
+		"details": created,
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteToken godoc
+// @Summary Delete personal access token
+// @Description Revoke one of the authenticated user's personal access tokens
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/me/tokens/{id} [delete]
+func (tc *TokenController) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tokenID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid token id: %v", err)
+		http.Error(w, "Invalid token id", http.StatusBadRequest)
+		return
+	}
+
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !tc.Model.DeleteToken(tokenID, requester.ID) {
+		log.Printf("Token not found: id=%d user_id=%d", tokenID, requester.ID)
+		http.Error(w, "Token not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
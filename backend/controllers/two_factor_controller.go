@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/auth"
+	"backend/auth/totp"
+	"backend/models/responses"
+	"backend/models/user"
+	"backend/ratelimit"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	totpIssuer        = "CODECK"
+	recoveryCodeCount = 10
+)
+
+// TwoFactorController manages TOTP enrollment and completes two-step logins started by LoginController.
+type TwoFactorController struct {
+	Model user.UserModel
+	Login *LoginController
+}
+
+// swagger imports (used in annotations)
+var (
+	_ = responses.ErrorResponse{}
+)
+
+func NewTwoFactorController(model user.UserModel, login *LoginController) *TwoFactorController {
+	return &TwoFactorController{Model: model, Login: login}
+}
+
+// EnrollTOTP godoc
+// @Summary Start TOTP enrollment
+// @Description Generate a new TOTP secret for the authenticated user. Two-factor login is only turned on after the first code is confirmed
+// @Tags two-factor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} responses.TOTPEnrollResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/2fa/totp [post]
+func (tc *TwoFactorController) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if requester.TOTPEnabled() {
+		http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Printf("Failed to generate TOTP secret: %v", err)
+		http.Error(w, "Failed to start enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	uri := totp.URI(totpIssuer, requester.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		log.Printf("Failed to render TOTP QR code: %v", err)
+		http.Error(w, "Failed to start enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	if !tc.Model.SetTOTPSecret(requester.ID, secret) {
+		log.Printf("Failed to store TOTP secret for user_id=%d", requester.ID)
+		http.Error(w, "Failed to start enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"secret":      secret,
+		"otpauth_uri": uri,
+		"qr_code":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Turn on two-factor login with a code from the authenticator app. Returns recovery codes, which are only shown once
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body responses.TOTPCodeRequest true "Current TOTP code"
+// @Success 200 {object} responses.RecoveryCodesResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/2fa/totp/confirm [post]
+func (tc *TwoFactorController) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Code string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if requester.TOTPEnabled() {
+		http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	if requester.TOTPSecret == "" {
+		http.Error(w, "No enrollment in progress", http.StatusBadRequest)
+		return
+	}
+
+	if !tc.checkTOTP(requester, request.Code) {
+		log.Printf("Invalid TOTP confirmation code for user_id=%d", requester.ID)
+		http.Error(w, "Invalid code", http.StatusBadRequest)
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		log.Printf("Failed to generate recovery codes: %v", err)
+		http.Error(w, "Failed to enable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	if !tc.Model.EnableTOTP(requester.ID, hashes) {
+		log.Printf("Failed to enable TOTP for user_id=%d", requester.ID)
+		http.Error(w, "Failed to enable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"recovery_codes": codes,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Description Turn off two-factor login. Requires a current TOTP code or an unused recovery code
+// @Tags two-factor
+// @Accept json
+// @Security BearerAuth
+// @Param request body responses.TOTPCodeRequest true "TOTP or recovery code"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/2fa/totp [delete]
+func (tc *TwoFactorController) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Code string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if !requester.TOTPEnabled() {
+		http.Error(w, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !tc.checkSecondFactor(requester, request.Code) {
+		log.Printf("Invalid code to disable TOTP for user_id=%d", requester.ID)
+		http.Error(w, "Invalid code", http.StatusBadRequest)
+		return
+	}
+
+	if !tc.Model.DisableTOTP(requester.ID) {
+		log.Printf("Failed to disable TOTP for user_id=%d", requester.ID)
+		http.Error(w, "Failed to disable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompleteLogin godoc
+// @Summary Complete two-step login
+// @Description Exchange the challenge returned by /login and a TOTP or recovery code for a session
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body responses.TwoFactorLoginRequest true "Challenge and code"
+// @Success 200 {object} responses.LoginResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /login/2fa [post]
+func (tc *TwoFactorController) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Challenge string `json:"challenge"`
+		Code      string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if request.Challenge == "" || request.Code == "" {
+		http.Error(w, "Challenge and code are required", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := tc.Login.Tokens.ParseChallenge(request.Challenge)
+	if err != nil {
+		log.Printf("Rejected login challenge: %v", err)
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+
+	u, exists := tc.Model.GetUserByID(userID)
+	if !exists || !u.TOTPEnabled() {
+		http.Error(w, "Invalid or expired challenge", http.StatusUnauthorized)
+		return
+	}
+
+	if lockedUntil, locked := tc.Model.LockedUntil(u.Email); locked {
+		ratelimit.TooManyRequests(w, time.Until(lockedUntil))
+		return
+	}
+
+	if !tc.checkSecondFactor(u, request.Code) {
+		log.Printf("Invalid second factor for user_id=%d", u.ID)
+		tc.Model.RecordLoginFailure(u.ID)
+		http.Error(w, "Invalid code", http.StatusUnauthorized)
+		return
+	}
+
+	response, err := tc.Login.startSession(r, u)
+	if err != nil {
+		log.Printf("Failed to issue token for user_id=%d: %v", u.ID, err)
+		http.Error(w, "Failed to issue token", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// checkTOTP accepts a code from u's authenticator once; the same code cannot be used again.
+func (tc *TwoFactorController) checkTOTP(u user.User, code string) bool {
+	step, valid := totp.Validate(u.TOTPSecret, strings.TrimSpace(code), time.Now())
+	return valid && tc.Model.RecordTOTPStep(u.ID, step)
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code.
+func (tc *TwoFactorController) checkSecondFactor(u user.User, code string) bool {
+	if tc.checkTOTP(u, code) {
+		return true
+	}
+	return tc.Model.ConsumeRecoveryCode(u.ID, auth.HashSecret(normalizeRecoveryCode(code)))
+}
+
+// newRecoveryCodes returns codes formatted for the user, e.g. "k3f9a-2mzq7", and the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	alphabet := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(alphabet.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = auth.HashSecret(raw)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
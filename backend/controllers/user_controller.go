@@ -7,7 +7,9 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
+	"backend/auth"
 	"backend/models/activity"
 	"backend/models/responses"
 	"backend/models/user"
@@ -20,6 +22,9 @@ type UserController struct {
 	ActivityModel activity.ActivityModel
 }
 
+// maxCalendarDays caps the range of a single calendar request.
+const maxCalendarDays = 366
+
 // swagger imports (used in annotations)
 var (
 	_ = responses.ErrorResponse{}
@@ -69,6 +74,7 @@ func (uc *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} user.User
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
 // @Router /users [post]
 func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var userInput struct {
@@ -92,6 +98,12 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(userInput.Password) > 72 {
+		log.Println("Password too long in user creation")
+		http.Error(w, "Password cannot be longer than 72 bytes", http.StatusBadRequest)
+		return
+	}
+
 	if _, exists := uc.Model.GetUserByEmail(userInput.Email); exists {
 		log.Printf("Email already in use: %s", userInput.Email)
 		http.Error(w, "Email already in use", http.StatusConflict)
@@ -105,6 +117,11 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	createdUser := uc.Model.CreateUser(user)
+	if createdUser.ID == 0 {
+		log.Printf("Failed to create user: email=%s", userInput.Email)
+		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(createdUser)
@@ -112,11 +129,14 @@ func (uc *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 // GetUserActivities godoc
 // @Summary Get user activities
-// @Description Get all activities created by a specific user
+// @Description Get the activities created by a specific user, optionally only those from one judge, with one tag or rated at least min_rating
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Param judge query string false "Online judge, e.g. codeforces"
+// @Param tag query string false "Problem tag, e.g. dp"
+// @Param min_rating query int false "Minimum problem rating"
 // @Success 200 {array} activity.Activity
 // @Failure 404 {object} responses.ErrorResponse
 // @Router /users/{id}/activities [get]
@@ -138,9 +158,305 @@ func (uc *UserController) GetUserActivities(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Get all activities for this user
-	activities := uc.ActivityModel.GetActivitiesByCreatorID(userID)
+	query := r.URL.Query()
+	filter := activity.Filter{
+		Judge: query.Get("judge"),
+		Tag:   activity.NormalizeTag(query.Get("tag")),
+	}
+	if filter.Judge != "" && !activity.ValidJudge(filter.Judge) {
+		http.Error(w, "Unknown judge: "+filter.Judge, http.StatusBadRequest)
+		return
+	}
+	if filter.Tag != "" && !activity.ValidTag(filter.Tag) {
+		http.Error(w, "Invalid tag", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("min_rating"); value != "" {
+		minRating, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "min_rating must be a number", http.StatusBadRequest)
+			return
+		}
+		filter.MinRating = &minRating
+	}
+
+	activities := uc.ActivityModel.FilterActivitiesByCreatorID(userID, filter)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(activities)
 }
+
+// GetUserStreak godoc
+// @Summary Get user streak
+// @Description Get the user's current and longest run of consecutive days with activities. Days start in the user's time zone, and frozen days keep a streak going without counting towards it
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} responses.StreakResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/{id}/streak [get]
+func (uc *UserController) GetUserStreak(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid user id: %v", err)
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	u, exists := uc.Model.GetUserByID(userID)
+	if !exists {
+		log.Printf("User not found: id=%d", userID)
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	today := activity.Today(time.Now(), u.Location())
+	streak := activity.ComputeStreak(uc.ActivityModel.GetActiveDays(userID), frozenDays(uc.Model.GetStreakFreezes(userID)), today)
+
+	response := map[string]interface{}{
+		"user_id":         userID,
+		"timezone":        u.Location().String(),
+		"today":           today,
+		"current_streak":  streak.Current,
+		"longest_streak":  streak.Longest,
+		"last_active_day": streak.LastActiveDay,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetUserCalendar godoc
+// @Summary Get user activity calendar
+// @Description Get the number of activities per day between from and to (YYYY-MM-DD, inclusive, at most 366 days). Defaults to the year up to today in the user's time zone
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} responses.CalendarResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/{id}/calendar [get]
+func (uc *UserController) GetUserCalendar(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		log.Printf("Invalid user id: %v", err)
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	u, exists := uc.Model.GetUserByID(userID)
+	if !exists {
+		log.Printf("User not found: id=%d", userID)
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	to := activity.Today(time.Now(), u.Location())
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse(activity.DayLayout, value); err != nil {
+			http.Error(w, "Invalid to: expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	from := to.AddDate(0, 0, -(maxCalendarDays - 1))
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse(activity.DayLayout, value); err != nil {
+			http.Error(w, "Invalid from: expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if from.After(to) {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) >= maxCalendarDays*24*time.Hour {
+		http.Error(w, "The calendar can cover at most 366 days", http.StatusBadRequest)
+		return
+	}
+
+	counts := uc.ActivityModel.CountActivitiesByDay(userID, from, to)
+	days := activity.BuildCalendar(counts, frozenDays(uc.Model.GetStreakFreezes(userID)), from, to)
+
+	response := map[string]interface{}{
+		"user_id":  userID,
+		"timezone": u.Location().String(),
+		"from":     from,
+		"to":       to,
+		"days":     days,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetTimezone godoc
+// @Summary Set time zone
+// @Description Set the authenticated user's IANA time zone, e.g. America/Sao_Paulo. Streaks and calendars count days in this zone
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body responses.TimezoneRequest true "Time zone"
+// @Success 200 {object} user.User
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/timezone [put]
+func (uc *UserController) SetTimezone(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Timezone string `json:"timezone"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode timezone payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// "Local" would mean the server's zone, which is not something a user can pick
+	if _, err := time.LoadLocation(request.Timezone); err != nil || request.Timezone == "" || request.Timezone == "Local" {
+		http.Error(w, "Unknown time zone: "+request.Timezone, http.StatusBadRequest)
+		return
+	}
+
+	updated, ok := uc.Model.SetTimezone(requester.ID, request.Timezone)
+	if !ok {
+		log.Printf("Failed to set timezone for user_id=%d", requester.ID)
+		http.Error(w, "Failed to set time zone", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// AddStreakFreeze godoc
+// @Summary Freeze a day
+// @Description Mark a day off so it does not break the authenticated user's streak. Days can be frozen from yesterday on, at most 2 per calendar month
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body responses.StreakFreezeRequest true "Day to freeze"
+// @Success 201 {object} user.StreakFreeze
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /users/me/streak/freezes [post]
+func (uc *UserController) AddStreakFreeze(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		Date string `json:"date"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("Failed to decode streak freeze payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	day, err := time.Parse(activity.DayLayout, request.Date)
+	if err != nil {
+		http.Error(w, "Invalid date: expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	// Yesterday is allowed so a missed day can still be covered before it ends the streak
+	today := activity.Today(time.Now(), requester.Location())
+	if day.Before(today.AddDate(0, 0, -1)) {
+		http.Error(w, "Days before yesterday cannot be frozen", http.StatusBadRequest)
+		return
+	}
+
+	used := 0
+	for _, freeze := range uc.Model.GetStreakFreezes(requester.ID) {
+		frozen := activity.Day(freeze.Day)
+		if frozen.Equal(day) {
+			http.Error(w, "Day is already frozen", http.StatusConflict)
+			return
+		}
+		if frozen.Year() == day.Year() && frozen.Month() == day.Month() {
+			used++
+		}
+	}
+	if used >= user.MaxStreakFreezesPerMonth {
+		log.Printf("Streak freeze limit reached: user_id=%d, month=%s", requester.ID, day.Format("2006-01"))
+		http.Error(w, "No freeze days left for this month", http.StatusConflict)
+		return
+	}
+
+	freeze := user.StreakFreeze{UserID: requester.ID, Day: day, CreatedAt: time.Now()}
+	if !uc.Model.AddStreakFreeze(freeze) {
+		log.Printf("Failed to freeze day: user_id=%d, day=%s", requester.ID, request.Date)
+		http.Error(w, "Failed to freeze day", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(freeze)
+}
+
+// DeleteStreakFreeze godoc
+// @Summary Unfreeze a day
+// @Description Remove a frozen day of the authenticated user that has not passed yet
+// @Tags users
+// @Security BearerAuth
+// @Param date path string true "Frozen day (YYYY-MM-DD)"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /users/me/streak/freezes/{date} [delete]
+func (uc *UserController) DeleteStreakFreeze(w http.ResponseWriter, r *http.Request) {
+	requester, ok := auth.CurrentUser(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	day, err := time.Parse(activity.DayLayout, mux.Vars(r)["date"])
+	if err != nil {
+		http.Error(w, "Invalid date: expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	// Past freezes are part of the streak history; removing one could only break it after the fact
+	if day.Before(activity.Today(time.Now(), requester.Location())) {
+		http.Error(w, "Past freeze days cannot be removed", http.StatusBadRequest)
+		return
+	}
+
+	if !uc.Model.DeleteStreakFreeze(requester.ID, day) {
+		log.Printf("Streak freeze not found: user_id=%d, day=%s", requester.ID, day.Format(activity.DayLayout))
+		http.Error(w, "Freeze day not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func frozenDays(freezes []user.StreakFreeze) []time.Time {
+	days := make([]time.Time, len(freezes))
+	for i, freeze := range freezes {
+		days[i] = freeze.Day
+	}
+	return days
+}
@@ -20,7 +20,12 @@ const docTemplate = `{
     "paths": {
         "/activities": {
             "post": {
-                "description": "Create a new activity with title, date, and optional image/description",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new activity with title, date, and optional image/description, owned by the requester. The solved problem can be described with judge, problem id and URL, rating or difficulty, tags, verdict, language and time spent. A problem URL from a known judge fills in the judge and problem id and is stored in canonical form, with a problem_key shared by all activities on that problem, and linked to the problem catalog through catalog_problem_id. It can be posted right away to groups the requester is a member of",
                 "consumes": [
                     "application/json"
                 ],
@@ -54,6 +59,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -96,7 +125,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new comment on an activity",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new comment on an activity as the requester",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,6 +172,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -147,24 +187,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/activities/{id}": {
+        "/activities/{activity_id}/reactions": {
             "get": {
-                "description": "Get activity information by activity ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get how many reactions of each kind an activity has",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "activities"
+                    "reactions"
                 ],
-                "summary": "Get activity by ID",
+                "summary": "Get reactions of an activity",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Activity ID",
-                        "name": "id",
+                        "name": "activity_id",
                         "in": "path",
                         "required": true
                     }
@@ -173,7 +210,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/activity.Activity"
+                            "$ref": "#/definitions/responses.ReactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
                     "404": {
@@ -183,42 +226,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/activities/{activity_id}/reactions/{kind}": {
             "put": {
-                "description": "Update activity information (title cannot be updated)",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Leave a reaction of the given kind (like, fire, clap or mind_blown) on an activity. Reacting twice with the same kind has no further effect",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "activities"
+                    "reactions"
                 ],
-                "summary": "Update an existing activity",
+                "summary": "React to an activity",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Activity ID",
-                        "name": "id",
+                        "name": "activity_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Activity update data",
-                        "name": "activity",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.ActivityUpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "Reaction kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/activity.Activity"
+                            "$ref": "#/definitions/responses.ReactionsResponse"
                         }
                     },
                     "400": {
@@ -227,6 +272,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -236,57 +287,41 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an activity (only creator can delete)\nDelete an activity (only creator can delete)",
-                "consumes": [
-                    "application/json",
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Take back the requester's reaction of the given kind on an activity",
                 "produces": [
-                    "application/json",
                     "application/json"
                 ],
                 "tags": [
-                    "activities",
-                    "activities"
+                    "reactions"
                 ],
-                "summary": "Delete an activity",
+                "summary": "Remove a reaction",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Activity ID",
-                        "name": "id",
+                        "name": "activity_id",
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "description": "Delete request with creator_id",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.ActivityDeleteRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "Activity ID",
-                        "name": "id",
+                        "description": "Reaction kind",
+                        "name": "kind",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Delete request with creator_id",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.ActivityDeleteRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ReactionsResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -294,8 +329,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
@@ -309,9 +344,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/comments/{comment_id}": {
-            "delete": {
-                "description": "Delete a comment (only comment author or activity creator can delete)",
+        "/activities/{id}": {
+            "get": {
+                "description": "Get activity information by activity ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -319,44 +354,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "activities"
                 ],
-                "summary": "Delete a comment",
+                "summary": "Get activity by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comment ID",
-                        "name": "comment_id",
+                        "description": "Activity ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Delete request with requester_id",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.CommentDeleteRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.CommentDeleteResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/responses.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/responses.ErrorResponse"
+                            "$ref": "#/definitions/activity.Activity"
                         }
                     },
                     "404": {
@@ -366,11 +380,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/groups": {
-            "post": {
-                "description": "Create a new group with name, end date, and optional image/description",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update activity information, including the problem metadata (title cannot be updated, only creator can update)",
                 "consumes": [
                     "application/json"
                 ],
@@ -378,25 +395,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "activities"
                 ],
-                "summary": "Create a new group",
+                "summary": "Update an existing activity",
                 "parameters": [
                     {
-                        "description": "Group creation data",
-                        "name": "group",
+                        "type": "string",
+                        "description": "Activity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Activity update data",
+                        "name": "activity",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/responses.GroupCreateRequest"
+                            "$ref": "#/definitions/responses.ActivityUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/group.Group"
+                            "$ref": "#/definitions/activity.Activity"
                         }
                     },
                     "400": {
@@ -404,13 +428,34 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/groups/{id}": {
-            "get": {
-                "description": "Get group information (members only)",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an activity (only creator can delete)",
                 "consumes": [
                     "application/json"
                 ],
@@ -418,31 +463,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "activities"
                 ],
-                "summary": "Get group details",
+                "summary": "Delete an activity",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Group ID",
+                        "type": "string",
+                        "description": "Activity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Requester User ID",
-                        "name": "requester_id",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/group.Group"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -450,6 +485,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -463,42 +504,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update group information (name cannot be updated)",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/activities/{id}/groups": {
+            "get": {
+                "description": "List the ids of the groups an activity is posted to",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "activities"
                 ],
-                "summary": "Update an existing group",
+                "summary": "Get activity groups",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Activity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Group update data",
-                        "name": "group",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.GroupUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/group.Group"
+                            "$ref": "#/definitions/responses.ActivityGroupsResponse"
                         }
                     },
                     "400": {
@@ -515,8 +546,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a group (only creator can delete)",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Post an existing activity to another group (only the activity creator, and only to groups they are a member of)",
                 "consumes": [
                     "application/json"
                 ],
@@ -524,30 +560,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "activities"
                 ],
-                "summary": "Delete a group",
+                "summary": "Post activity to group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Activity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Delete request with creator_id",
+                        "description": "Group to post to",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/responses.GroupDeleteRequest"
+                            "$ref": "#/definitions/responses.ActivityGroupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ActivityGroupResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -555,6 +594,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -566,48 +611,47 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/groups/{id}/activities": {
-            "get": {
-                "description": "Get all activities for a group (members only)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+        "/activities/{id}/groups/{group_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Take an activity out of a group. The activity itself is kept. Allowed for the activity creator and for group moderators and above",
                 "tags": [
-                    "groups"
+                    "activities"
                 ],
-                "summary": "Get group activities",
+                "summary": "Remove activity from group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Group ID",
+                        "type": "string",
+                        "description": "Activity ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Requester User ID",
-                        "name": "requester_id",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/activity.Activity"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -615,6 +659,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -630,48 +680,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups/{id}/invites": {
+        "/auth/providers": {
             "get": {
-                "description": "Get all invites for a group",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the names of the configured external login providers",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
-                ],
-                "summary": "Get group invites",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "authentication"
                 ],
+                "summary": "List login providers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/group.GroupInvite"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/responses.ErrorResponse"
+                            "$ref": "#/definitions/responses.ProvidersResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "description": "Create an invite link for the group (only group creator can create invites)",
+                "description": "Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; presenting a used one revokes its session",
                 "consumes": [
                     "application/json"
                 ],
@@ -679,42 +710,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "authentication"
                 ],
-                "summary": "Create group invite link",
+                "summary": "Refresh access token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Create invite request",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/responses.CreateInviteRequest"
+                            "$ref": "#/definitions/responses.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/group.GroupInvite"
+                            "$ref": "#/definitions/responses.TokenResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
@@ -722,31 +752,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups/{id}/members": {
+        "/auth/{provider}/callback": {
             "get": {
-                "description": "Get all members of a group (members only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Handle the provider's redirect: exchange the code, then sign in the linked user. Unlinked identities with a verified email are linked to the account with the same email if that account verified it too, or a new account is created. An existing account that has not verified the email must sign in with its password and verify it first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "authentication"
                 ],
-                "summary": "Get group members",
+                "summary": "Finish external login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Group ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Provider name, e.g. github or google",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Requester User ID",
-                        "name": "requester_id",
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State from the login redirect",
+                        "name": "state",
                         "in": "query",
                         "required": true
                     }
@@ -755,7 +789,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.GroupMembersResponse"
+                            "$ref": "#/definitions/responses.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LoginChallengeResponse"
                         }
                     },
                     "400": {
@@ -764,6 +804,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -775,51 +821,41 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Add a user to a group",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/{provider}/login": {
+            "get": {
+                "description": "Redirect to the provider's consent page. The state is bound to the browser with a cookie and the code exchange is protected with PKCE",
                 "tags": [
-                    "groups"
+                    "authentication"
                 ],
-                "summary": "Add user to group",
+                "summary": "Start external login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Provider name, e.g. github or google",
+                        "name": "provider",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Add user request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.AddUserToGroupRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/responses.AddUserToGroupResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/responses.ErrorResponse"
-                        }
+                    "302": {
+                        "description": "Redirect to the provider"
                     },
                     "404": {
                         "description": "Not Found",
@@ -827,16 +863,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/comments/{comment_id}": {
             "delete": {
-                "description": "Remove a user from a group (only group creator or the user themselves can remove)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a comment (only comment author, activity creator or a moderator of a group the activity is posted to can delete)",
                 "consumes": [
                     "application/json"
                 ],
@@ -844,32 +887,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "comments"
                 ],
-                "summary": "Remove user from group",
+                "summary": "Delete a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
-                        "name": "id",
+                        "description": "Comment ID",
+                        "name": "comment_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Remove user request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.RemoveUserFromGroupRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.SuccessResponse"
+                            "$ref": "#/definitions/responses.CommentDeleteResponse"
                         }
                     },
                     "400": {
@@ -878,6 +912,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -893,9 +933,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/groups/{id}/members/nickname": {
-            "put": {
-                "description": "Set or update a user's nickname in a group",
+        "/groups": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new group with name, end date, and optional image/description. The requester becomes its creator and first member.",
                 "consumes": [
                     "application/json"
                 ],
@@ -905,30 +950,23 @@ const docTemplate = `{
                 "tags": [
                     "groups"
                 ],
-                "summary": "Set user nickname in group",
+                "summary": "Create a new group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Group ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Set nickname request",
-                        "name": "request",
+                        "description": "Group creation data",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/responses.SetNicknameRequest"
+                            "$ref": "#/definitions/responses.GroupCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/responses.SuccessResponse"
+                            "$ref": "#/definitions/group.Group"
                         }
                     },
                     "400": {
@@ -937,22 +975,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/responses.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove a user's nickname in a group",
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get group information (members only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -962,30 +1001,33 @@ const docTemplate = `{
                 "tags": [
                     "groups"
                 ],
-                "summary": "Delete user nickname in group",
+                "summary": "Get group details",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Delete nickname request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.DeleteNicknameRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.SuccessResponse"
+                            "$ref": "#/definitions/group.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
                     "403": {
@@ -1001,11 +1043,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/invites/{invite_code}/deactivate": {
-            "delete": {
-                "description": "Deactivate an invite link (only group creator can deactivate)",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update group information (name cannot be updated, only the owner can update)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1015,22 +1060,22 @@ const docTemplate = `{
                 "tags": [
                     "groups"
                 ],
-                "summary": "Deactivate group invite",
+                "summary": "Update an existing group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Invite Code",
-                        "name": "invite_code",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Deactivate invite request",
-                        "name": "request",
+                        "description": "Group update data",
+                        "name": "group",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/responses.DeactivateInviteRequest"
+                            "$ref": "#/definitions/responses.GroupUpdateRequest"
                         }
                     }
                 ],
@@ -1038,7 +1083,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.SuccessResponse"
+                            "$ref": "#/definitions/group.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
                     "403": {
@@ -1054,11 +1111,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/invites/{invite_code}/join": {
-            "post": {
-                "description": "Join a group using an invite code",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a group (only the owner can delete)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1068,40 +1128,40 @@ const docTemplate = `{
                 "tags": [
                     "groups"
                 ],
-                "summary": "Join group by invite code",
+                "summary": "Delete a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Invite Code",
-                        "name": "invite_code",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Join group request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.JoinGroupRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/responses.SuccessResponse"
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
@@ -1109,9 +1169,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Authenticate user with email and password, returns user data and token",
+        "/groups/{id}/activities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the activities posted to a group and dated between its start and end dates, newest first, with author and comment count (members only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1119,25 +1184,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "groups"
                 ],
-                "summary": "Authenticate user",
+                "summary": "Get group activities",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.LoginResponse"
+                            "$ref": "#/definitions/responses.GroupActivitiesResponse"
                         }
                     },
                     "400": {
@@ -1151,49 +1214,15 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/users": {
-            "post": {
-                "description": "Create a new user account with email, name, and password",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Create a new user",
-                "parameters": [
-                    {
-                        "description": "User creation data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/responses.UserCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/user.User"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
@@ -1201,9 +1230,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/groups/{id}/invites": {
             "get": {
-                "description": "Get user information by user ID (password field excluded)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all active invites for a group (members only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1211,13 +1245,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get group invites",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1227,7 +1261,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/group.GroupInvite"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
                     "404": {
@@ -1237,11 +1286,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}/activities": {
-            "get": {
-                "description": "Get all activities created by a specific user",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an invite link for the group (only the owner and admins can create invites)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1249,26 +1301,242 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "Get user activities",
+                "summary": "Create group invite link",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create invite request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreateInviteRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/activity.Activity"
-                            }
+                            "$ref": "#/definitions/group.GroupInvite"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rank the current members by their activities posted to the group between its start and end dates that solved their problem (accepted or without a verdict), using the group's scoring mode unless another is given. Members show up under their group nickname when set; equal scores share a rank (members only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scoring mode: activities, active_days or difficulty",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LeaderboardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all members of a group (members only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.GroupMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a user to a group (only the owner and admins can add members directly)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Add user to group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Add user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.AddUserToGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.AddUserToGroupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
                         }
                     },
                     "404": {
@@ -1276,190 +1544,3728 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/responses.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user from a group (the user themselves, or the owner or an admin who outranks them). The owner cannot be removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Remove user from group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remove user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.RemoveUserFromGroupRequest"
+                        }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members/nickname": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set or update a user's nickname in a group (user_id defaults to the requester)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Set user nickname in group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set nickname request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.SetNicknameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user's nickname in a group (user_id defaults to the requester)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete user nickname in group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delete nickname request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/responses.DeleteNicknameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promote or demote a group member. The owner can appoint admins, moderators and members; admins can only move members below them between moderator and member. The owner role cannot be assigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Set member role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set role request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.SetMemberRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SetMemberRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/problems": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the problems the group's current members solved between the group's start and end dates, with how many of them solved each and who solved it first, most solved first. Members show up under their group nickname when set (members only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "problems"
+                ],
+                "summary": "Get problems solved in a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.GroupProblemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the group's progress towards its goals, counting the posted activities that solved their problem (accepted or without a verdict): overall and per-member completion, how much of the date window has passed by the requester's time zone and, for each goal, when it will be reached at the current pace",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group goal progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.GroupProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Offer ownership of the group to another member (owner only). The member becomes owner once they accept; a newer nomination replaces a pending one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Nominate a new owner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nominee",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TransferOwnershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/group.OwnershipTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The owner cancels their pending nomination, or the nominee declines it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Cancel or decline group ownership transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/group.OwnershipTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/transfer/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept a pending ownership nomination. The nominee becomes the owner and the previous owner stays on as an admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Accept group ownership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/group.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List all ownership transfers of a group, newest first (members only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group ownership history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.OwnershipTransfersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{invite_code}/deactivate": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivate an invite link (only its creator, the group owner or admins can deactivate)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Deactivate group invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite Code",
+                        "name": "invite_code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{invite_code}/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Join a group as the authenticated user using an invite code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Join group by invite code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite Code",
+                        "name": "invite_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Join group request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/responses.JoinGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate user with email and password, starts a session and returns user data, a signed access token and a refresh token. Users with two-factor login get a challenge for /login/2fa instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Authenticate user",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LoginChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/2fa": {
+            "post": {
+                "description": "Exchange the challenge returned by /login and a TOTP or recovery code for a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Complete two-step login",
+                "parameters": [
+                    {
+                        "description": "Challenge and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TwoFactorLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke the session of the current access token. The access token and its refresh token stop working immediately",
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Log out",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Mail a single-use password reset link to the address. The response is the same whether or not an account exists for it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Set a new password with a token from a password reset mail. All sessions of the account are logged out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/problems/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a problem from the catalog with the requester and members of the requester's groups who solved it, in the order they first solved it. Activities with an accepted verdict or without a verdict count as solves",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "problems"
+                ],
+                "summary": "Get a problem",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Problem ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ProblemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "description": "Create a new user account with email, name, and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User creation data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/2fa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a new TOTP secret for the authenticated user. Two-factor login is only turned on after the first code is confirmed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Start TOTP enrollment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.TOTPEnrollResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn off two-factor login. Requires a current TOTP code or an unused recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Disable TOTP",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TOTPCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/2fa/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn on two-factor login with a code from the authenticator app. Returns recovery codes, which are only shown once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Confirm TOTP enrollment",
+                "parameters": [
+                    {
+                        "description": "Current TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TOTPCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/accounts/{judge}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Link the authenticated user's handle on an online judge, replacing the handle linked before; relinking the same handle only changes the groups and keeps its import position. Accepted submissions made from now on are imported as activities in the background, see /users/me/sync-status and posted to the given groups. Codeforces, AtCoder and LeetCode accounts can be linked; AtCoder submissions come from AtCoder Problems, with its difficulty estimates as ratings, and LeetCode only lists a user's 20 most recent accepted submissions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Link a judge account",
+                "parameters": [
+                    {
+                        "enum": [
+                            "codeforces",
+                            "atcoder",
+                            "leetcode"
+                        ],
+                        "type": "string",
+                        "description": "Judge",
+                        "name": "judge",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Handle and the groups to post imported activities to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.LinkAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/linkedaccount.LinkedAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop importing from the authenticated user's account on an online judge. Activities imported so far are kept",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unlink a judge account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Judge",
+                        "name": "judge",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/feed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the activities posted to any of the requester's groups, newest first, with their author, groups, comment count and reactions. Pass next_cursor back as cursor to get the following page",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get home feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.FeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suggest rated catalog problems the requester has not solved, near the rating estimated from their hardest solves. Problems slightly above that rating, with tags the requester solved few problems with, and solved by many of the requester's groupmates come first. The same seed gives the same recommendations; without one, the recommendations change daily",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "problems"
+                ],
+                "summary": "Get recommended problems",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations, 1 to 50 (default 10)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tags; only problems with at least one of them are recommended, e.g. dp,greedy",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed for the random part of the scores",
+                        "name": "seed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.RecommendationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the active sessions of the authenticated user. The session of the current access token is marked as current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log out one of the authenticated user's sessions, e.g. a lost device",
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/streak/freezes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a day off so it does not break the authenticated user's streak. Days can be frozen from yesterday on, at most 2 per calendar month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Freeze a day",
+                "parameters": [
+                    {
+                        "description": "Day to freeze",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.StreakFreezeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/user.StreakFreeze"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/streak/freezes/{date}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a frozen day of the authenticated user that has not passed yet",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unfreeze a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Frozen day (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import the authenticated user's linked judge accounts right away instead of waiting for their next scheduled import. Accounts imported within the last minute are left alone. The import runs in the background",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Sync linked judge accounts now",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SyncStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sync-status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show, per linked judge account of the authenticated user, when it was last imported successfully, the last error, how many imports failed in a row and when the next import is due",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get judge sync status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SyncStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/timezone": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the authenticated user's IANA time zone, e.g. America/Sao_Paulo. Streaks and calendars count days in this zone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set time zone",
+                "parameters": [
+                    {
+                        "description": "Time zone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TimezoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the authenticated user's personal access tokens. The secrets themselves are never returned again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "List personal access tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.TokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a named token with the given scopes for scripts and plugins. The token is only shown in this response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Create personal access token",
+                "parameters": [
+                    {
+                        "description": "Token name, scopes and optional expiry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/responses.TokenCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.TokenCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one of the authenticated user's personal access tokens",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Delete personal access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/verify-email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Without a token, mail a verification link to the authenticated user's address. With a token from that mail, mark the address as verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "description": "Verification token",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/responses.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get user information by user ID (password field excluded)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/accounts": {
+            "get": {
+                "description": "List the online judge handles a user has linked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List linked judge accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.LinkedAccountsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activities": {
+            "get": {
+                "description": "Get the activities created by a specific user, optionally only those from one judge, with one tag or rated at least min_rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user activities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Online judge, e.g. codeforces",
+                        "name": "judge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Problem tag, e.g. dp",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum problem rating",
+                        "name": "min_rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/activity.Activity"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/calendar": {
+            "get": {
+                "description": "Get the number of activities per day between from and to (YYYY-MM-DD, inclusive, at most 366 days). Defaults to the year up to today in the user's time zone",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user activity calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CalendarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/streak": {
+            "get": {
+                "description": "Get the user's current and longest run of consecutive days with activities. Days start in the user's time zone, and frozen days keep a streak going without counting towards it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user streak",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.StreakResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "activity.Activity": {
+            "type": "object",
+            "properties": {
+                "activity_image": {
+                    "type": "string"
+                },
+                "catalog_problem_id": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge": {
+                    "description": "The solved problem, when known. Rating is its difficulty on the Codeforces scale (800 to 3500);\nDifficulty is the label of judges that grade problems as easy, medium or hard. ProblemKey is set by\nthe server and identifies the problem whichever URL variant was used, e.g. \"codeforces:1352A\".\nSubmissionID is the judge's ID of the submission an imported activity was created from, and\nCatalogProblemID the problem catalog's entry for the problem, also set by the server",
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "problem_id": {
+                    "type": "string"
+                },
+                "problem_key": {
+                    "type": "string"
+                },
+                "problem_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_spent_minutes": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "activity.CalendarDay": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "frozen": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "comment.Comment": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "group.ActivityAuthor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.FeedGroup": {
+            "type": "object",
+            "properties": {
+                "author_nickname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.FeedItem": {
+            "type": "object",
+            "properties": {
+                "activity_image": {
+                    "type": "string"
+                },
+                "author": {
+                    "$ref": "#/definitions/group.ActivityAuthor"
+                },
+                "catalog_problem_id": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.FeedGroup"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge": {
+                    "description": "The solved problem, when known. Rating is its difficulty on the Codeforces scale (800 to 3500);\nDifficulty is the label of judges that grade problems as easy, medium or hard. ProblemKey is set by\nthe server and identifies the problem whichever URL variant was used, e.g. \"codeforces:1352A\".\nSubmissionID is the judge's ID of the submission an imported activity was created from, and\nCatalogProblemID the problem catalog's entry for the problem, also set by the server",
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "problem_id": {
+                    "type": "string"
+                },
+                "problem_key": {
+                    "type": "string"
+                },
+                "problem_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "reactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reaction.Summary"
+                    }
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_spent_minutes": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.GoalProgress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "description": "Done and Required count the goal's units: activities, or active days for GoalActiveDaysPerWeek,\ncounted week by week. For per-member goals they are summed over members, counting each member up\nto their own target.",
+                    "type": "integer"
+                },
+                "goal": {
+                    "type": "string"
+                },
+                "on_track": {
+                    "type": "boolean"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "projected_finish": {
+                    "description": "ProjectedFinish is when the goal is reached at the group's pace so far; nil once it is reached or\nwhile there is no pace yet",
+                    "type": "string"
+                },
+                "required": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.Group": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "goal_active_days_per_week": {
+                    "type": "integer"
+                },
+                "goal_activities_per_member": {
+                    "type": "integer"
+                },
+                "goal_total_activities": {
+                    "description": "Goals the group works towards over its date window, see the Goal constants; nil means not set",
+                    "type": "integer"
+                },
+                "group_image": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "require_verified_email": {
+                    "description": "RequireVerifiedEmail keeps users without a verified email address from joining through invites",
+                    "type": "boolean"
+                },
+                "scoring_mode": {
+                    "description": "ScoringMode decides how the leaderboard ranks members, see the Scoring constants",
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.GroupInvite": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "invite_code": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "group.GroupMember": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "integer"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "active_days": {
+                    "type": "integer"
+                },
+                "activity_count": {
+                    "type": "integer"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.MemberProgress": {
+            "type": "object",
+            "properties": {
+                "active_days": {
+                    "type": "integer"
+                },
+                "activity_count": {
+                    "type": "integer"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "goals": {
+                    "description": "Goals maps each goal to the member's completion; for GoalTotalActivities that is against an even share",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.OwnershipTransfer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "from_user_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "group.PostedActivity": {
+            "type": "object",
+            "properties": {
+                "activity_image": {
+                    "type": "string"
+                },
+                "author": {
+                    "$ref": "#/definitions/group.ActivityAuthor"
+                },
+                "catalog_problem_id": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge": {
+                    "description": "The solved problem, when known. Rating is its difficulty on the Codeforces scale (800 to 3500);\nDifficulty is the label of judges that grade problems as easy, medium or hard. ProblemKey is set by\nthe server and identifies the problem whichever URL variant was used, e.g. \"codeforces:1352A\".\nSubmissionID is the judge's ID of the submission an imported activity was created from, and\nCatalogProblemID the problem catalog's entry for the problem, also set by the server",
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "problem_id": {
+                    "type": "string"
+                },
+                "problem_key": {
+                    "type": "string"
+                },
+                "problem_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_spent_minutes": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "group.Progress": {
+            "type": "object",
+            "properties": {
+                "elapsed_days": {
+                    "type": "integer"
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.GoalProgress"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.MemberProgress"
+                    }
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "time_percent": {
+                    "type": "number"
+                },
+                "total_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "linkedaccount.LinkedAccount": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "group_ids": {
+                    "description": "GroupIDs are the groups imported activities are posted to",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "handle": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge": {
+                    "type": "string"
+                },
+                "last_imported_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "linkedaccount.SyncStatus": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "handle": {
+                    "type": "string"
+                },
+                "judge": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "next_sync_at": {
+                    "type": "string"
+                },
+                "syncing": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "pat.PersonalAccessToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "problem.GroupProblem": {
+            "type": "object",
+            "properties": {
+                "first_solver": {
+                    "$ref": "#/definitions/problem.Solver"
+                },
+                "problem": {
+                    "$ref": "#/definitions/problem.Problem"
+                },
+                "solve_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "judge": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Recommendation": {
+            "type": "object",
+            "properties": {
+                "groupmate_solves": {
+                    "type": "integer"
+                },
+                "problem": {
+                    "$ref": "#/definitions/problem.Problem"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "problem.Solver": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "solved_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reaction.Summary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reacted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "responses.ActivityCreateRequest": {
+            "type": "object",
+            "properties": {
+                "activity_image": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-12-31"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A competitive programming contest"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "judge": {
+                    "type": "string",
+                    "example": "codeforces"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "C++17"
+                },
+                "problem_id": {
+                    "type": "string",
+                    "example": "1850A"
+                },
+                "problem_url": {
+                    "type": "string",
+                    "example": "https://codeforces.com/problemset/problem/1850/A"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 1600
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dp",
+                        "greedy"
+                    ]
+                },
+                "time_spent_minutes": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Algorithm Contest"
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "accepted"
+                }
+            }
+        },
+        "responses.ActivityGroupRequest": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
+                }
+            }
+        },
+        "responses.ActivityGroupResponse": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "string",
+                    "example": "activity123"
+                },
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Activity posted to group successfully"
+                }
+            }
+        },
+        "responses.ActivityGroupsResponse": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "string",
+                    "example": "activity123"
+                },
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "responses.ActivityUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "activity_image": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-12-31"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Updated description"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "judge": {
+                    "type": "string",
+                    "example": "codeforces"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "C++17"
+                },
+                "problem_id": {
+                    "type": "string",
+                    "example": "1850A"
+                },
+                "problem_url": {
+                    "type": "string",
+                    "example": "https://codeforces.com/problemset/problem/1850/A"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 1600
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dp",
+                        "greedy"
+                    ]
+                },
+                "time_spent_minutes": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "verdict": {
+                    "type": "string",
+                    "example": "accepted"
+                }
+            }
+        },
+        "responses.AddUserToGroupRequest": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "responses.AddUserToGroupResponse": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "User added to group successfully"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "responses.CalendarResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/activity.CalendarDay"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-31T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "responses.CommentCreateRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Great activity!"
+                }
+            }
+        },
+        "responses.CommentDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "comment_id": {
+                    "type": "string",
+                    "example": "comment123"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Comment deleted successfully"
+                }
+            }
+        },
+        "responses.CommentsResponse": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "string",
+                    "example": "activity123"
+                },
+                "comment_count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/comment.Comment"
+                    }
+                }
+            }
+        },
+        "responses.CreateInviteRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
+                }
+            }
+        },
+        "responses.DeleteNicknameRequest": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "responses.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request"
+                }
+            }
+        },
+        "responses.FeedResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.FeedItem"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MjAyNS0wNy0wMS80Mg"
+                }
+            }
+        },
+        "responses.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "responses.GroupActivitiesResponse": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.PostedActivity"
+                    }
+                },
+                "activity_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
                 }
             }
-        }
-    },
-    "definitions": {
-        "activity.Activity": {
+        },
+        "responses.GroupCreateRequest": {
             "type": "object",
             "properties": {
-                "activity_image": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "example": "A group for studying algorithms"
                 },
-                "createdAt": {
-                    "type": "string"
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12-31"
                 },
-                "creator_id": {
-                    "type": "integer"
+                "goal_active_days_per_week": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "date": {
-                    "type": "string"
+                "goal_activities_per_member": {
+                    "type": "integer",
+                    "example": 50
                 },
-                "description": {
-                    "type": "string"
+                "goal_total_activities": {
+                    "type": "integer",
+                    "example": 300
                 },
-                "id": {
-                    "type": "integer"
+                "group_image": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
                 },
-                "title": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "Study Group"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "require_verified_email": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "scoring_mode": {
+                    "type": "string",
+                    "example": "activities"
                 }
             }
         },
-        "comment.Comment": {
+        "responses.GroupMembersResponse": {
             "type": "object",
             "properties": {
-                "activity_id": {
-                    "type": "integer"
-                },
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
                 },
-                "id": {
-                    "type": "integer"
+                "member_count": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "updated_at": {
-                    "type": "string"
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.GroupMember"
+                    }
+                }
+            }
+        },
+        "responses.GroupProblemsResponse": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "user_id": {
-                    "type": "integer"
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problem.GroupProblem"
+                    }
                 }
             }
         },
-        "group.Group": {
+        "responses.GroupProgressResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12-31T00:00:00Z"
                 },
-                "creator_id": {
-                    "type": "integer"
+                "group_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "progress": {
+                    "$ref": "#/definitions/group.Progress"
                 },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "today": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                }
+            }
+        },
+        "responses.GroupUpdateRequest": {
+            "type": "object",
+            "properties": {
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Updated description"
                 },
                 "end_date": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2025-12-31"
+                },
+                "goal_active_days_per_week": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "goal_activities_per_member": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "goal_total_activities": {
+                    "description": "Set a goal to null to remove it",
+                    "type": "integer",
+                    "example": 300
                 },
                 "group_image": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
                 },
-                "id": {
-                    "type": "integer"
+                "require_verified_email": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "name": {
-                    "type": "string"
+                "scoring_mode": {
+                    "type": "string",
+                    "example": "active_days"
+                }
+            }
+        },
+        "responses.JoinGroupRequest": {
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "type": "string",
+                    "example": "Cool Coder"
+                }
+            }
+        },
+        "responses.LeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-12-31T00:00:00Z"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.LeaderboardEntry"
+                    }
+                },
+                "group_id": {
+                    "type": "string",
+                    "example": "group123"
+                },
+                "scoring_mode": {
+                    "type": "string",
+                    "example": "active_days"
                 },
                 "start_date": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                }
+            }
+        },
+        "responses.LinkAccountRequest": {
+            "type": "object",
+            "properties": {
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
                 },
-                "updated_at": {
-                    "type": "string"
+                "handle": {
+                    "type": "string",
+                    "example": "tourist"
                 }
             }
         },
-        "group.GroupInvite": {
+        "responses.LinkedAccountsResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/linkedaccount.LinkedAccount"
+                    }
                 },
-                "created_by": {
-                    "type": "integer"
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "responses.LoginChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
                 "expires_at": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
+                },
+                "two_factor_required": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "responses.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "responses.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
                 },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                },
+                "user": {
+                    "$ref": "#/definitions/user.User"
+                }
+            }
+        },
+        "responses.OwnershipTransfersResponse": {
+            "type": "object",
+            "properties": {
                 "group_id": {
-                    "type": "integer"
+                    "type": "string",
+                    "example": "group123"
                 },
-                "invite_code": {
-                    "type": "string"
+                "transfer_count": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "is_active": {
-                    "type": "boolean"
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/group.OwnershipTransfer"
+                    }
+                }
+            }
+        },
+        "responses.ProblemResponse": {
+            "type": "object",
+            "properties": {
+                "first_solver": {
+                    "$ref": "#/definitions/problem.Solver"
+                },
+                "problem": {
+                    "$ref": "#/definitions/problem.Problem"
+                },
+                "solve_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "solvers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problem.Solver"
+                    }
+                }
+            }
+        },
+        "responses.ProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "github",
+                        "google"
+                    ]
+                }
+            }
+        },
+        "responses.ReactionsResponse": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reaction.Summary"
+                    }
                 }
             }
         },
-        "group.GroupMember": {
+        "responses.RecommendationsResponse": {
             "type": "object",
             "properties": {
-                "group_id": {
-                    "type": "integer"
+                "estimated_rating": {
+                    "type": "integer",
+                    "example": 1400
                 },
-                "nickname": {
-                    "type": "string"
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problem.Recommendation"
+                    }
                 },
-                "user_id": {
-                    "type": "integer"
+                "seed": {
+                    "type": "integer",
+                    "example": 20378
                 }
             }
         },
-        "responses.ActivityCreateRequest": {
+        "responses.RecoveryCodesResponse": {
             "type": "object",
             "properties": {
-                "activity_image": {
-                    "type": "string",
-                    "example": "https://example.com/image.jpg"
-                },
-                "date": {
-                    "type": "string",
-                    "example": "2025-12-31"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "A competitive programming contest"
-                },
-                "title": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k3f9a-2mzq7",
+                        "p8x2c-4tn6d"
+                    ]
+                }
+            }
+        },
+        "responses.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
                     "type": "string",
-                    "example": "Algorithm Contest"
+                    "example": "2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
                 }
             }
         },
-        "responses.ActivityDeleteRequest": {
+        "responses.RemoveUserFromGroupRequest": {
             "type": "object",
             "properties": {
-                "creator_id": {
+                "user_id": {
                     "type": "string",
                     "example": "user123"
                 }
             }
         },
-        "responses.ActivityUpdateRequest": {
+        "responses.ResetPasswordRequest": {
             "type": "object",
             "properties": {
-                "activity_image": {
-                    "type": "string",
-                    "example": "https://example.com/image.jpg"
-                },
-                "date": {
+                "password": {
                     "type": "string",
-                    "example": "2025-12-31"
+                    "example": "new-password123"
                 },
-                "description": {
+                "token": {
                     "type": "string",
-                    "example": "Updated description"
+                    "example": "2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
                 }
             }
         },
-        "responses.AddUserToGroupRequest": {
+        "responses.SessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/session.Session"
+                    }
+                }
+            }
+        },
+        "responses.SetMemberRoleRequest": {
             "type": "object",
             "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
+                },
                 "user_id": {
                     "type": "string",
                     "example": "user123"
                 }
             }
         },
-        "responses.AddUserToGroupResponse": {
+        "responses.SetMemberRoleResponse": {
             "type": "object",
             "properties": {
                 "group_id": {
@@ -1468,7 +5274,11 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string",
-                    "example": "User added to group successfully"
+                    "example": "Role updated successfully"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
                 },
                 "user_id": {
                     "type": "string",
@@ -1476,12 +5286,12 @@ const docTemplate = `{
                 }
             }
         },
-        "responses.CommentCreateRequest": {
+        "responses.SetNicknameRequest": {
             "type": "object",
             "properties": {
-                "content": {
+                "nickname": {
                     "type": "string",
-                    "example": "Great activity!"
+                    "example": "Cool Coder"
                 },
                 "user_id": {
                     "type": "string",
@@ -1489,244 +5299,257 @@ const docTemplate = `{
                 }
             }
         },
-        "responses.CommentDeleteRequest": {
+        "responses.StreakFreezeRequest": {
             "type": "object",
             "properties": {
-                "requester_id": {
+                "date": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "2025-07-04"
                 }
             }
         },
-        "responses.CommentDeleteResponse": {
+        "responses.StreakResponse": {
             "type": "object",
             "properties": {
-                "comment_id": {
+                "current_streak": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "last_active_day": {
                     "type": "string",
-                    "example": "comment123"
+                    "example": "2025-07-03T00:00:00Z"
                 },
-                "message": {
+                "longest_streak": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "timezone": {
                     "type": "string",
-                    "example": "Comment deleted successfully"
+                    "example": "America/Sao_Paulo"
+                },
+                "today": {
+                    "type": "string",
+                    "example": "2025-07-03T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "responses.CommentsResponse": {
+        "responses.SuccessResponse": {
             "type": "object",
             "properties": {
-                "activity_id": {
+                "message": {
                     "type": "string",
-                    "example": "activity123"
-                },
-                "comment_count": {
-                    "type": "integer",
-                    "example": 5
-                },
-                "comments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/comment.Comment"
-                    }
+                    "example": "Operation completed successfully"
                 }
             }
         },
-        "responses.CreateInviteRequest": {
+        "responses.SyncStatusResponse": {
             "type": "object",
             "properties": {
-                "creator_id": {
-                    "type": "string",
-                    "example": "user123"
-                },
-                "expires_at": {
-                    "type": "string",
-                    "example": "2025-12-31T23:59:59Z"
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/linkedaccount.SyncStatus"
+                    }
                 }
             }
         },
-        "responses.DeactivateInviteRequest": {
+        "responses.TOTPCodeRequest": {
             "type": "object",
             "properties": {
-                "requester_id": {
+                "code": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "123456"
                 }
             }
         },
-        "responses.DeleteNicknameRequest": {
+        "responses.TOTPEnrollResponse": {
             "type": "object",
             "properties": {
-                "requester_id": {
+                "otpauth_uri": {
                     "type": "string",
-                    "example": "user456"
+                    "example": "otpauth://totp/CODECK:user@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=CODECK"
                 },
-                "user_id": {
+                "qr_code": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "data:image/png;base64,iVBORw0KGgo..."
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
                 }
             }
         },
-        "responses.ErrorResponse": {
+        "responses.TimezoneRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "timezone": {
                     "type": "string",
-                    "example": "Invalid request"
+                    "example": "America/Sao_Paulo"
                 }
             }
         },
-        "responses.GroupCreateRequest": {
+        "responses.TokenCreateRequest": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "A group for studying algorithms"
-                },
-                "end_date": {
-                    "type": "string",
-                    "example": "2025-12-31"
-                },
-                "group_image": {
+                "expires_at": {
                     "type": "string",
-                    "example": "https://example.com/image.jpg"
+                    "example": "2026-12-31T23:59:59Z"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Study Group"
+                    "example": "vim plugin"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "activities:write",
+                        "groups:read"
+                    ]
                 }
             }
         },
-        "responses.GroupDeleteRequest": {
+        "responses.TokenCreateResponse": {
             "type": "object",
             "properties": {
-                "creator_id": {
+                "details": {
+                    "$ref": "#/definitions/pat.PersonalAccessToken"
+                },
+                "token": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "cdk_2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
                 }
             }
         },
-        "responses.GroupMembersResponse": {
+        "responses.TokenResponse": {
             "type": "object",
             "properties": {
-                "group_id": {
+                "expires_at": {
                     "type": "string",
-                    "example": "group123"
+                    "example": "2025-12-31T23:59:59Z"
                 },
-                "member_count": {
-                    "type": "integer",
-                    "example": 3
+                "refresh_token": {
+                    "type": "string",
+                    "example": "2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
                 },
-                "members": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "responses.TokensResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/group.GroupMember"
+                        "$ref": "#/definitions/pat.PersonalAccessToken"
                     }
                 }
             }
         },
-        "responses.GroupUpdateRequest": {
+        "responses.TransferOwnershipRequest": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "Updated description"
-                },
-                "end_date": {
-                    "type": "string",
-                    "example": "2025-12-31"
-                },
-                "group_image": {
+                "user_id": {
                     "type": "string",
-                    "example": "https://example.com/image.jpg"
+                    "example": "user123"
                 }
             }
         },
-        "responses.JoinGroupRequest": {
+        "responses.TwoFactorLoginRequest": {
             "type": "object",
             "properties": {
-                "user_id": {
+                "challenge": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
-        "responses.LoginRequest": {
+        "responses.UserCreateRequest": {
             "type": "object",
             "properties": {
                 "email": {
                     "type": "string",
                     "example": "user@example.com"
                 },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
                 "password": {
                     "type": "string",
                     "example": "password123"
                 }
             }
         },
-        "responses.LoginResponse": {
+        "responses.VerifyEmailRequest": {
             "type": "object",
             "properties": {
                 "token": {
                     "type": "string",
-                    "example": "dummy-jwt-token-1"
-                },
-                "user": {
-                    "$ref": "#/definitions/user.User"
+                    "example": "2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"
                 }
             }
         },
-        "responses.RemoveUserFromGroupRequest": {
+        "session.Session": {
             "type": "object",
             "properties": {
-                "requester_id": {
-                    "type": "string",
-                    "example": "user456"
+                "created_at": {
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "string",
-                    "example": "user123"
-                }
-            }
-        },
-        "responses.SetNicknameRequest": {
-            "type": "object",
-            "properties": {
-                "nickname": {
-                    "type": "string",
-                    "example": "Cool Coder"
+                "current": {
+                    "type": "boolean"
                 },
-                "requester_id": {
-                    "type": "string",
-                    "example": "user456"
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
                 },
                 "user_id": {
-                    "type": "string",
-                    "example": "user123"
-                }
-            }
-        },
-        "responses.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Operation completed successfully"
+                    "type": "integer"
                 }
             }
         },
-        "responses.UserCreateRequest": {
+        "user.StreakFreeze": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "created_at": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "John Doe"
+                "day": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -1739,12 +5562,21 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
+                "timezone": {
+                    "type": "string"
+                },
+                "totp_enabled_at": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -0,0 +1,228 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/models/activity"
+)
+
+// AtCoderProblemsURL is where the AtCoder Problems API and its problem data are served.
+const AtCoderProblemsURL = "https://kenkoooo.com/atcoder"
+
+const (
+	// atcoderPageSize is the most submissions the API returns for one call
+	atcoderPageSize = 500
+	// atcoderMaxPages bounds the calls of a single import, like codeforcesMaxPages
+	atcoderMaxPages = 10
+	// atcoderPageDelay spaces out calls for more pages, as AtCoder Problems asks of its clients
+	atcoderPageDelay = time.Second
+	// atcoderProblemsTTL is how long problem names and difficulties are kept before loading them again
+	atcoderProblemsTTL = 24 * time.Hour
+)
+
+var (
+	atcoderUserID      = regexp.MustCompile(`^[A-Za-z0-9_]{3,16}$`)
+	errAtCoderNotFound = errors.New("not found")
+)
+
+// AtCoderClient reads accepted submissions through the AtCoder Problems API. Its cursor is the
+// submission time, in Unix seconds, to continue from.
+type AtCoderClient struct {
+	baseURL string
+	client  *http.Client
+
+	mu             sync.Mutex
+	problems       map[string]atcoderProblem
+	problemsLoaded time.Time
+}
+
+type atcoderProblem struct {
+	Name       string
+	Difficulty *float64
+}
+
+// NewAtCoderClient calls the API at baseURL, normally AtCoderProblemsURL, through client. Both can be
+// replaced to talk to a stub server.
+func NewAtCoderClient(baseURL string, client *http.Client) *AtCoderClient {
+	return &AtCoderClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (c *AtCoderClient) Judge() string {
+	return activity.JudgeAtCoder
+}
+
+type atcoderSubmission struct {
+	ID          int64  `json:"id"`
+	EpochSecond int64  `json:"epoch_second"`
+	ProblemID   string `json:"problem_id"`
+	ContestID   string `json:"contest_id"`
+	Language    string `json:"language"`
+	Result      string `json:"result"`
+}
+
+func (c *AtCoderClient) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	if !atcoderUserID.MatchString(handle) {
+		return "", ErrHandleNotFound
+	}
+	// Users without any accepted submission are unknown to AtCoder Problems as well
+	var rank struct {
+		Count int `json:"count"`
+	}
+	err := c.get(ctx, "/atcoder-api/v3/user/ac_rank", url.Values{"user": {handle}}, &rank)
+	if errors.Is(err, errAtCoderNotFound) {
+		return "", ErrHandleNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return handle, nil
+}
+
+func (c *AtCoderClient) Submissions(ctx context.Context, handle, cursor string) ([]Submission, string, error) {
+	if cursor == "" {
+		return []Submission{}, strconv.FormatInt(time.Now().Unix(), 10), nil
+	}
+	from, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return nil, cursor, fmt.Errorf("invalid atcoder cursor %q", cursor)
+	}
+
+	// Submissions come oldest first. from_second is inclusive, so the cursor stays on the last second
+	// seen: submissions judged late in that second are still found, and the importer skips repeats.
+	var accepted []atcoderSubmission
+	for page := 0; page < atcoderMaxPages; page++ {
+		if page > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, cursor, ctx.Err()
+			case <-time.After(atcoderPageDelay):
+			}
+		}
+
+		var submissions []atcoderSubmission
+		params := url.Values{"user": {handle}, "from_second": {strconv.FormatInt(from, 10)}}
+		if err := c.get(ctx, "/atcoder-api/v3/user/submissions", params, &submissions); err != nil {
+			return nil, cursor, err
+		}
+
+		next := from
+		for _, s := range submissions {
+			next = max(next, s.EpochSecond)
+			if s.Result == "AC" {
+				accepted = append(accepted, s)
+			}
+		}
+		done := len(submissions) < atcoderPageSize || next == from
+		from = next
+		if done {
+			break
+		}
+	}
+
+	result := []Submission{}
+	if len(accepted) == 0 {
+		return result, strconv.FormatInt(from, 10), nil
+	}
+	problems, err := c.loadProblems(ctx)
+	if err != nil {
+		return nil, cursor, err
+	}
+	for _, s := range accepted {
+		if !atcoderTask.MatchString(s.ProblemID) || !atcoderTask.MatchString(s.ContestID) {
+			continue
+		}
+		submission := Submission{
+			ID:          strconv.FormatInt(s.ID, 10),
+			Problem:     atcoder(strings.ToLower(s.ContestID), strings.ToLower(s.ProblemID)),
+			Language:    s.Language,
+			SubmittedAt: time.Unix(s.EpochSecond, 0).UTC(),
+		}
+		if problem, ok := problems[s.ProblemID]; ok {
+			submission.Name = problem.Name
+			if problem.Difficulty != nil {
+				rating := AtCoderRating(*problem.Difficulty)
+				submission.Rating = &rating
+			}
+		}
+		result = append(result, submission)
+	}
+	return result, strconv.FormatInt(from, 10), nil
+}
+
+// AtCoderRating turns an AtCoder Problems difficulty estimate into a positive rating. Estimates go
+// below zero for the easiest problems, so those are squashed into (0, 400) the way AtCoder Problems
+// shows them.
+func AtCoderRating(difficulty float64) int {
+	if difficulty < 400 {
+		difficulty = 400 / math.Exp((400-difficulty)/400)
+	}
+	return max(1, int(math.Round(difficulty)))
+}
+
+// loadProblems returns problem names and difficulty estimates, loading them when they are missing or stale.
+func (c *AtCoderClient) loadProblems(ctx context.Context) (map[string]atcoderProblem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.problems != nil && time.Since(c.problemsLoaded) < atcoderProblemsTTL {
+		return c.problems, nil
+	}
+
+	var list []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.get(ctx, "/resources/problems.json", nil, &list); err != nil {
+		return nil, err
+	}
+	var models map[string]struct {
+		Difficulty *float64 `json:"difficulty"`
+	}
+	if err := c.get(ctx, "/resources/problem-models.json", nil, &models); err != nil {
+		return nil, err
+	}
+
+	problems := make(map[string]atcoderProblem, len(list))
+	for _, p := range list {
+		problems[p.ID] = atcoderProblem{Name: p.Name, Difficulty: models[p.ID].Difficulty}
+	}
+	c.problems, c.problemsLoaded = problems, time.Now()
+	return problems, nil
+}
+
+func (c *AtCoderClient) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	target := c.baseURL + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("atcoder problems %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("atcoder problems %s: %w", path, errAtCoderNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("atcoder problems %s: status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("atcoder problems %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"backend/models/activity"
+	"backend/models/problem"
+)
+
+// ReadCatalogDump reads problems for the catalog from a data dump of judgeName saved at path: the
+// response of the Codeforces problemset.problems API method, or the merged-problems.json of AtCoder
+// Problems, optionally with its problem-models.json at modelsPath for difficulties. It also returns how
+// many of the dump's problems were left out because they could not be identified.
+func ReadCatalogDump(judgeName, path, modelsPath string) ([]problem.Problem, int, error) {
+	if judgeName != activity.JudgeCodeforces && judgeName != activity.JudgeAtCoder {
+		return nil, 0, fmt.Errorf("no dumps of judge %q can be read, only %s and %s", judgeName, activity.JudgeCodeforces, activity.JudgeAtCoder)
+	}
+	if modelsPath != "" && judgeName != activity.JudgeAtCoder {
+		return nil, 0, fmt.Errorf("problem models only apply to %s dumps", activity.JudgeAtCoder)
+	}
+	dump, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer dump.Close()
+
+	if judgeName == activity.JudgeCodeforces {
+		return ParseCodeforcesProblemset(dump)
+	}
+	if modelsPath == "" {
+		return ParseAtCoderProblems(dump, nil)
+	}
+	models, err := os.Open(modelsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer models.Close()
+	return ParseAtCoderProblems(dump, models)
+}
+
+// ParseCodeforcesProblemset reads the response of the problemset.problems API method. Problems
+// outside contests are left out and counted, as with imported submissions.
+func ParseCodeforcesProblemset(r io.Reader) ([]problem.Problem, int, error) {
+	var response struct {
+		Status  string `json:"status"`
+		Comment string `json:"comment"`
+		Result  struct {
+			Problems []struct {
+				ContestID int      `json:"contestId"`
+				Index     string   `json:"index"`
+				Name      string   `json:"name"`
+				Rating    int      `json:"rating"`
+				Tags      []string `json:"tags"`
+			} `json:"problems"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, 0, fmt.Errorf("codeforces problemset: %w", err)
+	}
+	if response.Status != "OK" {
+		return nil, 0, fmt.Errorf("codeforces problemset: status %q: %s", response.Status, response.Comment)
+	}
+
+	problems := []problem.Problem{}
+	skipped := 0
+	for _, p := range response.Result.Problems {
+		if p.ContestID == 0 {
+			skipped++
+			continue
+		}
+		id := fmt.Sprintf("%d%s", p.ContestID, p.Index)
+		if p.ContestID >= codeforcesFirstGymContest {
+			id = "gym" + id
+		}
+		identified, err := Canonical(activity.JudgeCodeforces, id)
+		if err != nil {
+			skipped++
+			continue
+		}
+		entry := catalogProblem(identified, p.Name, p.Tags)
+		if p.Rating > 0 {
+			rating := p.Rating
+			entry.Rating = &rating
+		}
+		problems = append(problems, entry)
+	}
+	return problems, skipped, nil
+}
+
+// ParseAtCoderProblems reads AtCoder Problems' merged-problems.json and, when models is not nil, the
+// difficulty estimates of its problem-models.json, rated like imported submissions.
+func ParseAtCoderProblems(merged io.Reader, models io.Reader) ([]problem.Problem, int, error) {
+	var list []struct {
+		ID        string `json:"id"`
+		ContestID string `json:"contest_id"`
+		Name      string `json:"name"`
+	}
+	if err := json.NewDecoder(merged).Decode(&list); err != nil {
+		return nil, 0, fmt.Errorf("atcoder problems: %w", err)
+	}
+	difficulties := map[string]struct {
+		Difficulty *float64 `json:"difficulty"`
+	}{}
+	if models != nil {
+		if err := json.NewDecoder(models).Decode(&difficulties); err != nil {
+			return nil, 0, fmt.Errorf("atcoder problem models: %w", err)
+		}
+	}
+
+	problems := []problem.Problem{}
+	skipped := 0
+	for _, p := range list {
+		if !atcoderTask.MatchString(p.ID) || !atcoderTask.MatchString(p.ContestID) {
+			skipped++
+			continue
+		}
+		entry := catalogProblem(atcoder(strings.ToLower(p.ContestID), strings.ToLower(p.ID)), p.Name, nil)
+		if d := difficulties[p.ID].Difficulty; d != nil {
+			rating := AtCoderRating(*d)
+			entry.Rating = &rating
+		}
+		problems = append(problems, entry)
+	}
+	return problems, skipped, nil
+}
+
+func catalogProblem(p Problem, name string, tags []string) problem.Problem {
+	return problem.Problem{
+		Judge:      p.Judge,
+		ExternalID: p.ID,
+		Key:        p.Key(),
+		Name:       strings.TrimSpace(name),
+		URL:        p.URL,
+		Tags:       judgeTags(tags),
+	}
+}
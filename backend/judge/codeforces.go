@@ -0,0 +1,235 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/models/activity"
+)
+
+// CodeforcesAPIURL is where the Codeforces API is served.
+const CodeforcesAPIURL = "https://codeforces.com/api"
+
+const (
+	// codeforcesPageSize is how many submissions one user.status call asks for
+	codeforcesPageSize = 100
+	// codeforcesMaxPages bounds the calls of a single import; an account with more new submissions than
+	// that gets the rest imported by the following imports
+	codeforcesMaxPages = 10
+	// codeforcesFirstGymContest is where gym contest IDs start
+	codeforcesFirstGymContest = 100000
+)
+
+// CodeforcesClient reads accepted submissions through the public Codeforces API. Its cursor is the ID
+// of the newest submission seen so far, see codeforcesCursor.
+type CodeforcesClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewCodeforcesClient calls the API at baseURL, normally CodeforcesAPIURL, through client. Both can be
+// replaced to talk to a fake API.
+func NewCodeforcesClient(baseURL string, client *http.Client) *CodeforcesClient {
+	return &CodeforcesClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (c *CodeforcesClient) Judge() string {
+	return activity.JudgeCodeforces
+}
+
+type codeforcesSubmission struct {
+	ID                  int64  `json:"id"`
+	CreationTimeSeconds int64  `json:"creationTimeSeconds"`
+	ProgrammingLanguage string `json:"programmingLanguage"`
+	Verdict             string `json:"verdict"`
+	Problem             struct {
+		ContestID int      `json:"contestId"`
+		Index     string   `json:"index"`
+		Name      string   `json:"name"`
+		Rating    int      `json:"rating"`
+		Tags      []string `json:"tags"`
+	} `json:"problem"`
+}
+
+func (c *CodeforcesClient) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	var users []struct {
+		Handle string `json:"handle"`
+	}
+	if err := c.call(ctx, "user.info", url.Values{"handles": {handle}}, &users); err != nil {
+		return "", err
+	}
+	if len(users) != 1 {
+		return "", ErrHandleNotFound
+	}
+	return users[0].Handle, nil
+}
+
+func (c *CodeforcesClient) Submissions(ctx context.Context, handle, cursor string) ([]Submission, string, error) {
+	if cursor == "" {
+		var submissions []codeforcesSubmission
+		if err := c.call(ctx, "user.status", url.Values{"handle": {handle}, "from": {"1"}, "count": {"1"}}, &submissions); err != nil {
+			return nil, cursor, err
+		}
+		var newest int64
+		if len(submissions) > 0 {
+			newest = submissions[0].ID
+		}
+		return []Submission{}, strconv.FormatInt(newest, 10), nil
+	}
+
+	next, err := parseCodeforcesCursor(cursor)
+	if err != nil {
+		return nil, cursor, fmt.Errorf("invalid codeforces cursor %q", cursor)
+	}
+
+	var accepted []codeforcesSubmission
+	oldest := next.Before
+	reachedCursor := false
+	// Submissions come newest first, so page back until reaching the cursor
+	for page := 0; page < codeforcesMaxPages && !reachedCursor; page++ {
+		var submissions []codeforcesSubmission
+		params := url.Values{
+			"handle": {handle},
+			"from":   {strconv.Itoa(next.From + page*codeforcesPageSize)},
+			"count":  {strconv.Itoa(codeforcesPageSize)},
+		}
+		if err := c.call(ctx, "user.status", params, &submissions); err != nil {
+			return nil, cursor, err
+		}
+
+		for _, s := range submissions {
+			if s.ID <= next.After {
+				reachedCursor = true
+				break
+			}
+			// Seen by the import that left the backlog
+			if next.Before != 0 && s.ID >= next.Before {
+				continue
+			}
+			next.Newest = max(next.Newest, s.ID)
+			oldest = s.ID
+			if s.Verdict == "OK" {
+				accepted = append(accepted, s)
+			}
+		}
+		if len(submissions) < codeforcesPageSize {
+			reachedCursor = true
+		}
+	}
+
+	if reachedCursor {
+		next = codeforcesCursor{After: next.Newest, Newest: next.Newest, From: 1}
+	} else {
+		// Older submissions are left for the next import. Newer ones only push them further back, so
+		// it can carry on from where this one stopped.
+		next.Before = oldest
+		next.From += codeforcesMaxPages * codeforcesPageSize
+	}
+
+	result := []Submission{}
+	for i := len(accepted) - 1; i >= 0; i-- {
+		if submission, ok := codeforcesToSubmission(accepted[i]); ok {
+			result = append(result, submission)
+		}
+	}
+	return result, next.String(), nil
+}
+
+// codeforcesCursor is normally the ID of the newest submission seen. An import that pages back
+// codeforcesMaxPages without reaching it leaves a backlog: the submissions between After and Before,
+// which the next import looks for starting at the From-th newest submission. Once the backlog is
+// imported, the cursor moves on to Newest.
+type codeforcesCursor struct {
+	After  int64
+	Before int64
+	Newest int64
+	From   int
+}
+
+func parseCodeforcesCursor(cursor string) (codeforcesCursor, error) {
+	if !strings.Contains(cursor, ":") {
+		after, err := strconv.ParseInt(cursor, 10, 64)
+		return codeforcesCursor{After: after, Newest: after, From: 1}, err
+	}
+	var c codeforcesCursor
+	if _, err := fmt.Sscanf(cursor, "%d:%d:%d:%d", &c.After, &c.Before, &c.Newest, &c.From); err != nil {
+		return c, err
+	}
+	if c.From < 1 {
+		return c, fmt.Errorf("invalid offset %d", c.From)
+	}
+	return c, nil
+}
+
+func (c codeforcesCursor) String() string {
+	if c.Before == 0 {
+		return strconv.FormatInt(c.Newest, 10)
+	}
+	return fmt.Sprintf("%d:%d:%d:%d", c.After, c.Before, c.Newest, c.From)
+}
+
+// codeforcesToSubmission leaves out problems outside contests, such as acm.sgu.ru ones, which have no
+// problem ID of their own.
+func codeforcesToSubmission(s codeforcesSubmission) (Submission, bool) {
+	if s.Problem.ContestID == 0 {
+		return Submission{}, false
+	}
+	id := fmt.Sprintf("%d%s", s.Problem.ContestID, s.Problem.Index)
+	if s.Problem.ContestID >= codeforcesFirstGymContest {
+		id = "gym" + id
+	}
+	problem, err := Canonical(activity.JudgeCodeforces, id)
+	if err != nil {
+		return Submission{}, false
+	}
+
+	submission := Submission{
+		ID:          strconv.FormatInt(s.ID, 10),
+		Problem:     problem,
+		Name:        s.Problem.Name,
+		Tags:        s.Problem.Tags,
+		Language:    s.ProgrammingLanguage,
+		SubmittedAt: time.Unix(s.CreationTimeSeconds, 0).UTC(),
+	}
+	if s.Problem.Rating > 0 {
+		rating := s.Problem.Rating
+		submission.Rating = &rating
+	}
+	return submission, true
+}
+
+// call runs an API method and decodes its result. The API wraps every result as
+// {"status": "OK", "result": ...}, or {"status": "FAILED", "comment": ...} on errors.
+func (c *CodeforcesClient) call(ctx context.Context, method string, params url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("codeforces %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status  string          `json:"status"`
+		Comment string          `json:"comment"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("codeforces %s: status %d: %w", method, resp.StatusCode, err)
+	}
+	if body.Status != "OK" {
+		if strings.Contains(body.Comment, "not found") {
+			return ErrHandleNotFound
+		}
+		return fmt.Errorf("codeforces %s: %s", method, body.Comment)
+	}
+	return json.Unmarshal(body.Result, result)
+}
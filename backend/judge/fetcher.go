@@ -0,0 +1,35 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrHandleNotFound is returned by fetchers for handles the judge does not know.
+var ErrHandleNotFound = errors.New("handle not found")
+
+// Submission is an accepted submission on an online judge.
+type Submission struct {
+	// ID is the judge's submission ID
+	ID          string
+	Problem     Problem
+	Name        string
+	Rating      *int
+	Difficulty  *string
+	Tags        []string
+	Language    string
+	SubmittedAt time.Time
+}
+
+// Fetcher loads users' accepted submissions from one online judge.
+type Fetcher interface {
+	// Judge is the activity judge the fetcher imports from, e.g. activity.JudgeCodeforces.
+	Judge() string
+	// ResolveHandle checks that handle exists and returns it spelled the way the judge does.
+	ResolveHandle(ctx context.Context, handle string) (string, error)
+	// Submissions returns handle's accepted submissions made after cursor, oldest first, along with
+	// the cursor to continue from next time. An empty cursor starts from now: it returns no submissions,
+	// only a cursor, so linking an account does not import its whole history.
+	Submissions(ctx context.Context, handle, cursor string) ([]Submission, string, error)
+}
@@ -0,0 +1,145 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/models/activity"
+	"backend/models/group"
+	"backend/models/linkedaccount"
+	"backend/models/problem"
+	"backend/models/user"
+)
+
+// Importer turns the accepted submissions of linked judge accounts into activities.
+type Importer struct {
+	fetchers   map[string]Fetcher
+	accounts   linkedaccount.LinkedAccountModel
+	activities activity.ActivityModel
+	groups     group.GroupModel
+	users      user.UserModel
+	problems   problem.ProblemModel
+}
+
+func NewImporter(accounts linkedaccount.LinkedAccountModel, activities activity.ActivityModel, groups group.GroupModel, users user.UserModel, problems problem.ProblemModel, fetchers ...Fetcher) *Importer {
+	im := &Importer{
+		fetchers:   map[string]Fetcher{},
+		accounts:   accounts,
+		activities: activities,
+		groups:     groups,
+		users:      users,
+		problems:   problems,
+	}
+	for _, f := range fetchers {
+		im.fetchers[f.Judge()] = f
+	}
+	return im
+}
+
+// Fetcher returns the fetcher for judge, if accounts on judge can be linked.
+func (im *Importer) Fetcher(judge string) (Fetcher, bool) {
+	f, ok := im.fetchers[judge]
+	return f, ok
+}
+
+// ImportAccount creates activities for the account's accepted submissions since its cursor and
+// returns how many it created. A submission is imported at most once, and not at all when the user
+// already logged the problem, so solving a problem again does not add to their count. The cursor only
+// moves once every submission is imported.
+func (im *Importer) ImportAccount(ctx context.Context, account linkedaccount.LinkedAccount) (int, error) {
+	f, ok := im.fetchers[account.Judge]
+	if !ok {
+		return 0, nil
+	}
+	submissions, cursor, err := f.Submissions(ctx, account.Handle, account.Cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	loc := time.UTC
+	if u, exists := im.users.GetUserByID(account.UserID); exists {
+		loc = u.Location()
+	}
+
+	created := 0
+	for _, s := range submissions {
+		if _, exists := im.activities.GetActivityBySubmission(account.Judge, s.ID); exists {
+			continue
+		}
+		if im.activities.HasProblem(account.UserID, s.Problem.Key()) {
+			continue
+		}
+
+		a := newImportedActivity(account.UserID, s, loc)
+		problem.Link(im.problems, &a)
+		a = im.activities.CreateActivity(a)
+		if a.ID == 0 {
+			// The cursor stays put so the next import retries this submission; the ones created
+			// so far are not imported twice
+			log.Printf("Failed to import submission: judge=%s, submission_id=%s", account.Judge, s.ID)
+			return created, fmt.Errorf("failed to import %s submission %s", account.Judge, s.ID)
+		}
+		created++
+
+		for _, groupID := range account.GroupIDs {
+			// Members can leave a group after choosing it
+			if im.groups.IsUserInGroup(groupID, account.UserID) {
+				im.groups.AddActivityToGroup(groupID, a.ID)
+			}
+		}
+	}
+
+	if !im.accounts.SetCursor(account.ID, account.Handle, cursor, time.Now()) {
+		log.Printf("Not moving the cursor of %s account %d: it was relinked or unlinked during the import", account.Judge, account.ID)
+	}
+	return created, nil
+}
+
+// newImportedActivity is dated on the day of the submission in the user's time zone, like the days of their streak.
+func newImportedActivity(userID int, s Submission, loc *time.Location) activity.Activity {
+	judge, problemID, problemURL, problemKey := s.Problem.Judge, s.Problem.ID, s.Problem.URL, s.Problem.Key()
+	verdict := activity.VerdictAccepted
+	submissionID := s.ID
+	title := s.Name
+	if title == "" {
+		title = problemKey
+	}
+
+	a := activity.Activity{
+		CreatorID:    userID,
+		Title:        title,
+		Date:         activity.Day(s.SubmittedAt.In(loc)),
+		Judge:        &judge,
+		ProblemID:    &problemID,
+		ProblemURL:   &problemURL,
+		ProblemKey:   &problemKey,
+		SubmissionID: &submissionID,
+		Rating:       s.Rating,
+		Difficulty:   s.Difficulty,
+		Verdict:      &verdict,
+	}
+	if s.Language != "" {
+		language := s.Language
+		a.Language = &language
+	}
+	a.Tags = judgeTags(s.Tags)
+	a.Normalize()
+	return a
+}
+
+// judgeTags normalizes a judge's tags for a problem. Judges have tags we would reject from users; those
+// are left out rather than failing the import.
+func judgeTags(tags []string) activity.Tags {
+	var result activity.Tags
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = activity.NormalizeTag(tag)
+		if activity.ValidTag(tag) && !seen[tag] && len(result) < activity.MaxTags {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
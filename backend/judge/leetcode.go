@@ -0,0 +1,217 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/models/activity"
+)
+
+// LeetCodeGraphQLURL is LeetCode's GraphQL endpoint.
+const LeetCodeGraphQLURL = "https://leetcode.com/graphql"
+
+// leetcodeRecentLimit is the most recent accepted submissions LeetCode lists for a user. Accounts
+// have to be imported before they solve more problems than that, or the oldest are missed.
+const leetcodeRecentLimit = 20
+
+var leetcodeUsername = regexp.MustCompile(`^[A-Za-z0-9_-]{1,30}$`)
+
+// GraphQLClient runs a GraphQL query and decodes its data into result.
+type GraphQLClient interface {
+	Query(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error
+}
+
+// HTTPGraphQLClient posts queries to a GraphQL endpoint over HTTP.
+type HTTPGraphQLClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewHTTPGraphQLClient(endpoint string, client *http.Client) *HTTPGraphQLClient {
+	return &HTTPGraphQLClient{endpoint: endpoint, client: client}
+}
+
+func (c *HTTPGraphQLClient) Query(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// LeetCode rejects GraphQL requests that do not look like they come from its own pages
+	req.Header.Set("Referer", "https://leetcode.com")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("graphql: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql: status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("graphql: %w", err)
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("graphql: %s", response.Errors[0].Message)
+	}
+	return json.Unmarshal(response.Data, result)
+}
+
+// LeetCodeClient reads recent accepted submissions through LeetCode's GraphQL API. Its cursor is the
+// ID of the newest submission seen so far.
+type LeetCodeClient struct {
+	graphql GraphQLClient
+
+	mu        sync.Mutex
+	questions map[string]leetcodeQuestion
+}
+
+type leetcodeQuestion struct {
+	Difficulty string `json:"difficulty"`
+	TopicTags  []struct {
+		Name string `json:"name"`
+	} `json:"topicTags"`
+}
+
+// NewLeetCodeClient queries LeetCode through graphql, normally an HTTPGraphQLClient for LeetCodeGraphQLURL.
+func NewLeetCodeClient(graphql GraphQLClient) *LeetCodeClient {
+	return &LeetCodeClient{graphql: graphql, questions: map[string]leetcodeQuestion{}}
+}
+
+func (c *LeetCodeClient) Judge() string {
+	return activity.JudgeLeetCode
+}
+
+const leetcodeUserQuery = `query user($username: String!) {
+  matchedUser(username: $username) { username }
+}`
+
+const leetcodeRecentQuery = `query recentAcSubmissions($username: String!, $limit: Int!) {
+  recentAcSubmissionList(username: $username, limit: $limit) { id title titleSlug timestamp lang }
+}`
+
+const leetcodeQuestionQuery = `query question($titleSlug: String!) {
+  question(titleSlug: $titleSlug) { difficulty topicTags { name } }
+}`
+
+func (c *LeetCodeClient) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	if !leetcodeUsername.MatchString(handle) {
+		return "", ErrHandleNotFound
+	}
+	var data struct {
+		MatchedUser *struct {
+			Username string `json:"username"`
+		} `json:"matchedUser"`
+	}
+	if err := c.graphql.Query(ctx, leetcodeUserQuery, map[string]interface{}{"username": handle}, &data); err != nil {
+		return "", err
+	}
+	if data.MatchedUser == nil {
+		return "", ErrHandleNotFound
+	}
+	return data.MatchedUser.Username, nil
+}
+
+func (c *LeetCodeClient) Submissions(ctx context.Context, handle, cursor string) ([]Submission, string, error) {
+	var after int64
+	if cursor != "" {
+		var err error
+		if after, err = strconv.ParseInt(cursor, 10, 64); err != nil {
+			return nil, cursor, fmt.Errorf("invalid leetcode cursor %q", cursor)
+		}
+	}
+
+	var data struct {
+		RecentAcSubmissionList []struct {
+			ID        string `json:"id"`
+			Title     string `json:"title"`
+			TitleSlug string `json:"titleSlug"`
+			Timestamp string `json:"timestamp"`
+			Lang      string `json:"lang"`
+		} `json:"recentAcSubmissionList"`
+	}
+	variables := map[string]interface{}{"username": handle, "limit": leetcodeRecentLimit}
+	if err := c.graphql.Query(ctx, leetcodeRecentQuery, variables, &data); err != nil {
+		return nil, cursor, err
+	}
+
+	newest := after
+	result := []Submission{}
+	// The list comes newest first
+	for i := len(data.RecentAcSubmissionList) - 1; i >= 0; i-- {
+		s := data.RecentAcSubmissionList[i]
+		id, err := strconv.ParseInt(s.ID, 10, 64)
+		if err != nil || id <= after {
+			continue
+		}
+		newest = max(newest, id)
+		if cursor == "" || !slug.MatchString(s.TitleSlug) {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(s.Timestamp, 10, 64)
+
+		question, err := c.question(ctx, s.TitleSlug)
+		if err != nil {
+			return nil, cursor, err
+		}
+		submission := Submission{
+			ID:          s.ID,
+			Problem:     leetcode(s.TitleSlug),
+			Name:        s.Title,
+			Language:    s.Lang,
+			SubmittedAt: time.Unix(timestamp, 0).UTC(),
+		}
+		if question.Difficulty != "" {
+			difficulty := strings.ToLower(question.Difficulty)
+			submission.Difficulty = &difficulty
+		}
+		for _, tag := range question.TopicTags {
+			submission.Tags = append(submission.Tags, tag.Name)
+		}
+		result = append(result, submission)
+	}
+	return result, strconv.FormatInt(newest, 10), nil
+}
+
+// question loads a problem's difficulty and topic tags, which do not change, so each is loaded once.
+func (c *LeetCodeClient) question(ctx context.Context, titleSlug string) (leetcodeQuestion, error) {
+	c.mu.Lock()
+	question, ok := c.questions[titleSlug]
+	c.mu.Unlock()
+	if ok {
+		return question, nil
+	}
+
+	var data struct {
+		Question *leetcodeQuestion `json:"question"`
+	}
+	if err := c.graphql.Query(ctx, leetcodeQuestionQuery, map[string]interface{}{"titleSlug": titleSlug}, &data); err != nil {
+		return leetcodeQuestion{}, err
+	}
+	if data.Question != nil {
+		question = *data.Question
+	}
+
+	c.mu.Lock()
+	c.questions[titleSlug] = question
+	c.mu.Unlock()
+	return question, nil
+}
@@ -0,0 +1,223 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/models/linkedaccount"
+)
+
+// SchedulerConfig decides how often linked accounts are imported.
+type SchedulerConfig struct {
+	// Interval is the time between imports of one account; zero only imports accounts on request
+	Interval time.Duration
+	// Jitter spreads imports out by moving each one up to this fraction of Interval earlier or later
+	Jitter float64
+	// Backoff is the wait after a first failure; it doubles with every further failure up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Concurrency is how many accounts on the same judge are imported at once
+	Concurrency int
+	// PollInterval is how often the scheduler looks for accounts that are due
+	PollInterval time.Duration
+	// ManualCooldown is how soon after an import an account can be synced on request again
+	ManualCooldown time.Duration
+}
+
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Interval:       30 * time.Minute,
+		Jitter:         0.1,
+		Backoff:        time.Minute,
+		MaxBackoff:     12 * time.Hour,
+		Concurrency:    2,
+		PollInterval:   30 * time.Second,
+		ManualCooldown: time.Minute,
+	}
+}
+
+// SchedulerConfigFromEnv overrides the defaults with IMPORT_INTERVAL, a duration such as "15m" or
+// "off" to only import on request, and IMPORT_CONCURRENCY, the number of imports per judge at once.
+func SchedulerConfigFromEnv() (SchedulerConfig, error) {
+	config := DefaultSchedulerConfig()
+	switch value := os.Getenv("IMPORT_INTERVAL"); value {
+	case "":
+	case "0", "off":
+		config.Interval = 0
+	default:
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return SchedulerConfig{}, fmt.Errorf("IMPORT_INTERVAL: invalid duration %q", value)
+		}
+		config.Interval = interval
+	}
+	if value := os.Getenv("IMPORT_CONCURRENCY"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			return SchedulerConfig{}, fmt.Errorf("IMPORT_CONCURRENCY: invalid number %q", value)
+		}
+		config.Concurrency = concurrency
+	}
+	return config, nil
+}
+
+// Scheduler runs the importer for every linked account that is due, whatever its judge. When an
+// account is due is kept in the database, so schedules survive restarts.
+type Scheduler struct {
+	importer *Importer
+	accounts linkedaccount.LinkedAccountModel
+	config   SchedulerConfig
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	slots   map[string]chan struct{}
+	running map[int]bool
+	random  *rand.Rand
+}
+
+func NewScheduler(importer *Importer, accounts linkedaccount.LinkedAccountModel, config SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		importer: importer,
+		accounts: accounts,
+		config:   config,
+		wake:     make(chan struct{}, 1),
+		slots:    map[string]chan struct{}{},
+		running:  map[int]bool{},
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Run syncs due accounts every PollInterval, and right away when a sync is requested, until ctx is done.
+// With an Interval, accounts left unscheduled while imports were off are synced first.
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.config.Interval > 0 {
+		s.accounts.ScheduleUnscheduled(time.Now())
+	}
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.SyncDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// SyncDue imports every account that is due and waits until they are done. Accounts on different
+// judges are imported in parallel, up to Concurrency at a time on each judge.
+func (s *Scheduler) SyncDue(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, account := range s.accounts.GetDueAccounts(time.Now()) {
+		if !s.start(account.ID) {
+			continue
+		}
+		wg.Add(1)
+		go func(account linkedaccount.LinkedAccount) {
+			defer wg.Done()
+			defer s.finish(account.ID)
+
+			slot := s.slot(account.Judge)
+			select {
+			case slot <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slot }()
+			s.sync(ctx, account)
+		}(account)
+	}
+	wg.Wait()
+}
+
+// TriggerSync makes the user's accounts due now, leaving out ones imported within ManualCooldown,
+// and wakes the scheduler. It returns the user's accounts as they are afterwards.
+func (s *Scheduler) TriggerSync(userID int) []linkedaccount.LinkedAccount {
+	now := time.Now()
+	for _, account := range s.accounts.GetAccountsByUserID(userID) {
+		if now.Sub(account.LastAttemptAt()) >= s.config.ManualCooldown {
+			s.accounts.SetNextSync(account.ID, account.Handle, &now)
+		}
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return s.accounts.GetAccountsByUserID(userID)
+}
+
+// Syncing reports whether the account is being imported right now.
+func (s *Scheduler) Syncing(accountID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[accountID]
+}
+
+func (s *Scheduler) sync(ctx context.Context, account linkedaccount.LinkedAccount) {
+	created, err := s.importer.ImportAccount(ctx, account)
+	now := time.Now()
+	if err != nil {
+		log.Printf("Failed to import %s account %q of user_id=%d: %v", account.Judge, account.Handle, account.UserID, err)
+		next := now.Add(s.backoff(account.Failures + 1))
+		s.accounts.RecordFailure(account.ID, account.Handle, err.Error(), now, &next)
+		return
+	}
+	if created > 0 {
+		log.Printf("Imported %d %s submissions of user_id=%d", created, account.Judge, account.UserID)
+	}
+
+	var next *time.Time
+	if s.config.Interval > 0 {
+		at := now.Add(s.jitter(s.config.Interval))
+		next = &at
+	}
+	s.accounts.SetNextSync(account.ID, account.Handle, next)
+}
+
+// backoff is the wait after the given number of failures in a row.
+func (s *Scheduler) backoff(failures int) time.Duration {
+	wait := float64(s.config.Backoff) * math.Pow(2, float64(failures-1))
+	return s.jitter(time.Duration(math.Min(wait, float64(s.config.MaxBackoff))))
+}
+
+func (s *Scheduler) jitter(d time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(float64(d) * (1 + s.config.Jitter*(2*s.random.Float64()-1)))
+}
+
+// slot returns the semaphore that limits imports on judge.
+func (s *Scheduler) slot(judge string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.slots[judge]; !ok {
+		s.slots[judge] = make(chan struct{}, s.config.Concurrency)
+	}
+	return s.slots[judge]
+}
+
+// start marks the account as running, unless it already is from another SyncDue call.
+func (s *Scheduler) start(accountID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[accountID] {
+		return false
+	}
+	s.running[accountID] = true
+	return true
+}
+
+func (s *Scheduler) finish(accountID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, accountID)
+}
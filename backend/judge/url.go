@@ -0,0 +1,348 @@
+// Package judge knows about the online judges members solve problems on. ParseURL recognises problem
+// links from the major judges and reduces each to a canonical problem, so that two members solving
+// the same problem through different links are recognised as solving the same problem.
+package judge
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"backend/models/activity"
+)
+
+var (
+	// ErrUnrecognised is returned for URLs that are not a problem on a known judge.
+	ErrUnrecognised = errors.New("not a problem URL of a known judge")
+	// ErrNoCanonicalForm is returned for judges whose problem IDs cannot be checked, such as "other".
+	ErrNoCanonicalForm = errors.New("judge has no canonical problem form")
+)
+
+// Problem is a problem on an online judge in canonical form.
+type Problem struct {
+	Judge string
+	ID    string
+	URL   string
+}
+
+// Key identifies the problem across all judges, e.g. "codeforces:1352A".
+func (p Problem) Key() string {
+	return p.Judge + ":" + p.ID
+}
+
+var (
+	codeforcesIDPattern = regexp.MustCompile(`^(?i)(gym)?(\d+)([a-z]\d?)$`)
+	codeforcesIndex     = regexp.MustCompile(`^(?i)[a-z]\d?$`)
+	digits              = regexp.MustCompile(`^\d+$`)
+	atcoderTask         = regexp.MustCompile(`^(?i)[a-z0-9_-]+$`)
+	slug                = regexp.MustCompile(`^(?i)[a-z0-9_-]+$`)
+	spojCode            = regexp.MustCompile(`^(?i)[a-z0-9_]+$`)
+	kattisName          = regexp.MustCompile(`^(?i)[a-z0-9]+$`)
+	beecrowdResource    = regexp.MustCompile(`^UOJ_(\d+)\.html$`)
+	uvaPDF              = regexp.MustCompile(`^(\d+)\.pdf$`)
+	vjudgeID            = regexp.MustCompile(`^([A-Za-z0-9]+)-(\S+)$`)
+)
+
+// ParseURL recognises a problem link from Codeforces (problemset, contest and gym), AtCoder, LeetCode,
+// SPOJ, Kattis, CSES, beecrowd (formerly URI), UVa and vjudge. Mirrors, www prefixes, trailing
+// slashes, query strings and fragments are all ignored. vjudge links resolve to the origin judge when
+// it is one of these; otherwise the problem is kept as a vjudge problem.
+func ParseURL(rawURL string) (Problem, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return Problem{}, ErrUnrecognised
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var problem Problem
+	var ok bool
+	switch {
+	case onHost(host, "codeforces.com") || host == "codeforces.ru":
+		problem, ok = parseCodeforces(path)
+	case host == "atcoder.jp":
+		problem, ok = parseAtCoder(path)
+	case host == "leetcode.com" || host == "leetcode.cn":
+		problem, ok = parseLeetCode(path)
+	case host == "spoj.com":
+		problem, ok = parseSPOJ(path)
+	case onHost(host, "kattis.com"):
+		problem, ok = parseKattis(path)
+	case host == "cses.fi":
+		problem, ok = parseCSES(path)
+	case onHost(host, "beecrowd.com.br") || onHost(host, "beecrowd.com") || onHost(host, "urionlinejudge.com.br"):
+		problem, ok = parseBeecrowd(path)
+	case host == "onlinejudge.org" || host == "uva.onlinejudge.org":
+		problem, ok = parseUVa(u, path)
+	case host == "vjudge.net":
+		problem, ok = parseVJudge(path)
+	}
+	if !ok {
+		return Problem{}, ErrUnrecognised
+	}
+	return problem, nil
+}
+
+// onHost reports whether host is domain or one of its subdomains.
+func onHost(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// Canonical returns the canonical form of a problem given by judge and problem ID rather than by URL.
+// For vjudge IDs of a known origin judge ("CodeForces-1352A") it returns the origin judge's problem.
+func Canonical(judge, id string) (Problem, error) {
+	id = strings.TrimSpace(id)
+	var problem Problem
+	var ok bool
+	switch judge {
+	case activity.JudgeCodeforces:
+		if m := codeforcesIDPattern.FindStringSubmatch(id); m != nil {
+			problem, ok = codeforces(m[1] != "", m[2], m[3]), true
+		}
+	case activity.JudgeAtCoder:
+		if atcoderTask.MatchString(id) {
+			task := strings.ToLower(id)
+			// The contest is part of most task IDs, e.g. abc300_a; AtCoder redirects when it is not
+			contest, _, _ := strings.Cut(task, "_")
+			problem, ok = atcoder(contest, task), true
+		}
+	case activity.JudgeLeetCode:
+		problem, ok = leetcode(id), slug.MatchString(id)
+	case activity.JudgeSPOJ:
+		problem, ok = spoj(id), spojCode.MatchString(id)
+	case activity.JudgeKattis:
+		problem, ok = kattis(id), kattisName.MatchString(id)
+	case activity.JudgeCSES:
+		problem, ok = cses(id), digits.MatchString(id)
+	case activity.JudgeBeecrowd:
+		problem, ok = beecrowd(id), digits.MatchString(id)
+	case activity.JudgeUVa:
+		problem, ok = uva(id), uvaIDPattern.MatchString(id)
+	case activity.JudgeVJudge:
+		problem, ok = parseVJudge([]string{"problem", id})
+	default:
+		return Problem{}, ErrNoCanonicalForm
+	}
+	if !ok {
+		return Problem{}, fmt.Errorf("%q is not a %s problem id", id, judge)
+	}
+	return problem, nil
+}
+
+func parseCodeforces(path []string) (Problem, bool) {
+	switch {
+	// /problemset/problem/1352/A
+	case len(path) == 4 && path[0] == "problemset" && path[1] == "problem":
+		return codeforcesProblem(false, path[2], path[3])
+	// /contest/1352/problem/A and /gym/102942/problem/B
+	case len(path) == 4 && (path[0] == "contest" || path[0] == "gym") && path[2] == "problem":
+		return codeforcesProblem(path[0] == "gym", path[1], path[3])
+	// /problemset/gymProblem/102942/B
+	case len(path) == 4 && path[0] == "problemset" && path[1] == "gymProblem":
+		return codeforcesProblem(true, path[2], path[3])
+	}
+	return Problem{}, false
+}
+
+func codeforcesProblem(gym bool, contest, index string) (Problem, bool) {
+	if !digits.MatchString(contest) || !codeforcesIndex.MatchString(index) {
+		return Problem{}, false
+	}
+	return codeforces(gym, contest, index), true
+}
+
+// codeforces IDs are the contest and index, "1352A", with a "gym" prefix for gym problems.
+func codeforces(gym bool, contest, index string) Problem {
+	contest = strings.TrimLeft(contest, "0")
+	index = strings.ToUpper(index)
+	if gym {
+		return Problem{
+			Judge: activity.JudgeCodeforces,
+			ID:    "gym" + contest + index,
+			URL:   fmt.Sprintf("https://codeforces.com/gym/%s/problem/%s", contest, index),
+		}
+	}
+	return Problem{
+		Judge: activity.JudgeCodeforces,
+		ID:    contest + index,
+		URL:   fmt.Sprintf("https://codeforces.com/problemset/problem/%s/%s", contest, index),
+	}
+}
+
+func parseAtCoder(path []string) (Problem, bool) {
+	// /contests/abc300/tasks/abc300_a, optionally with a language prefix such as /en
+	if len(path) > 0 && len(path[0]) == 2 {
+		path = path[1:]
+	}
+	if len(path) != 4 || path[0] != "contests" || path[2] != "tasks" || !atcoderTask.MatchString(path[1]) || !atcoderTask.MatchString(path[3]) {
+		return Problem{}, false
+	}
+	return atcoder(strings.ToLower(path[1]), strings.ToLower(path[3])), true
+}
+
+// atcoder IDs are task IDs, which are unique even when a task is reused in several contests.
+func atcoder(contest, task string) Problem {
+	return Problem{
+		Judge: activity.JudgeAtCoder,
+		ID:    task,
+		URL:   fmt.Sprintf("https://atcoder.jp/contests/%s/tasks/%s", contest, task),
+	}
+}
+
+func parseLeetCode(path []string) (Problem, bool) {
+	// /problems/two-sum, optionally followed by /description, /solutions and the like
+	if len(path) < 2 || path[0] != "problems" || !slug.MatchString(path[1]) {
+		return Problem{}, false
+	}
+	return leetcode(path[1]), true
+}
+
+func leetcode(slug string) Problem {
+	slug = strings.ToLower(slug)
+	return Problem{Judge: activity.JudgeLeetCode, ID: slug, URL: "https://leetcode.com/problems/" + slug + "/"}
+}
+
+func parseSPOJ(path []string) (Problem, bool) {
+	// /problems/PRIME1, or /problems/PRIME1/en
+	if len(path) < 2 || path[0] != "problems" || !spojCode.MatchString(path[1]) {
+		return Problem{}, false
+	}
+	return spoj(path[1]), true
+}
+
+func spoj(code string) Problem {
+	code = strings.ToUpper(code)
+	return Problem{Judge: activity.JudgeSPOJ, ID: code, URL: "https://www.spoj.com/problems/" + code + "/"}
+}
+
+func parseKattis(path []string) (Problem, bool) {
+	// /problems/hello, also inside a contest as /contests/abc123/problems/hello
+	if len(path) == 4 && path[0] == "contests" {
+		path = path[2:]
+	}
+	if len(path) != 2 || path[0] != "problems" || !kattisName.MatchString(path[1]) {
+		return Problem{}, false
+	}
+	return kattis(path[1]), true
+}
+
+func kattis(name string) Problem {
+	name = strings.ToLower(name)
+	return Problem{Judge: activity.JudgeKattis, ID: name, URL: "https://open.kattis.com/problems/" + name}
+}
+
+func parseCSES(path []string) (Problem, bool) {
+	// /problemset/task/1068, or the statement-only /problemset/view/1068
+	if len(path) != 3 || path[0] != "problemset" || (path[1] != "task" && path[1] != "view") || !digits.MatchString(path[2]) {
+		return Problem{}, false
+	}
+	return cses(path[2]), true
+}
+
+func cses(id string) Problem {
+	return Problem{Judge: activity.JudgeCSES, ID: id, URL: "https://cses.fi/problemset/task/" + id}
+}
+
+func parseBeecrowd(path []string) (Problem, bool) {
+	// /repository/UOJ_1001.html on the resources host
+	if len(path) == 2 && path[0] == "repository" {
+		if m := beecrowdResource.FindStringSubmatch(path[1]); m != nil {
+			return beecrowd(m[1]), true
+		}
+		return Problem{}, false
+	}
+	// /judge/en/problems/view/1001 on the old hosts, /en/problems/view/1001 on judge.beecrowd.com
+	if len(path) > 0 && path[0] == "judge" {
+		path = path[1:]
+	}
+	if len(path) != 4 || path[1] != "problems" || path[2] != "view" || !digits.MatchString(path[3]) {
+		return Problem{}, false
+	}
+	return beecrowd(path[3]), true
+}
+
+func beecrowd(id string) Problem {
+	return Problem{Judge: activity.JudgeBeecrowd, ID: id, URL: "https://judge.beecrowd.com/en/problems/view/" + id}
+}
+
+// uvaIDPattern matches UVa problem numbers, and "p" with UVa's internal problem ID for show_problem
+// links, which do not carry the problem number. The two kinds of ID of one problem do not match.
+var uvaIDPattern = regexp.MustCompile(`^p?\d+$`)
+
+func parseUVa(u *url.URL, path []string) (Problem, bool) {
+	// /external/1/100.pdf
+	if len(path) == 3 && path[0] == "external" {
+		if m := uvaPDF.FindStringSubmatch(path[2]); m != nil {
+			return uva(m[1]), true
+		}
+		return Problem{}, false
+	}
+	// /index.php?option=com_onlinejudge&Itemid=8&page=show_problem&problem=36
+	query := u.Query()
+	if len(path) == 1 && path[0] == "index.php" && query.Get("page") == "show_problem" && digits.MatchString(query.Get("problem")) {
+		return uva("p" + query.Get("problem")), true
+	}
+	return Problem{}, false
+}
+
+func uva(id string) Problem {
+	if internal, ok := strings.CutPrefix(id, "p"); ok {
+		return Problem{
+			Judge: activity.JudgeUVa,
+			ID:    id,
+			URL:   "https://onlinejudge.org/index.php?option=com_onlinejudge&Itemid=8&page=show_problem&problem=" + internal,
+		}
+	}
+	return Problem{
+		Judge: activity.JudgeUVa,
+		ID:    id,
+		URL:   uvaPDFURL(id),
+	}
+}
+
+// uvaPDFURL is the statement of a problem number, filed under the number without its last two digits.
+func uvaPDFURL(number string) string {
+	volume := strings.TrimLeft(number[:max(len(number)-2, 0)], "0")
+	if volume == "" {
+		volume = "0"
+	}
+	return fmt.Sprintf("https://onlinejudge.org/external/%s/%s.pdf", volume, number)
+}
+
+// vjudgeOrigins maps vjudge's names of origin judges to ours.
+var vjudgeOrigins = map[string]string{
+	"codeforces": activity.JudgeCodeforces,
+	"gym":        activity.JudgeCodeforces,
+	"atcoder":    activity.JudgeAtCoder,
+	"leetcode":   activity.JudgeLeetCode,
+	"spoj":       activity.JudgeSPOJ,
+	"kattis":     activity.JudgeKattis,
+	"cses":       activity.JudgeCSES,
+	"uva":        activity.JudgeUVa,
+	"uri":        activity.JudgeBeecrowd,
+	"beecrowd":   activity.JudgeBeecrowd,
+}
+
+func parseVJudge(path []string) (Problem, bool) {
+	// /problem/CodeForces-1352A; contest links do not say which problem they are
+	if len(path) != 2 || path[0] != "problem" {
+		return Problem{}, false
+	}
+	m := vjudgeID.FindStringSubmatch(path[1])
+	if m == nil {
+		return Problem{}, false
+	}
+	origin, id := m[1], m[2]
+	if judge, known := vjudgeOrigins[strings.ToLower(origin)]; known {
+		if strings.EqualFold(origin, "gym") {
+			id = "gym" + id
+		}
+		problem, err := Canonical(judge, id)
+		return problem, err == nil
+	}
+	// vjudge does not mind the case of origin names, so HDU-1000 and hdu-1000 are one problem
+	id = strings.ToLower(origin) + "-" + id
+	return Problem{Judge: activity.JudgeVJudge, ID: id, URL: "https://vjudge.net/problem/" + id}, true
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"os"
+	"strconv"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewMailerFromEnv returns an SMTPMailer when SMTP_HOST is set and otherwise a FileMailer
+// writing to MAIL_DIR (default "mail"), which is meant for local development.
+func NewMailerFromEnv() (Mailer, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileMailer(dir), nil
+	}
+
+	port := 587
+	if portStr := os.Getenv("SMTP_PORT"); portStr != "" {
+		parsed, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, err
+		}
+		port = parsed
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@codeck.local"
+	}
+	return NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+}
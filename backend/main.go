@@ -16,16 +16,33 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"time"
+	_ "time/tzdata" // user time zones must resolve in the alpine image, which has no zoneinfo
 
+	"backend/auth"
+	"backend/auth/oauth"
 	"backend/controllers"
+	"backend/judge"
+	"backend/mail"
 
 	"backend/models/activity"
 	"backend/models/comment"
 	"backend/models/group"
+	"backend/models/identity"
+	"backend/models/linkedaccount"
+	"backend/models/pat"
+	"backend/models/problem"
+	"backend/models/reaction"
+	"backend/models/session"
 	"backend/models/user"
 
+	"backend/ratelimit"
 	"backend/routes"
 
 	_ "backend/docs" // docs is generated by swag init command
@@ -37,38 +54,147 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import-problems" {
+		os.Exit(importProblems(os.Args[2:]))
+	}
+
 	r := mux.NewRouter()
 
 	// Add Swagger endpoint
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
-	// Migrate db
-	log.Println("Trying to migrate")
-	dsn := "host=db user=my_usr password=my_pwd dbname=codeck port=5432 sslmode=disable"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db := openDatabase()
+
+	groupModel := group.NewGormGroupModel(db)
+	groupModel.BackfillOwnerRoles()
+	group.DefaultGroupModel = groupModel
+	activity.DefaultActivityModel = activity.NewGormActivityModel(db)
+	user.DefaultUserModel = user.NewGormUserModel(db)
+	comment.DefaultCommentModel = comment.NewGormCommentModel(db)
+	reaction.DefaultReactionModel = reaction.NewGormReactionModel(db)
+	session.DefaultSessionModel = session.NewGormSessionModel(db)
+	identity.DefaultIdentityModel = identity.NewGormIdentityModel(db)
+	pat.DefaultTokenModel = pat.NewGormTokenModel(db)
+	linkedaccount.DefaultLinkedAccountModel = linkedaccount.NewGormLinkedAccountModel(db)
+	problemModel := problem.NewGormProblemModel(db)
+	problemModel.LinkActivities()
+	problem.DefaultProblemModel = problemModel
+
+	tokenManager, err := auth.NewTokenManagerFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
+		log.Fatalf("Failed to configure token signing: %v", err)
 	}
-	if err := db.AutoMigrate(&group.Group{}, &group.GroupMember{}, &group.GroupInvite{}, &activity.Activity{}, &comment.Comment{}, &user.User{}); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	mailer, err := mail.NewMailerFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	providers, err := oauth.RegistryFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure login providers: %v", err)
+	}
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
 	}
-	log.Println("Migration successful")
 
-	group.DefaultGroupModel = group.NewGormGroupModel(db)
-	activity.DefaultActivityModel = activity.NewGormActivityModel(db)
-	user.DefaultUserModel = user.NewGormUserModel(db)
+	rateLimits, err := ratelimit.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure rate limits: %v", err)
+	}
+	limits := ratelimit.NewLimits(ratelimit.NewMemoryStore(), rateLimits)
 
-	groupController := controllers.NewGroupController(group.DefaultGroupModel)
-	activityController := controllers.NewActivityController(activity.DefaultActivityModel)
-	userController := controllers.NewUserController(user.DefaultUserModel, activity.DefaultActivityModel)
-	loginController := controllers.NewLoginController(user.DefaultUserModel)
+	importer := judge.NewImporter(linkedaccount.DefaultLinkedAccountModel, activity.DefaultActivityModel, group.DefaultGroupModel, user.DefaultUserModel, problem.DefaultProblemModel,
+		judge.NewCodeforcesClient(judge.CodeforcesAPIURL, &http.Client{Timeout: 30 * time.Second}),
+		judge.NewAtCoderClient(judge.AtCoderProblemsURL, &http.Client{Timeout: time.Minute}),
+		judge.NewLeetCodeClient(judge.NewHTTPGraphQLClient(judge.LeetCodeGraphQLURL, &http.Client{Timeout: 30 * time.Second})))
+	schedulerConfig, err := judge.SchedulerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure imports: %v", err)
+	}
+	scheduler := judge.NewScheduler(importer, linkedaccount.DefaultLinkedAccountModel, schedulerConfig)
+	go scheduler.Run(context.Background())
 
-	routes.RegisterGroupRoutes(r, groupController)
-	routes.RegisterActivityRoutes(r, activityController)
-	routes.RegisterUserRoutes(r, userController)
-	routes.RegisterLoginRoutes(r, loginController)
+	authenticator := auth.NewAuthenticator(tokenManager, user.DefaultUserModel, session.DefaultSessionModel, pat.DefaultTokenModel)
+
+	groupController := controllers.NewGroupController(group.DefaultGroupModel, reaction.DefaultReactionModel)
+	activityController := controllers.NewActivityController(activity.DefaultActivityModel, group.DefaultGroupModel, problem.DefaultProblemModel)
+	userController := controllers.NewUserController(user.DefaultUserModel, activity.DefaultActivityModel)
+	loginController := controllers.NewLoginController(user.DefaultUserModel, session.DefaultSessionModel, tokenManager)
+	twoFactorController := controllers.NewTwoFactorController(user.DefaultUserModel, loginController)
+	tokenController := controllers.NewTokenController(pat.DefaultTokenModel)
+	oauthController := controllers.NewOAuthController(providers, identity.DefaultIdentityModel, user.DefaultUserModel, loginController)
+	accountController := controllers.NewAccountController(user.DefaultUserModel, session.DefaultSessionModel, mailer, appURL)
+	commentController := controllers.NewCommentController(comment.DefaultCommentModel, activity.DefaultActivityModel, group.DefaultGroupModel)
+	reactionController := controllers.NewReactionController(reaction.DefaultReactionModel, activity.DefaultActivityModel)
+	problemController := controllers.NewProblemController(problem.DefaultProblemModel, group.DefaultGroupModel, activity.DefaultActivityModel)
+	linkedAccountController := controllers.NewLinkedAccountController(linkedaccount.DefaultLinkedAccountModel, group.DefaultGroupModel, importer, scheduler)
+
+	routes.RegisterGroupRoutes(r, groupController, authenticator)
+	routes.RegisterActivityRoutes(r, activityController, authenticator, limits)
+	routes.RegisterUserRoutes(r, userController, authenticator, limits)
+	routes.RegisterLoginRoutes(r, loginController, authenticator, limits)
+	routes.RegisterCommentRoutes(r, commentController, authenticator, limits)
+	routes.RegisterReactionRoutes(r, reactionController, authenticator)
+	routes.RegisterLinkedAccountRoutes(r, linkedAccountController, authenticator)
+	routes.RegisterProblemRoutes(r, problemController, authenticator)
+	routes.RegisterAccountRoutes(r, accountController, authenticator, limits)
+	routes.RegisterOAuthRoutes(r, oauthController)
+	routes.RegisterTokenRoutes(r, tokenController, authenticator)
+	routes.RegisterTwoFactorRoutes(r, twoFactorController, authenticator, limits)
 
 	log.Println("Server is running on port 8080")
 	log.Println("API Documentation available at: http://localhost:8080/swagger/index.html")
 	log.Fatal(http.ListenAndServe(":8080", r))
 
 }
+
+// openDatabase connects to the database and migrates it.
+func openDatabase() *gorm.DB {
+	log.Println("Trying to migrate")
+	dsn := "host=db user=my_usr password=my_pwd dbname=codeck port=5432 sslmode=disable"
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	if err := db.AutoMigrate(&group.Group{}, &group.GroupMember{}, &group.GroupInvite{}, &group.OwnershipTransfer{}, &group.GroupActivity{}, &activity.Activity{}, &comment.Comment{}, &reaction.Reaction{}, &user.User{}, &user.UserToken{}, &user.RecoveryCode{}, &user.StreakFreeze{}, &session.Session{}, &session.RefreshToken{}, &identity.Identity{}, &identity.LoginState{}, &pat.PersonalAccessToken{}, &linkedaccount.LinkedAccount{}, &problem.Problem{}); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	log.Println("Migration successful")
+	return db
+}
+
+// importProblems runs the import-problems command, which adds the problems in a judge's data dump to
+// the problem catalog without calling the judge, and returns the exit code:
+//
+//	server import-problems -judge codeforces problemset.json
+//	server import-problems -judge atcoder [-models problem-models.json] merged-problems.json
+func importProblems(args []string) int {
+	flags := flag.NewFlagSet("import-problems", flag.ContinueOnError)
+	judgeName := flags.String("judge", "", "judge the dump comes from: codeforces or atcoder")
+	modelsPath := flags.String("models", "", "AtCoder Problems problem-models.json with difficulty estimates")
+	batchSize := flags.Int("batch", 500, "problems saved per transaction")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server import-problems -judge codeforces|atcoder [-models file] [-batch n] dump.json")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *batchSize < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	problems, invalid, err := judge.ReadCatalogDump(*judgeName, flags.Arg(0), *modelsPath)
+	if err != nil {
+		log.Printf("Failed to read %s: %v", flags.Arg(0), err)
+		return 1
+	}
+	result, err := problem.NewGormProblemModel(openDatabase()).ImportProblems(problems, *batchSize)
+	result.Skipped += invalid
+	fmt.Printf("created %d, updated %d, skipped %d\n", result.Created, result.Updated, result.Skipped)
+	if err != nil {
+		log.Printf("Import stopped: %v", err)
+		return 1
+	}
+	return 0
+}
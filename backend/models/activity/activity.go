@@ -7,13 +7,30 @@ import (
 )
 
 type Activity struct {
-	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ID            int       `gorm:"primaryKey;autoIncrement;index:idx_activities_date_id,priority:2" json:"id"`
 	CreatorID     int       `gorm:"not null;index" json:"creator_id"`
 	Title         string    `gorm:"type:text;not null" json:"title"`
-	Date          time.Time `gorm:"type:date;not null" json:"date"`
+	Date          time.Time `gorm:"type:date;not null;index:idx_activities_date_id,priority:1" json:"date"`
 	ActivityImage *string   `gorm:"type:text" json:"activity_image,omitempty"`
 	Description   *string   `gorm:"type:text" json:"description,omitempty"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	// The solved problem, when known. Rating is its difficulty on the Codeforces scale (800 to 3500);
+	// Difficulty is the label of judges that grade problems as easy, medium or hard. ProblemKey is set by
+	// the server and identifies the problem whichever URL variant was used, e.g. "codeforces:1352A".
+	// SubmissionID is the judge's ID of the submission an imported activity was created from, and
+	// CatalogProblemID the problem catalog's entry for the problem, also set by the server
+	Judge            *string `gorm:"type:text;index;uniqueIndex:idx_activities_judge_submission,priority:1" json:"judge,omitempty"`
+	ProblemID        *string `gorm:"type:text" json:"problem_id,omitempty"`
+	ProblemURL       *string `gorm:"type:text" json:"problem_url,omitempty"`
+	ProblemKey       *string `gorm:"type:text;index" json:"problem_key,omitempty"`
+	SubmissionID     *string `gorm:"type:text;uniqueIndex:idx_activities_judge_submission,priority:2" json:"submission_id,omitempty"`
+	CatalogProblemID *int    `gorm:"index" json:"catalog_problem_id,omitempty"`
+	Rating           *int    `json:"rating,omitempty"`
+	Difficulty       *string `gorm:"type:text" json:"difficulty,omitempty"`
+	Tags             Tags    `gorm:"type:text" json:"tags,omitempty"`
+	Verdict          *string `gorm:"type:text" json:"verdict,omitempty"`
+	Language         *string `gorm:"type:text" json:"language,omitempty"`
+	TimeSpentMinutes *int    `json:"time_spent_minutes,omitempty"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
 }
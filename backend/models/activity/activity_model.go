@@ -1,11 +1,24 @@
 package activity
 
+import "time"
+
 type ActivityModel interface {
 	GetActivityByID(id int) (Activity, bool)
 	GetActivitiesByCreatorID(creatorID int) []Activity
+	FilterActivitiesByCreatorID(creatorID int, filter Filter) []Activity
 	CreateActivity(a Activity) Activity
 	UpdateActivity(id int, updates map[string]interface{}) (Activity, bool)
 	DeleteActivity(id int) bool
+	// GetActiveDays returns the distinct days creatorID logged activities on, oldest first.
+	GetActiveDays(creatorID int) []time.Time
+	// CountActivitiesByDay returns creatorID's activity count per day between from and to inclusive,
+	// leaving out days without activities.
+	CountActivitiesByDay(creatorID int, from, to time.Time) []DayCount
+	// GetActivityBySubmission finds the activity imported from a judge's submission, including deleted ones.
+	GetActivityBySubmission(judge, submissionID string) (Activity, bool)
+	// HasProblem reports whether creatorID ever logged an activity on the problem with problemKey, counting
+	// deleted ones so that a deleted import does not come back with the next submission.
+	HasProblem(creatorID int, problemKey string) bool
 }
 
 // DefaultActivityModel must be set in main.go after DB initialization
@@ -28,6 +28,24 @@ func (m *GormActivityModel) GetActivitiesByCreatorID(creatorID int) []Activity {
 	return list
 }
 
+func (m *GormActivityModel) FilterActivitiesByCreatorID(creatorID int, filter Filter) []Activity {
+	query := m.db.Where("creator_id = ?", creatorID)
+	if filter.Judge != "" {
+		query = query.Where("judge = ?", filter.Judge)
+	}
+	if filter.Tag != "" {
+		// Tags are stored as a JSON array, and valid tags cannot contain quotes or wildcards
+		query = query.Where("tags LIKE ?", `%"`+filter.Tag+`"%`)
+	}
+	if filter.MinRating != nil {
+		query = query.Where("rating >= ?", *filter.MinRating)
+	}
+
+	var list []Activity
+	query.Find(&list)
+	return list
+}
+
 func (m *GormActivityModel) CreateActivity(a Activity) Activity {
 	m.db.Create(&a)
 	return a
@@ -39,6 +57,7 @@ func (m *GormActivityModel) UpdateActivity(id int, updates map[string]interface{
 		return Activity{}, false
 	}
 	m.db.Model(&a).Updates(updates)
+	m.db.First(&a, "id = ?", id)
 	return a, true
 }
 
@@ -49,6 +68,37 @@ func (m *GormActivityModel) DeleteActivity(id int) bool {
 	return true
 }
 
+func (m *GormActivityModel) GetActiveDays(creatorID int) []time.Time {
+	var days []time.Time
+	m.db.Model(&Activity{}).Where("creator_id = ?", creatorID).Distinct("date").Order("date").Pluck("date", &days)
+	return days
+}
+
+func (m *GormActivityModel) CountActivitiesByDay(creatorID int, from, to time.Time) []DayCount {
+	var counts []DayCount
+	m.db.Model(&Activity{}).
+		Select("date, COUNT(*) AS count").
+		Where("creator_id = ? AND date BETWEEN ? AND ?", creatorID, from, to).
+		Group("date").
+		Order("date").
+		Scan(&counts)
+	return counts
+}
+
+func (m *GormActivityModel) GetActivityBySubmission(judge, submissionID string) (Activity, bool) {
+	var a Activity
+	if err := m.db.Unscoped().Where("judge = ? AND submission_id = ?", judge, submissionID).First(&a).Error; err != nil {
+		return Activity{}, false
+	}
+	return a, true
+}
+
+func (m *GormActivityModel) HasProblem(creatorID int, problemKey string) bool {
+	var count int64
+	m.db.Unscoped().Model(&Activity{}).Where("creator_id = ? AND problem_key = ?", creatorID, problemKey).Count(&count)
+	return count > 0
+}
+
 func (m *GormActivityModel) Clear() {
 	m.db.Exec("DELETE FROM activities")
 	m.db.Exec("ALTER SEQUENCE activities_id_seq RESTART WITH 1")
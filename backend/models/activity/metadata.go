@@ -0,0 +1,222 @@
+package activity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Online judges an activity's problem can come from. JudgeVJudge is for vjudge problems whose origin
+// judge is not one of the others.
+const (
+	JudgeCodeforces = "codeforces"
+	JudgeAtCoder    = "atcoder"
+	JudgeLeetCode   = "leetcode"
+	JudgeCSES       = "cses"
+	JudgeSPOJ       = "spoj"
+	JudgeBeecrowd   = "beecrowd"
+	JudgeKattis     = "kattis"
+	JudgeUVa        = "uva"
+	JudgeVJudge     = "vjudge"
+	JudgeOther      = "other"
+)
+
+var Judges = []string{JudgeCodeforces, JudgeAtCoder, JudgeLeetCode, JudgeCSES, JudgeSPOJ, JudgeBeecrowd, JudgeKattis, JudgeUVa, JudgeVJudge, JudgeOther}
+
+// Difficulty labels, for judges that grade problems by label rather than by rating.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+var Difficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// Verdicts of the submission an activity records.
+const (
+	VerdictAccepted            = "accepted"
+	VerdictWrongAnswer         = "wrong_answer"
+	VerdictTimeLimitExceeded   = "time_limit_exceeded"
+	VerdictMemoryLimitExceeded = "memory_limit_exceeded"
+	VerdictRuntimeError        = "runtime_error"
+	VerdictCompilationError    = "compilation_error"
+	VerdictOther               = "other"
+)
+
+var Verdicts = []string{VerdictAccepted, VerdictWrongAnswer, VerdictTimeLimitExceeded, VerdictMemoryLimitExceeded, VerdictRuntimeError, VerdictCompilationError, VerdictOther}
+
+// SolvedCondition matches the activities that count as solving their problem: accepted ones, and ones
+// without a verdict, which users log for problems they solved.
+const SolvedCondition = "(activities.verdict IS NULL OR activities.verdict = '" + VerdictAccepted + "')"
+
+// Solved reports whether the activity counts as solving its problem, see SolvedCondition.
+func (a Activity) Solved() bool {
+	return a.Verdict == nil || *a.Verdict == VerdictAccepted
+}
+
+const (
+	MaxTags            = 20
+	maxTagLength       = 50
+	maxProblemIDLength = 50
+	maxLanguageLength  = 50
+	maxURLLength       = 2048
+	// maxTimeSpentMinutes is a full day
+	maxTimeSpentMinutes = 24 * 60
+)
+
+// Tags are stored as a JSON array in a text column. Unlike a serializer tag, the Valuer also applies to
+// map updates, which is how activities are updated.
+type Tags []string
+
+func (t Tags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]string(t))
+	return string(b), err
+}
+
+func (t *Tags) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(t))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(t))
+	default:
+		return fmt.Errorf("cannot scan %T into Tags", value)
+	}
+}
+
+// NormalizeTag lowercases and trims tag so the same tag is always stored the same way.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// ValidTag reports whether a normalized tag is short enough and only uses letters, digits, spaces and - + * # .
+// Leaving out quotes and SQL wildcards keeps tags safe to match inside the stored JSON.
+func ValidTag(tag string) bool {
+	if tag == "" || len(tag) > maxTagLength {
+		return false
+	}
+	for _, r := range tag {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', strings.ContainsRune(" -+*#.", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func ValidJudge(judge string) bool {
+	return contains(Judges, judge)
+}
+
+// Normalize lowercases the labelled fields and tidies up tags, dropping duplicates.
+func (a *Activity) Normalize() {
+	for _, field := range []*string{a.Judge, a.Difficulty, a.Verdict} {
+		if field != nil {
+			*field = strings.ToLower(strings.TrimSpace(*field))
+		}
+	}
+	for _, field := range []*string{a.ProblemID, a.ProblemURL, a.Language} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	if a.Tags != nil {
+		tags := Tags{}
+		seen := map[string]bool{}
+		for _, tag := range a.Tags {
+			tag = NormalizeTag(tag)
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+		a.Tags = tags
+	}
+}
+
+// Validate checks the problem metadata of a normalized activity.
+func (a Activity) Validate() error {
+	if a.Judge != nil && !ValidJudge(*a.Judge) {
+		return fmt.Errorf("judge must be one of: %s", strings.Join(Judges, ", "))
+	}
+	if a.ProblemID != nil {
+		if a.Judge == nil {
+			return errors.New("problem_id requires a judge")
+		}
+		if *a.ProblemID == "" || len(*a.ProblemID) > maxProblemIDLength || strings.ContainsAny(*a.ProblemID, " \t\n") {
+			return errors.New("problem_id must be a single word of at most 50 characters")
+		}
+	}
+	if a.ProblemURL != nil {
+		u, err := url.Parse(*a.ProblemURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(*a.ProblemURL) > maxURLLength {
+			return errors.New("problem_url must be an http or https URL")
+		}
+	}
+	if a.Rating != nil && *a.Rating <= 0 {
+		return errors.New("rating must be positive")
+	}
+	if a.Difficulty != nil && !contains(Difficulties, *a.Difficulty) {
+		return fmt.Errorf("difficulty must be one of: %s", strings.Join(Difficulties, ", "))
+	}
+	if len(a.Tags) > MaxTags {
+		return fmt.Errorf("an activity can have at most %d tags", MaxTags)
+	}
+	for _, tag := range a.Tags {
+		if !ValidTag(tag) {
+			return fmt.Errorf("invalid tag %q", tag)
+		}
+	}
+	if a.Verdict != nil && !contains(Verdicts, *a.Verdict) {
+		return fmt.Errorf("verdict must be one of: %s", strings.Join(Verdicts, ", "))
+	}
+	if a.Language != nil && len(*a.Language) > maxLanguageLength {
+		return errors.New("language cannot be longer than 50 characters")
+	}
+	if a.TimeSpentMinutes != nil && (*a.TimeSpentMinutes <= 0 || *a.TimeSpentMinutes > maxTimeSpentMinutes) {
+		return errors.New("time_spent_minutes must be between 1 and 1440")
+	}
+	return nil
+}
+
+// Metadata returns the problem metadata columns with their values, for map updates.
+func (a Activity) Metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"judge":              a.Judge,
+		"problem_id":         a.ProblemID,
+		"problem_url":        a.ProblemURL,
+		"rating":             a.Rating,
+		"difficulty":         a.Difficulty,
+		"tags":               a.Tags,
+		"verdict":            a.Verdict,
+		"language":           a.Language,
+		"time_spent_minutes": a.TimeSpentMinutes,
+	}
+}
+
+// Filter narrows down a list of activities; zero fields do not filter.
+type Filter struct {
+	Judge     string
+	Tag       string
+	MinRating *int
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package activity
+
+import "time"
+
+// DayLayout is the format of calendar days in requests and responses.
+const DayLayout = "2006-01-02"
+
+// DayCount is the number of activities a user logged on one day.
+type DayCount struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// Streak summarises a user's run of consecutive active days.
+type Streak struct {
+	Current       int
+	Longest       int
+	LastActiveDay *time.Time
+}
+
+// Day truncates t to midnight UTC of its calendar day, the form activity dates are stored in.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the current calendar day in loc as a Day.
+func Today(now time.Time, loc *time.Location) time.Time {
+	return Day(now.In(loc))
+}
+
+// ComputeStreak counts consecutive active days. A frozen day keeps a streak going without adding
+// to it. The current streak is still alive when today has no activity yet but yesterday did.
+func ComputeStreak(activeDays, frozenDays []time.Time, today time.Time) Streak {
+	active := map[time.Time]bool{}
+	var first, last time.Time
+	for _, d := range activeDays {
+		d = Day(d)
+		active[d] = true
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+	frozen := map[time.Time]bool{}
+	for _, d := range frozenDays {
+		frozen[Day(d)] = true
+	}
+
+	var streak Streak
+	if len(active) == 0 {
+		return streak
+	}
+	streak.LastActiveDay = &last
+
+	run := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		switch {
+		case active[d]:
+			run++
+			if run > streak.Longest {
+				streak.Longest = run
+			}
+		case !frozen[d]:
+			run = 0
+		}
+	}
+
+	d := today
+	if !active[d] && !frozen[d] {
+		d = d.AddDate(0, 0, -1)
+	}
+	for ; !d.Before(first) && (active[d] || frozen[d]); d = d.AddDate(0, 0, -1) {
+		if active[d] {
+			streak.Current++
+		}
+	}
+	return streak
+}
+
+// CalendarDay is one day of a user's activity calendar.
+type CalendarDay struct {
+	Date   time.Time `json:"date"`
+	Count  int       `json:"count"`
+	Frozen bool      `json:"frozen"`
+}
+
+// BuildCalendar lists every day from from to to inclusive with its activity count and whether it was frozen.
+func BuildCalendar(counts []DayCount, frozenDays []time.Time, from, to time.Time) []CalendarDay {
+	byDay := map[time.Time]int{}
+	for _, c := range counts {
+		byDay[Day(c.Date)] += c.Count
+	}
+	frozen := map[time.Time]bool{}
+	for _, d := range frozenDays {
+		frozen[Day(d)] = true
+	}
+
+	days := []CalendarDay{}
+	for d := Day(from); !d.After(Day(to)); d = d.AddDate(0, 0, 1) {
+		days = append(days, CalendarDay{Date: d, Count: byDay[d], Frozen: frozen[d]})
+	}
+	return days
+}
@@ -0,0 +1,60 @@
+package group
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/models/reaction"
+)
+
+// FeedCursor marks where a feed page ended; the next page starts with the activity after it.
+type FeedCursor struct {
+	Date       time.Time
+	ActivityID int
+}
+
+// Encode turns the cursor into the opaque string handed to clients.
+func (c FeedCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s/%d", c.Date.Format("2006-01-02"), c.ActivityID)))
+}
+
+// ParseFeedCursor reads a cursor made by Encode.
+func ParseFeedCursor(s string) (FeedCursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return FeedCursor{}, false
+	}
+	date, id, found := strings.Cut(string(raw), "/")
+	if !found {
+		return FeedCursor{}, false
+	}
+	var c FeedCursor
+	if c.Date, err = time.Parse("2006-01-02", date); err != nil {
+		return FeedCursor{}, false
+	}
+	if _, err := fmt.Sscanf(id, "%d", &c.ActivityID); err != nil || c.ActivityID <= 0 {
+		return FeedCursor{}, false
+	}
+	return c, true
+}
+
+// FeedGroup is a group an activity in the feed is posted to, with the author's nickname there.
+type FeedGroup struct {
+	ID             int     `json:"id"`
+	Name           string  `json:"name"`
+	AuthorNickname *string `json:"author_nickname,omitempty"`
+}
+
+// FeedItem is an activity in a user's feed. It appears once even when posted to several of the user's groups.
+type FeedItem struct {
+	PostedActivity
+	Groups    []FeedGroup        `json:"groups"`
+	Reactions []reaction.Summary `json:"reactions"`
+}
+
+// Cursor returns the cursor for the page that follows this item.
+func (item FeedItem) Cursor() FeedCursor {
+	return FeedCursor{Date: item.Date, ActivityID: item.ID}
+}
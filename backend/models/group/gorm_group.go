@@ -5,6 +5,9 @@ import (
 	"encoding/base64"
 	"time"
 
+	"backend/models/activity"
+	"backend/models/reaction"
+
 	"gorm.io/gorm"
 )
 
@@ -46,7 +49,12 @@ func (m *GormGroupModel) DeleteGroup(id int) bool {
 }
 
 func (m *GormGroupModel) AddUserToGroup(groupID, userID int) bool {
-	member := GroupMember{UserID: userID, GroupID: groupID}
+	member := GroupMember{UserID: userID, GroupID: groupID, Role: RoleMember}
+	// The creator joins their own group as its owner
+	var g Group
+	if err := m.db.First(&g, "id = ?", groupID).Error; err == nil && g.CreatorID == userID {
+		member.Role = RoleOwner
+	}
 	if err := m.db.Create(&member).Error; err != nil {
 		return false
 	}
@@ -66,6 +74,16 @@ func (m *GormGroupModel) GetGroupMembers(groupID int) ([]GroupMember, bool) {
 	return members, true
 }
 
+func (m *GormGroupModel) GetMemberProfiles(groupID int) []MemberProfile {
+	var profiles []MemberProfile
+	m.db.Table("group_members").
+		Select("group_members.*, users.name AS name").
+		Joins("LEFT JOIN users ON users.id = group_members.user_id").
+		Where("group_members.group_id = ?", groupID).
+		Scan(&profiles)
+	return profiles
+}
+
 func (m *GormGroupModel) IsUserInGroup(groupID, userID int) bool {
 	var member GroupMember
 	if err := m.db.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
@@ -74,6 +92,30 @@ func (m *GormGroupModel) IsUserInGroup(groupID, userID int) bool {
 	return true
 }
 
+func (m *GormGroupModel) GetMember(groupID, userID int) (GroupMember, bool) {
+	var member GroupMember
+	if err := m.db.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+		return GroupMember{}, false
+	}
+	return member, true
+}
+
+func (m *GormGroupModel) GetMembershipsByUserID(userID int) []GroupMember {
+	var members []GroupMember
+	m.db.Where("user_id = ?", userID).Find(&members)
+	return members
+}
+
+func (m *GormGroupModel) SetMemberRole(groupID, userID int, role string) bool {
+	result := m.db.Model(&GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Update("role", role)
+	return result.RowsAffected > 0
+}
+
+// BackfillOwnerRoles makes every creator the owner of their group, for members that joined before roles existed.
+func (m *GormGroupModel) BackfillOwnerRoles() {
+	m.db.Exec("UPDATE group_members SET role = ? WHERE role <> ? AND EXISTS (SELECT 1 FROM groups WHERE groups.id = group_members.group_id AND groups.creator_id = group_members.user_id)", RoleOwner, RoleOwner)
+}
+
 func (m *GormGroupModel) SetUserNickname(groupID, userID int, nickname *string) bool {
 	result := m.db.Model(&GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Update("nickname", nickname)
 	return result.RowsAffected > 0
@@ -128,9 +170,214 @@ func (m *GormGroupModel) GetActiveInvites(groupID int) []GroupInvite {
 	return invites
 }
 
-func (m *GormGroupModel) GetGroupActivities(groupID int) ([]string, bool) {
-	// This would require a join table or relation in a real schema
-	return []string{}, true // Placeholder
+// GetGroupActivities lists the activities posted to the group, newest first. Activities dated outside
+// the group's start and end dates do not count for it and are left out.
+func (m *GormGroupModel) GetGroupActivities(groupID int) ([]PostedActivity, bool) {
+	return m.postedActivities(groupID, false)
+}
+
+func (m *GormGroupModel) GetGroupSolves(groupID int) ([]PostedActivity, bool) {
+	return m.postedActivities(groupID, true)
+}
+
+// postedActivities returns the activities posted to the group within its dates, only solves when solvedOnly.
+func (m *GormGroupModel) postedActivities(groupID int, solvedOnly bool) ([]PostedActivity, bool) {
+	if _, exists := m.GetGroupByID(groupID); !exists {
+		return nil, false
+	}
+
+	query := m.db.Table("activities")
+	if solvedOnly {
+		query = query.Where(activity.SolvedCondition)
+	}
+	var posted []PostedActivity
+	err := query.
+		Select("activities.*, users.id AS author_id, users.name AS author_name, "+
+			"(SELECT COUNT(*) FROM comments WHERE comments.activity_id = activities.id AND comments.deleted_at IS NULL) AS comment_count").
+		Joins("JOIN group_activities ON group_activities.activity_id = activities.id").
+		Joins("JOIN groups ON groups.id = group_activities.group_id").
+		Joins("LEFT JOIN users ON users.id = activities.creator_id AND users.deleted_at IS NULL").
+		Where("group_activities.group_id = ? AND activities.deleted_at IS NULL", groupID).
+		Where("activities.date BETWEEN groups.start_date AND groups.end_date").
+		Order("activities.date DESC, activities.id DESC").
+		Scan(&posted).Error
+	if err != nil {
+		return nil, false
+	}
+	return posted, true
+}
+
+// GetFeed pages through the activities posted to any group userID is in, newest first. The page is
+// picked by walking activities in (date, id) order, so its cost depends on the page size rather than
+// on how many activities the groups have. Reactions are left for the caller to fill in.
+func (m *GormGroupModel) GetFeed(userID int, after *FeedCursor, limit int) []FeedItem {
+	query := m.db.Table("activities").
+		Where("activities.deleted_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM group_activities "+
+			"JOIN group_members ON group_members.group_id = group_activities.group_id AND group_members.user_id = ? "+
+			"JOIN groups ON groups.id = group_activities.group_id AND groups.deleted_at IS NULL "+
+			"WHERE group_activities.activity_id = activities.id AND activities.date BETWEEN groups.start_date AND groups.end_date)", userID)
+	if after != nil {
+		query = query.Where("activities.date < ? OR (activities.date = ? AND activities.id < ?)", after.Date, after.Date, after.ActivityID)
+	}
+
+	var activityIDs []int
+	if err := query.Order("activities.date DESC, activities.id DESC").Limit(limit).Pluck("activities.id", &activityIDs).Error; err != nil || len(activityIDs) == 0 {
+		return []FeedItem{}
+	}
+
+	var posted []PostedActivity
+	m.db.Table("activities").
+		Select("activities.*, users.id AS author_id, users.name AS author_name, "+
+			"(SELECT COUNT(*) FROM comments WHERE comments.activity_id = activities.id AND comments.deleted_at IS NULL) AS comment_count").
+		Joins("LEFT JOIN users ON users.id = activities.creator_id AND users.deleted_at IS NULL").
+		Where("activities.id IN ?", activityIDs).
+		Order("activities.date DESC, activities.id DESC").
+		Scan(&posted)
+
+	var links []struct {
+		ActivityID int
+		FeedGroup
+	}
+	m.db.Table("group_activities").
+		Select("group_activities.activity_id, groups.id, groups.name, authors.nickname AS author_nickname").
+		Joins("JOIN groups ON groups.id = group_activities.group_id AND groups.deleted_at IS NULL").
+		Joins("JOIN group_members ON group_members.group_id = groups.id AND group_members.user_id = ?", userID).
+		Joins("JOIN activities ON activities.id = group_activities.activity_id").
+		Joins("LEFT JOIN group_members authors ON authors.group_id = groups.id AND authors.user_id = activities.creator_id").
+		Where("group_activities.activity_id IN ?", activityIDs).
+		Where("activities.date BETWEEN groups.start_date AND groups.end_date").
+		Order("groups.id").
+		Scan(&links)
+
+	groups := map[int][]FeedGroup{}
+	for _, link := range links {
+		groups[link.ActivityID] = append(groups[link.ActivityID], link.FeedGroup)
+	}
+
+	items := make([]FeedItem, len(posted))
+	for i, p := range posted {
+		items[i] = FeedItem{PostedActivity: p, Groups: groups[p.ID], Reactions: []reaction.Summary{}}
+	}
+	return items
+}
+
+func (m *GormGroupModel) AddActivityToGroup(groupID, activityID int) bool {
+	link := GroupActivity{GroupID: groupID, ActivityID: activityID}
+	return m.db.Create(&link).Error == nil
+}
+
+func (m *GormGroupModel) CreatePostedActivity(a activity.Activity, groupIDs []int) (activity.Activity, bool) {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&a).Error; err != nil {
+			return err
+		}
+		for _, groupID := range groupIDs {
+			if err := tx.Create(&GroupActivity{GroupID: groupID, ActivityID: a.ID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return activity.Activity{}, false
+	}
+	return a, true
+}
+
+func (m *GormGroupModel) RemoveActivityFromGroup(groupID, activityID int) bool {
+	result := m.db.Delete(&GroupActivity{}, "group_id = ? AND activity_id = ?", groupID, activityID)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormGroupModel) GetActivityGroupIDs(activityID int) []int {
+	var groupIDs []int
+	m.db.Model(&GroupActivity{}).
+		Joins("JOIN groups ON groups.id = group_activities.group_id AND groups.deleted_at IS NULL").
+		Where("group_activities.activity_id = ?", activityID).
+		Order("group_activities.group_id").
+		Pluck("group_activities.group_id", &groupIDs)
+	return groupIDs
+}
+
+// CreateOwnershipTransfer nominates toUserID as the next owner, replacing any nomination still pending.
+func (m *GormGroupModel) CreateOwnershipTransfer(groupID, fromUserID, toUserID int) (OwnershipTransfer, bool) {
+	transfer := OwnershipTransfer{GroupID: groupID, FromUserID: fromUserID, ToUserID: toUserID, Status: TransferPending}
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&OwnershipTransfer{}).
+			Where("group_id = ? AND status = ?", groupID, TransferPending).
+			Updates(map[string]interface{}{"status": TransferCancelled, "responded_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&transfer).Error
+	})
+	if err != nil {
+		return OwnershipTransfer{}, false
+	}
+	return transfer, true
+}
+
+func (m *GormGroupModel) GetPendingOwnershipTransfer(groupID int) (OwnershipTransfer, bool) {
+	var transfer OwnershipTransfer
+	if err := m.db.Where("group_id = ? AND status = ?", groupID, TransferPending).First(&transfer).Error; err != nil {
+		return OwnershipTransfer{}, false
+	}
+	return transfer, true
+}
+
+func (m *GormGroupModel) GetOwnershipTransfers(groupID int) []OwnershipTransfer {
+	var transfers []OwnershipTransfer
+	m.db.Where("group_id = ?", groupID).Order("created_at DESC, id DESC").Find(&transfers)
+	return transfers
+}
+
+// AcceptOwnershipTransfer hands the group to the nominee: they become its creator and owner, and the
+// previous owner stays on as an admin.
+func (m *GormGroupModel) AcceptOwnershipTransfer(transferID int) bool {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var transfer OwnershipTransfer
+		if err := tx.First(&transfer, "id = ?", transferID).Error; err != nil {
+			return err
+		}
+
+		// The status check lets only one of two concurrent answers through
+		result := tx.Model(&OwnershipTransfer{}).
+			Where("id = ? AND status = ?", transferID, TransferPending).
+			Updates(map[string]interface{}{"status": TransferAccepted, "responded_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(&Group{}).Where("id = ?", transfer.GroupID).Update("creator_id", transfer.ToUserID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&GroupMember{}).
+			Where("group_id = ? AND user_id = ?", transfer.GroupID, transfer.FromUserID).
+			Update("role", RoleAdmin).Error; err != nil {
+			return err
+		}
+		result = tx.Model(&GroupMember{}).
+			Where("group_id = ? AND user_id = ?", transfer.GroupID, transfer.ToUserID).
+			Update("role", RoleOwner)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	return err == nil
+}
+
+func (m *GormGroupModel) CloseOwnershipTransfer(transferID int, status string) bool {
+	result := m.db.Model(&OwnershipTransfer{}).
+		Where("id = ? AND status = ?", transferID, TransferPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now()})
+	return result.Error == nil && result.RowsAffected > 0
 }
 
 func (m *GormGroupModel) Clear() {
@@ -138,6 +385,9 @@ func (m *GormGroupModel) Clear() {
 	m.db.Exec("ALTER SEQUENCE groups_id_seq RESTART WITH 1")
 	m.db.Exec("DELETE FROM group_invites")
 	m.db.Exec("DELETE FROM group_members")
+	m.db.Exec("DELETE FROM group_activities")
+	m.db.Exec("DELETE FROM ownership_transfers")
+	m.db.Exec("ALTER SEQUENCE ownership_transfers_id_seq RESTART WITH 1")
 }
 
 func (m *GormGroupModel) SeedDefaultData() {
@@ -3,26 +3,57 @@ package group
 import (
 	"time"
 
+	"backend/models/activity"
+
 	"gorm.io/gorm"
 )
 
 type Group struct {
-	ID          int            `gorm:"primaryKey;autoIncrement" json:"id"`
-	CreatorID   int            `gorm:"not null;index" json:"creator_id"`
-	Name        string         `gorm:"type:text;not null" json:"name"`
-	StartDate   time.Time      `gorm:"type:date;not null" json:"start_date"`
-	EndDate     time.Time      `gorm:"type:date;not null" json:"end_date"`
-	GroupImage  *string        `gorm:"type:text" json:"group_image,omitempty"`
-	Description *string        `gorm:"type:text" json:"description,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatorID   int       `gorm:"not null;index" json:"creator_id"`
+	Name        string    `gorm:"type:text;not null" json:"name"`
+	StartDate   time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate     time.Time `gorm:"type:date;not null" json:"end_date"`
+	GroupImage  *string   `gorm:"type:text" json:"group_image,omitempty"`
+	Description *string   `gorm:"type:text" json:"description,omitempty"`
+	// RequireVerifiedEmail keeps users without a verified email address from joining through invites
+	RequireVerifiedEmail bool `gorm:"not null;default:false" json:"require_verified_email"`
+	// ScoringMode decides how the leaderboard ranks members, see the Scoring constants
+	ScoringMode string `gorm:"type:text;not null;default:activities" json:"scoring_mode"`
+	// Goals the group works towards over its date window, see the Goal constants; nil means not set
+	GoalTotalActivities     *int           `json:"goal_total_activities,omitempty"`
+	GoalActivitiesPerMember *int           `json:"goal_activities_per_member,omitempty"`
+	GoalActiveDaysPerWeek   *int           `json:"goal_active_days_per_week,omitempty"`
+	CreatedAt               time.Time      `json:"created_at"`
+	UpdatedAt               time.Time      `json:"updated_at"`
+	DeletedAt               gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type GroupMember struct {
 	UserID   int     `gorm:"not null;index" json:"user_id"`
 	GroupID  int     `gorm:"not null;index" json:"group_id"`
 	Nickname *string `gorm:"type:text" json:"nickname,omitempty"`
+	Role     string  `gorm:"type:text;not null;default:member" json:"role"`
+}
+
+// GroupActivity posts an activity to a group. An activity can be posted to any of the groups its creator is in.
+type GroupActivity struct {
+	GroupID    int       `gorm:"primaryKey;autoIncrement:false" json:"group_id"`
+	ActivityID int       `gorm:"primaryKey;autoIncrement:false;index" json:"activity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ActivityAuthor is the public part of the user who created an activity.
+type ActivityAuthor struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// PostedActivity is an activity as listed in a group, with its author and number of comments.
+type PostedActivity struct {
+	activity.Activity
+	Author       ActivityAuthor `gorm:"embedded;embeddedPrefix:author_" json:"author"`
+	CommentCount int            `json:"comment_count"`
 }
 
 type GroupInvite struct {
@@ -34,3 +65,23 @@ type GroupInvite struct {
 	IsActive   bool           `gorm:"default:true" json:"is_active"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// Statuses of an OwnershipTransfer. Only a pending transfer can be accepted, declined or cancelled.
+const (
+	TransferPending   = "pending"
+	TransferAccepted  = "accepted"
+	TransferDeclined  = "declined"
+	TransferCancelled = "cancelled"
+)
+
+// OwnershipTransfer is the owner's nomination of another member as the new owner. Rows are kept after
+// they are answered as the group's record of handovers.
+type OwnershipTransfer struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID     int        `gorm:"not null;index" json:"group_id"`
+	FromUserID  int        `gorm:"not null" json:"from_user_id"`
+	ToUserID    int        `gorm:"not null;index" json:"to_user_id"`
+	Status      string     `gorm:"type:text;not null;default:pending" json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
@@ -1,5 +1,7 @@
 package group
 
+import "backend/models/activity"
+
 type GroupModel interface {
 	GetGroupByID(id int) (Group, bool)
 	CreateGroup(group Group) Group
@@ -8,14 +10,33 @@ type GroupModel interface {
 	AddUserToGroup(groupID, userID int) bool
 	RemoveUserFromGroup(groupID, userID int) bool
 	GetGroupMembers(groupID int) ([]GroupMember, bool)
+	GetMemberProfiles(groupID int) []MemberProfile
 	IsUserInGroup(groupID, userID int) bool
+	GetMember(groupID, userID int) (GroupMember, bool)
+	GetMembershipsByUserID(userID int) []GroupMember
+	SetMemberRole(groupID, userID int, role string) bool
 	SetUserNickname(groupID, userID int, nickname *string) bool
 	DeleteUserNickname(groupID, userID int) bool
 	CreateInviteLink(groupID, createdBy int, expiresAt *string) (GroupInvite, bool)
 	GetInviteByCode(inviteCode string) (GroupInvite, bool)
 	DeactivateInvite(inviteCode string) bool
 	GetActiveInvites(groupID int) []GroupInvite
-	GetGroupActivities(groupID int) ([]string, bool)
+	GetGroupActivities(groupID int) ([]PostedActivity, bool)
+	// GetGroupSolves is GetGroupActivities without the activities that did not solve their problem, see
+	// activity.SolvedCondition. Leaderboards and goals only count these.
+	GetGroupSolves(groupID int) ([]PostedActivity, bool)
+	AddActivityToGroup(groupID, activityID int) bool
+	// CreatePostedActivity creates an activity posted to groupIDs, all or nothing.
+	CreatePostedActivity(a activity.Activity, groupIDs []int) (activity.Activity, bool)
+	RemoveActivityFromGroup(groupID, activityID int) bool
+	GetActivityGroupIDs(activityID int) []int
+	// GetFeed returns up to limit activities posted to userID's groups, newest first, starting after the cursor.
+	GetFeed(userID int, after *FeedCursor, limit int) []FeedItem
+	CreateOwnershipTransfer(groupID, fromUserID, toUserID int) (OwnershipTransfer, bool)
+	GetPendingOwnershipTransfer(groupID int) (OwnershipTransfer, bool)
+	GetOwnershipTransfers(groupID int) []OwnershipTransfer
+	AcceptOwnershipTransfer(transferID int) bool
+	CloseOwnershipTransfer(transferID int, status string) bool
 }
 
 // DefaultGroupModel must be set in main.go after DB initialization
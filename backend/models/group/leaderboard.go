@@ -0,0 +1,129 @@
+package group
+
+import (
+	"sort"
+	"strings"
+
+	"backend/models/activity"
+)
+
+// Ways a group can score its members over the group's date window.
+const (
+	// ScoringActivities counts every activity
+	ScoringActivities = "activities"
+	// ScoringActiveDays counts the distinct days with at least one activity, like a gym check-in
+	ScoringActiveDays = "active_days"
+	// ScoringDifficulty gives each activity a hundredth of its rating in points, see ActivityPoints
+	ScoringDifficulty = "difficulty"
+)
+
+// unratedRating is the rating assumed for activities without one, the easiest Codeforces rating.
+const unratedRating = 800
+
+// difficultyRatings are the ratings assumed for activities graded by label instead, as on LeetCode.
+var difficultyRatings = map[string]int{
+	activity.DifficultyEasy:   1000,
+	activity.DifficultyMedium: 1500,
+	activity.DifficultyHard:   2000,
+}
+
+// ValidScoringMode reports whether mode is one of the Scoring constants.
+func ValidScoringMode(mode string) bool {
+	return mode == ScoringActivities || mode == ScoringActiveDays || mode == ScoringDifficulty
+}
+
+// MemberProfile is a group member with the name of their account.
+type MemberProfile struct {
+	GroupMember
+	Name string `json:"name"`
+}
+
+// DisplayName is the member's nickname in the group, or their account name without one.
+func (p MemberProfile) DisplayName() string {
+	if p.Nickname != nil && *p.Nickname != "" {
+		return *p.Nickname
+	}
+	return p.Name
+}
+
+type LeaderboardEntry struct {
+	Rank          int    `json:"rank"`
+	UserID        int    `json:"user_id"`
+	DisplayName   string `json:"display_name"`
+	Score         int    `json:"score"`
+	ActivityCount int    `json:"activity_count"`
+	ActiveDays    int    `json:"active_days"`
+	Points        int    `json:"points"`
+}
+
+// ActivityPoints is what an activity with rating or, without one, difficulty is worth under ScoringDifficulty.
+func ActivityPoints(rating *int, difficulty *string) int {
+	r := unratedRating
+	if rating != nil && *rating > 0 {
+		r = *rating
+	} else if difficulty != nil && difficultyRatings[*difficulty] > 0 {
+		r = difficultyRatings[*difficulty]
+	}
+	if r < 100 {
+		return 1
+	}
+	return r / 100
+}
+
+// BuildLeaderboard ranks members by their activities under mode. activities must already be limited to the
+// group's date window and to solves. Only current members are ranked: activities of members who left are
+// ignored, and members without activities score zero. Equal scores share a rank (1, 2, 2, 4) and are
+// ordered by display name, then user id, so the result does not depend on query order.
+func BuildLeaderboard(members []MemberProfile, activities []PostedActivity, mode string) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, len(members))
+	byUser := make(map[int]*LeaderboardEntry, len(members))
+	days := make(map[int]map[string]bool, len(members))
+	for i, m := range members {
+		entries[i] = LeaderboardEntry{UserID: m.UserID, DisplayName: m.DisplayName()}
+		byUser[m.UserID] = &entries[i]
+		days[m.UserID] = map[string]bool{}
+	}
+
+	for _, a := range activities {
+		entry, ok := byUser[a.CreatorID]
+		if !ok {
+			continue
+		}
+		entry.ActivityCount++
+		entry.Points += ActivityPoints(a.Rating, a.Difficulty)
+		days[a.CreatorID][a.Date.Format("2006-01-02")] = true
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		entry.ActiveDays = len(days[entry.UserID])
+		switch mode {
+		case ScoringActiveDays:
+			entry.Score = entry.ActiveDays
+		case ScoringDifficulty:
+			entry.Score = entry.Points
+		default:
+			entry.Score = entry.ActivityCount
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		ni, nj := strings.ToLower(entries[i].DisplayName), strings.ToLower(entries[j].DisplayName)
+		if ni != nj {
+			return ni < nj
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries
+}
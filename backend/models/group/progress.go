@@ -0,0 +1,259 @@
+package group
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models/activity"
+)
+
+// Kinds of goals a group can work towards over its date window.
+const (
+	// GoalTotalActivities is a number of activities for the whole group together
+	GoalTotalActivities = "total_activities"
+	// GoalActivitiesPerMember is a number of activities every member should log
+	GoalActivitiesPerMember = "activities_per_member"
+	// GoalActiveDaysPerWeek is a number of days per week every member should be active on
+	GoalActiveDaysPerWeek = "active_days_per_week"
+)
+
+type GoalProgress struct {
+	Goal   string `json:"goal"`
+	Target int    `json:"target"`
+	// Done and Required count the goal's units: activities, or active days for GoalActiveDaysPerWeek,
+	// counted week by week. For per-member goals they are summed over members, counting each member up
+	// to their own target.
+	Done     int     `json:"done"`
+	Required int     `json:"required"`
+	Percent  float64 `json:"percent"`
+	// ProjectedFinish is when the goal is reached at the group's pace so far; nil once it is reached or
+	// while there is no pace yet
+	ProjectedFinish *time.Time `json:"projected_finish"`
+	OnTrack         bool       `json:"on_track"`
+}
+
+type MemberProgress struct {
+	UserID        int     `json:"user_id"`
+	DisplayName   string  `json:"display_name"`
+	ActivityCount int     `json:"activity_count"`
+	ActiveDays    int     `json:"active_days"`
+	Percent       float64 `json:"percent"`
+	// Goals maps each goal to the member's completion; for GoalTotalActivities that is against an even share
+	Goals map[string]float64 `json:"goals"`
+}
+
+type Progress struct {
+	TotalDays   int              `json:"total_days"`
+	ElapsedDays int              `json:"elapsed_days"`
+	TimePercent float64          `json:"time_percent"`
+	Percent     float64          `json:"percent"`
+	Goals       []GoalProgress   `json:"goals"`
+	Members     []MemberProgress `json:"members"`
+}
+
+// ValidGoalTarget reports whether target is a usable target for goal: positive, and at most 7 days a week.
+func ValidGoalTarget(goal string, target int) bool {
+	if goal == GoalActiveDaysPerWeek {
+		return target >= 1 && target <= 7
+	}
+	return target >= 1
+}
+
+type goalTarget struct {
+	goal   string
+	target *int
+}
+
+func (g Group) goalTargets() []goalTarget {
+	return []goalTarget{
+		{GoalTotalActivities, g.GoalTotalActivities},
+		{GoalActivitiesPerMember, g.GoalActivitiesPerMember},
+		{GoalActiveDaysPerWeek, g.GoalActiveDaysPerWeek},
+	}
+}
+
+// InvalidGoal returns the first goal that is set to an unusable target, if any.
+func (g Group) InvalidGoal() (string, bool) {
+	for _, t := range g.goalTargets() {
+		if t.target != nil && !ValidGoalTarget(t.goal, *t.target) {
+			return t.goal, true
+		}
+	}
+	return "", false
+}
+
+// Goals returns the group's goals and their targets in a fixed order, leaving out unset ones.
+func (g Group) Goals() []GoalProgress {
+	goals := []GoalProgress{}
+	for _, t := range g.goalTargets() {
+		if t.target != nil && *t.target > 0 {
+			goals = append(goals, GoalProgress{Goal: t.goal, Target: *t.target})
+		}
+	}
+	return goals
+}
+
+// BuildProgress measures members' activities against the group's goals on day today. activities must
+// already be limited to the group's date window and to solves; as on the leaderboard, only current
+// members count. Every percentage is capped at 100, and a goal's projection assumes the pace from the
+// start so far. Members are ordered by completion, then display name, then user id.
+func BuildProgress(g Group, members []MemberProfile, activities []PostedActivity, today time.Time) Progress {
+	start, end := activity.Day(g.StartDate), activity.Day(g.EndDate)
+	var progress Progress
+	if !end.Before(start) {
+		progress.TotalDays = daysBetween(start, end) + 1
+		progress.ElapsedDays = clamp(daysBetween(start, today)+1, 0, progress.TotalDays)
+		progress.TimePercent = percent(progress.ElapsedDays, progress.TotalDays)
+	}
+
+	counts := map[int]int{}
+	days := map[int]map[time.Time]bool{}
+	for _, m := range members {
+		days[m.UserID] = map[time.Time]bool{}
+	}
+	for _, a := range activities {
+		if _, ok := days[a.CreatorID]; !ok {
+			continue
+		}
+		counts[a.CreatorID]++
+		days[a.CreatorID][activity.Day(a.Date)] = true
+	}
+
+	progress.Goals = g.Goals()
+	progress.Members = make([]MemberProgress, len(members))
+	for i, m := range members {
+		progress.Members[i] = MemberProgress{
+			UserID:        m.UserID,
+			DisplayName:   m.DisplayName(),
+			ActivityCount: counts[m.UserID],
+			ActiveDays:    len(days[m.UserID]),
+			Goals:         map[string]float64{},
+		}
+	}
+
+	for i := range progress.Goals {
+		goal := &progress.Goals[i]
+		for j := range progress.Members {
+			member := &progress.Members[j]
+			switch goal.Goal {
+			case GoalTotalActivities:
+				goal.Done += member.ActivityCount
+				member.Goals[goal.Goal] = percentOf(float64(member.ActivityCount), float64(goal.Target)/float64(len(members)))
+			case GoalActivitiesPerMember:
+				goal.Done += min(member.ActivityCount, goal.Target)
+				member.Goals[goal.Goal] = percent(member.ActivityCount, goal.Target)
+			case GoalActiveDaysPerWeek:
+				done, required := weeklyActiveDays(days[member.UserID], start, progress.TotalDays, goal.Target)
+				goal.Done += done
+				member.Goals[goal.Goal] = percent(done, required)
+			}
+		}
+
+		switch goal.Goal {
+		case GoalTotalActivities:
+			goal.Required = goal.Target
+		case GoalActivitiesPerMember:
+			goal.Required = goal.Target * len(members)
+		case GoalActiveDaysPerWeek:
+			_, required := weeklyActiveDays(nil, start, progress.TotalDays, goal.Target)
+			goal.Required = required * len(members)
+		}
+		goal.Percent = percent(goal.Done, goal.Required)
+		projectFinish(goal, today, end, progress.ElapsedDays)
+	}
+
+	goalPercents := make([]float64, len(progress.Goals))
+	for i, goal := range progress.Goals {
+		goalPercents[i] = goal.Percent
+	}
+	progress.Percent = average(goalPercents)
+	for i := range progress.Members {
+		member := &progress.Members[i]
+		memberPercents := make([]float64, len(progress.Goals))
+		for j, goal := range progress.Goals {
+			memberPercents[j] = member.Goals[goal.Goal]
+		}
+		member.Percent = average(memberPercents)
+	}
+
+	sort.Slice(progress.Members, func(i, j int) bool {
+		if progress.Members[i].Percent != progress.Members[j].Percent {
+			return progress.Members[i].Percent > progress.Members[j].Percent
+		}
+		ni, nj := strings.ToLower(progress.Members[i].DisplayName), strings.ToLower(progress.Members[j].DisplayName)
+		if ni != nj {
+			return ni < nj
+		}
+		return progress.Members[i].UserID < progress.Members[j].UserID
+	})
+	return progress
+}
+
+// projectFinish extrapolates the goal's pace over the elapsed days to the day it will be reached.
+func projectFinish(goal *GoalProgress, today, end time.Time, elapsedDays int) {
+	if goal.Required > 0 && goal.Done >= goal.Required {
+		goal.OnTrack = true
+		return
+	}
+	if goal.Done == 0 || elapsedDays == 0 {
+		return
+	}
+	perDay := float64(goal.Done) / float64(elapsedDays)
+	daysLeft := int(math.Ceil(float64(goal.Required-goal.Done) / perDay))
+	finish := today.AddDate(0, 0, daysLeft)
+	goal.ProjectedFinish = &finish
+	goal.OnTrack = !finish.After(end)
+}
+
+// weeklyActiveDays measures active days against a days-per-week goal. The window is split into weeks
+// from start, and each week counts up to perWeek active days, so days in a busy week do not make up for
+// an idle one. A shorter last week requires at most as many days as it has. It returns the days that
+// count and the days required.
+func weeklyActiveDays(days map[time.Time]bool, start time.Time, totalDays, perWeek int) (done, required int) {
+	weeks := (totalDays + 6) / 7
+	active := make([]int, weeks)
+	for day := range days {
+		if week := daysBetween(start, day) / 7; week >= 0 && week < weeks {
+			active[week]++
+		}
+	}
+	for week := 0; week < weeks; week++ {
+		needed := min(perWeek, totalDays-7*week)
+		done += min(active[week], needed)
+		required += needed
+	}
+	return done, required
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+func clamp(n, low, high int) int {
+	return max(low, min(n, high))
+}
+
+func percent(done, required int) float64 {
+	return percentOf(float64(done), float64(required))
+}
+
+// percentOf is done as a percentage of required, capped at 100 and rounded to one decimal.
+func percentOf(done, required float64) float64 {
+	if required <= 0 {
+		return 0
+	}
+	return math.Round(math.Min(done/required, 1)*1000) / 10
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return math.Round(sum/float64(len(values))*10) / 10
+}
@@ -0,0 +1,81 @@
+package group
+
+// Roles a member can hold in a group, from most to least privileged. Every group has exactly one owner,
+// its creator, who cannot be removed or demoted.
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+)
+
+// Permission is an action in a group that not every member may take.
+type Permission string
+
+const (
+	PermissionUpdateGroup    Permission = "update_group"
+	PermissionDeleteGroup    Permission = "delete_group"
+	PermissionManageRoles    Permission = "manage_roles"
+	PermissionManageInvites  Permission = "manage_invites"
+	PermissionManageMembers  Permission = "manage_members"
+	PermissionDeleteComments Permission = "delete_comments"
+	// PermissionRemoveActivities allows taking other members' activities out of the group
+	PermissionRemoveActivities Permission = "remove_activities"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleOwner: {
+		PermissionUpdateGroup,
+		PermissionDeleteGroup,
+		PermissionManageRoles,
+		PermissionManageInvites,
+		PermissionManageMembers,
+		PermissionDeleteComments,
+		PermissionRemoveActivities,
+	},
+	RoleAdmin: {
+		PermissionManageRoles,
+		PermissionManageInvites,
+		PermissionManageMembers,
+		PermissionDeleteComments,
+		PermissionRemoveActivities,
+	},
+	RoleModerator: {
+		PermissionDeleteComments,
+		PermissionRemoveActivities,
+	},
+}
+
+var roleRanks = map[string]int{
+	RoleOwner:     3,
+	RoleAdmin:     2,
+	RoleModerator: 1,
+	RoleMember:    0,
+}
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleCan reports whether role grants permission.
+func RoleCan(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Outranks reports whether role is strictly more privileged than other. Members can only manage
+// members they outrank, so admins cannot remove each other or the owner.
+func Outranks(role, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
+
+// Can reports whether m's role grants permission.
+func (m GroupMember) Can(permission Permission) bool {
+	return RoleCan(m.Role, permission)
+}
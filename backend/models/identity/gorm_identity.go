@@ -0,0 +1,63 @@
+package identity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormIdentityModel struct {
+	db *gorm.DB
+}
+
+func NewGormIdentityModel(db *gorm.DB) *GormIdentityModel {
+	return &GormIdentityModel{db: db}
+}
+
+func (m *GormIdentityModel) GetIdentity(provider, subject string) (Identity, bool) {
+	var i Identity
+	if err := m.db.First(&i, "provider = ? AND subject = ?", provider, subject).Error; err != nil {
+		return Identity{}, false
+	}
+	return i, true
+}
+
+func (m *GormIdentityModel) CreateIdentity(i Identity) (Identity, bool) {
+	if err := m.db.Create(&i).Error; err != nil {
+		return Identity{}, false
+	}
+	return i, true
+}
+
+func (m *GormIdentityModel) GetIdentitiesByUserID(userID int) []Identity {
+	var list []Identity
+	m.db.Where("user_id = ?", userID).Order("id").Find(&list)
+	return list
+}
+
+func (m *GormIdentityModel) CreateLoginState(state LoginState) bool {
+	// Abandoned logins are cleaned up here rather than by a background job
+	m.db.Where("expires_at < ?", time.Now()).Delete(&LoginState{})
+	return m.db.Create(&state).Error == nil
+}
+
+func (m *GormIdentityModel) ConsumeLoginState(state string) (LoginState, bool) {
+	var deleted []LoginState
+	result := m.db.Clauses(clause.Returning{}).
+		Where("state = ?", state).
+		Delete(&deleted)
+	if result.Error != nil || len(deleted) == 0 {
+		return LoginState{}, false
+	}
+	if !time.Now().Before(deleted[0].ExpiresAt) {
+		return LoginState{}, false
+	}
+	return deleted[0], true
+}
+
+func (m *GormIdentityModel) Clear() {
+	m.db.Exec("DELETE FROM login_states")
+	m.db.Exec("DELETE FROM identities")
+	m.db.Exec("ALTER SEQUENCE identities_id_seq RESTART WITH 1")
+}
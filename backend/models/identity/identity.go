@@ -0,0 +1,23 @@
+package identity
+
+import "time"
+
+// Identity links an account at an external login provider to a user.
+type Identity struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int       `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"type:text;not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"type:text;not null;uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string    `gorm:"type:text" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginState is a pending authorization-code login. State is sent to the provider and must come back
+// in the callback; Verifier is the PKCE code verifier, which never leaves the server.
+type LoginState struct {
+	State     string    `gorm:"primaryKey;type:text" json:"state"`
+	Provider  string    `gorm:"type:text;not null" json:"provider"`
+	Verifier  string    `gorm:"type:text;not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+}
@@ -0,0 +1,13 @@
+package identity
+
+type IdentityModel interface {
+	GetIdentity(provider, subject string) (Identity, bool)
+	CreateIdentity(identity Identity) (Identity, bool)
+	GetIdentitiesByUserID(userID int) []Identity
+	CreateLoginState(state LoginState) bool
+	// ConsumeLoginState deletes the unexpired state and returns it, so each state can be used once.
+	ConsumeLoginState(state string) (LoginState, bool)
+}
+
+// DefaultIdentityModel must be set in main.go after DB initialization
+var DefaultIdentityModel IdentityModel
@@ -0,0 +1,117 @@
+package linkedaccount
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormLinkedAccountModel struct {
+	db *gorm.DB
+}
+
+func NewGormLinkedAccountModel(db *gorm.DB) *GormLinkedAccountModel {
+	return &GormLinkedAccountModel{db: db}
+}
+
+func (m *GormLinkedAccountModel) GetAccount(userID int, judge string) (LinkedAccount, bool) {
+	var a LinkedAccount
+	if err := m.db.First(&a, "user_id = ? AND judge = ?", userID, judge).Error; err != nil {
+		return LinkedAccount{}, false
+	}
+	return a, true
+}
+
+func (m *GormLinkedAccountModel) GetAccountByHandle(judge, handle string) (LinkedAccount, bool) {
+	var a LinkedAccount
+	if err := m.db.First(&a, "judge = ? AND LOWER(handle) = LOWER(?)", judge, handle).Error; err != nil {
+		return LinkedAccount{}, false
+	}
+	return a, true
+}
+
+func (m *GormLinkedAccountModel) GetAccountsByUserID(userID int) []LinkedAccount {
+	accounts := []LinkedAccount{}
+	m.db.Where("user_id = ?", userID).Order("judge").Find(&accounts)
+	return accounts
+}
+
+func (m *GormLinkedAccountModel) GetDueAccounts(now time.Time) []LinkedAccount {
+	accounts := []LinkedAccount{}
+	m.db.Where("next_sync_at <= ?", now).Order("next_sync_at, id").Find(&accounts)
+	return accounts
+}
+
+func (m *GormLinkedAccountModel) SaveAccount(account LinkedAccount) (LinkedAccount, bool) {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var existing LinkedAccount
+		err := tx.First(&existing, "user_id = ? AND judge = ?", account.UserID, account.Judge).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			account.ID = 0
+			return tx.Create(&account).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"handle": account.Handle, "group_ids": account.GroupIDs}
+		if !strings.EqualFold(existing.Handle, account.Handle) {
+			// Another handle is imported from its own start
+			updates["cursor"] = account.Cursor
+			updates["last_imported_at"] = account.LastImportedAt
+			updates["next_sync_at"] = account.NextSyncAt
+			updates["failures"] = 0
+			updates["last_error"] = nil
+			updates["last_error_at"] = nil
+		}
+		if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&account, "id = ?", existing.ID).Error
+	})
+	if err != nil {
+		return LinkedAccount{}, false
+	}
+	return account, true
+}
+
+func (m *GormLinkedAccountModel) SetCursor(id int, handle, cursor string, importedAt time.Time) bool {
+	result := m.linked(id, handle).Updates(map[string]interface{}{"cursor": cursor, "last_imported_at": importedAt, "failures": 0})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormLinkedAccountModel) RecordFailure(id int, handle, message string, failedAt time.Time, nextSyncAt *time.Time) bool {
+	result := m.linked(id, handle).Updates(map[string]interface{}{
+		"failures":      gorm.Expr("failures + 1"),
+		"last_error":    message,
+		"last_error_at": failedAt,
+		"next_sync_at":  nextSyncAt,
+	})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormLinkedAccountModel) SetNextSync(id int, handle string, nextSyncAt *time.Time) bool {
+	result := m.linked(id, handle).Update("next_sync_at", nextSyncAt)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+// linked selects the account while it is linked to handle, matched case-insensitively like relinking does.
+func (m *GormLinkedAccountModel) linked(id int, handle string) *gorm.DB {
+	return m.db.Model(&LinkedAccount{}).Where("id = ? AND LOWER(handle) = LOWER(?)", id, handle)
+}
+
+func (m *GormLinkedAccountModel) ScheduleUnscheduled(at time.Time) bool {
+	return m.db.Model(&LinkedAccount{}).Where("next_sync_at IS NULL").Update("next_sync_at", at).Error == nil
+}
+
+func (m *GormLinkedAccountModel) DeleteAccount(userID int, judge string) bool {
+	result := m.db.Delete(&LinkedAccount{}, "user_id = ? AND judge = ?", userID, judge)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormLinkedAccountModel) Clear() {
+	m.db.Exec("DELETE FROM linked_accounts")
+	m.db.Exec("ALTER SEQUENCE linked_accounts_id_seq RESTART WITH 1")
+}
@@ -0,0 +1,90 @@
+package linkedaccount
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LinkedAccount is a user's handle on an online judge, whose accepted submissions are imported as activities.
+type LinkedAccount struct {
+	ID     int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID int    `gorm:"not null;uniqueIndex:idx_linked_accounts_user_judge" json:"user_id"`
+	Judge  string `gorm:"type:text;not null;uniqueIndex:idx_linked_accounts_user_judge" json:"judge"`
+	Handle string `gorm:"type:text;not null" json:"handle"`
+	// GroupIDs are the groups imported activities are posted to
+	GroupIDs IDs `gorm:"type:text" json:"group_ids"`
+	// Cursor marks how far the judge's submissions have been imported; what it holds depends on the judge
+	Cursor         string     `gorm:"type:text;not null;default:''" json:"-"`
+	LastImportedAt *time.Time `json:"last_imported_at,omitempty"`
+	// NextSyncAt is when the account is due to be imported again; nil when it is only synced on request.
+	// Failures counts the imports that failed in a row, which pushes NextSyncAt back further each time
+	NextSyncAt  *time.Time `gorm:"index" json:"-"`
+	Failures    int        `gorm:"not null;default:0" json:"-"`
+	LastError   *string    `gorm:"type:text" json:"-"`
+	LastErrorAt *time.Time `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// SyncStatus is how importing a linked account has gone lately.
+type SyncStatus struct {
+	Judge         string     `json:"judge"`
+	Handle        string     `json:"handle"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	LastError     *string    `json:"last_error"`
+	LastErrorAt   *time.Time `json:"last_error_at"`
+	Failures      int        `json:"failures"`
+	NextSyncAt    *time.Time `json:"next_sync_at"`
+	Syncing       bool       `json:"syncing"`
+}
+
+func (a LinkedAccount) SyncStatus(syncing bool) SyncStatus {
+	return SyncStatus{
+		Judge:         a.Judge,
+		Handle:        a.Handle,
+		LastSuccessAt: a.LastImportedAt,
+		LastError:     a.LastError,
+		LastErrorAt:   a.LastErrorAt,
+		Failures:      a.Failures,
+		NextSyncAt:    a.NextSyncAt,
+		Syncing:       syncing,
+	}
+}
+
+// LastAttemptAt is when the account was last imported, successfully or not.
+func (a LinkedAccount) LastAttemptAt() time.Time {
+	var last time.Time
+	for _, t := range []*time.Time{a.LastImportedAt, a.LastErrorAt} {
+		if t != nil && t.After(last) {
+			last = *t
+		}
+	}
+	return last
+}
+
+// IDs are stored as a JSON array in a text column, like activity tags.
+type IDs []int
+
+func (ids IDs) Value() (driver.Value, error) {
+	if ids == nil {
+		ids = IDs{}
+	}
+	b, err := json.Marshal([]int(ids))
+	return string(b), err
+}
+
+func (ids *IDs) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*ids = IDs{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]int)(ids))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]int)(ids))
+	default:
+		return fmt.Errorf("cannot scan %T into IDs", value)
+	}
+}
@@ -0,0 +1,30 @@
+package linkedaccount
+
+import "time"
+
+type LinkedAccountModel interface {
+	GetAccount(userID int, judge string) (LinkedAccount, bool)
+	// GetAccountByHandle matches handles case-insensitively, as the judges do.
+	GetAccountByHandle(judge, handle string) (LinkedAccount, bool)
+	GetAccountsByUserID(userID int) []LinkedAccount
+	// GetDueAccounts returns the accounts whose NextSyncAt has come by now, the longest overdue first.
+	GetDueAccounts(now time.Time) []LinkedAccount
+	// SaveAccount links the account, updating the user's earlier account on the same judge in place. Its
+	// import position, schedule and failures are only replaced when the handle changes.
+	SaveAccount(account LinkedAccount) (LinkedAccount, bool)
+	// SetCursor, RecordFailure and SetNextSync only update the account while it is still linked to
+	// handle, so an import that outlives a relink to another handle leaves the new handle alone. They
+	// report whether the account was updated.
+	//
+	// SetCursor records an import that got up to cursor, which also ends a run of failures.
+	SetCursor(id int, handle, cursor string, importedAt time.Time) bool
+	// RecordFailure counts a failed import and reschedules the account.
+	RecordFailure(id int, handle, message string, failedAt time.Time, nextSyncAt *time.Time) bool
+	SetNextSync(id int, handle string, nextSyncAt *time.Time) bool
+	// ScheduleUnscheduled makes the accounts without a NextSyncAt due at the given time.
+	ScheduleUnscheduled(at time.Time) bool
+	DeleteAccount(userID int, judge string) bool
+}
+
+// DefaultLinkedAccountModel must be set in main.go after DB initialization
+var DefaultLinkedAccountModel LinkedAccountModel
@@ -0,0 +1,50 @@
+package pat
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormTokenModel struct {
+	db *gorm.DB
+}
+
+func NewGormTokenModel(db *gorm.DB) *GormTokenModel {
+	return &GormTokenModel{db: db}
+}
+
+func (m *GormTokenModel) CreateToken(t PersonalAccessToken) (PersonalAccessToken, bool) {
+	if err := m.db.Create(&t).Error; err != nil {
+		return PersonalAccessToken{}, false
+	}
+	return t, true
+}
+
+func (m *GormTokenModel) GetTokenByHash(tokenHash string) (PersonalAccessToken, bool) {
+	var t PersonalAccessToken
+	if err := m.db.First(&t, "token_hash = ?", tokenHash).Error; err != nil {
+		return PersonalAccessToken{}, false
+	}
+	return t, true
+}
+
+func (m *GormTokenModel) GetTokensByUserID(userID int) []PersonalAccessToken {
+	var list []PersonalAccessToken
+	m.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&list)
+	return list
+}
+
+func (m *GormTokenModel) DeleteToken(id, userID int) bool {
+	result := m.db.Where("id = ? AND user_id = ?", id, userID).Delete(&PersonalAccessToken{})
+	return result.RowsAffected > 0
+}
+
+func (m *GormTokenModel) MarkTokenUsed(id int) {
+	m.db.Model(&PersonalAccessToken{}).Where("id = ?", id).Update("last_used_at", time.Now())
+}
+
+func (m *GormTokenModel) Clear() {
+	m.db.Exec("DELETE FROM personal_access_tokens")
+	m.db.Exec("ALTER SEQUENCE personal_access_tokens_id_seq RESTART WITH 1")
+}
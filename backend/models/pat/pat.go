@@ -0,0 +1,51 @@
+package pat
+
+import "time"
+
+// Prefix starts every personal access token so the auth layer can tell them apart from session JWTs.
+const Prefix = "cdk_"
+
+const (
+	ScopeActivitiesWrite = "activities:write"
+	ScopeGroupsRead      = "groups:read"
+	ScopeGroupsWrite     = "groups:write"
+	ScopeCommentsWrite   = "comments:write"
+)
+
+// Scopes lists every scope a token can be granted.
+var Scopes = []string{ScopeActivitiesWrite, ScopeGroupsRead, ScopeGroupsWrite, ScopeCommentsWrite}
+
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken is a token a user created for scripts and plugins. The secret is shown once on
+// creation; only its hash is stored.
+type PersonalAccessToken struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     int        `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"type:text;not null" json:"name"`
+	Scopes     []string   `gorm:"type:text;serializer:json;not null" json:"scopes"`
+	TokenHash  string     `gorm:"type:text;uniqueIndex;not null" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+func (t PersonalAccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+func (t PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
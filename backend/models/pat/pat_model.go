@@ -0,0 +1,13 @@
+package pat
+
+type TokenModel interface {
+	CreateToken(token PersonalAccessToken) (PersonalAccessToken, bool)
+	GetTokenByHash(tokenHash string) (PersonalAccessToken, bool)
+	GetTokensByUserID(userID int) []PersonalAccessToken
+	// DeleteToken deletes the token only if it belongs to userID.
+	DeleteToken(id, userID int) bool
+	MarkTokenUsed(id int)
+}
+
+// DefaultTokenModel must be set in main.go after DB initialization
+var DefaultTokenModel TokenModel
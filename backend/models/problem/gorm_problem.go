@@ -0,0 +1,236 @@
+package problem
+
+import (
+	"sort"
+
+	"backend/models/activity"
+
+	"gorm.io/gorm"
+)
+
+type GormProblemModel struct {
+	db *gorm.DB
+}
+
+func NewGormProblemModel(db *gorm.DB) *GormProblemModel {
+	return &GormProblemModel{db: db}
+}
+
+func (m *GormProblemModel) GetProblemByID(id int) (Problem, bool) {
+	var p Problem
+	if err := m.db.First(&p, "id = ?", id).Error; err != nil {
+		return Problem{}, false
+	}
+	return p, true
+}
+
+func (m *GormProblemModel) GetProblemByKey(key string) (Problem, bool) {
+	var p Problem
+	if err := m.db.First(&p, "key = ?", key).Error; err != nil {
+		return Problem{}, false
+	}
+	return p, true
+}
+
+func (m *GormProblemModel) EnsureProblem(p Problem) (Problem, bool) {
+	existing, exists := m.GetProblemByKey(p.Key)
+	if !exists {
+		p.ID = 0
+		if err := m.db.Create(&p).Error; err == nil {
+			return p, true
+		}
+		// Another request may have added it in the meantime
+		if existing, exists = m.GetProblemByKey(p.Key); !exists {
+			return Problem{}, false
+		}
+	}
+
+	if updates := existing.fillIn(p); len(updates) > 0 {
+		if err := m.db.Model(&Problem{}).Where("id = ?", existing.ID).Updates(updates).Error; err != nil {
+			return Problem{}, false
+		}
+	}
+	return existing, true
+}
+
+// ImportProblems counts problems given more than once as skipped after the first. When a batch fails, the
+// result counts the batches saved before it.
+func (m *GormProblemModel) ImportProblems(problems []Problem, batchSize int) (ImportResult, error) {
+	var result ImportResult
+	seen := map[string]bool{}
+	for start := 0; start < len(problems); start += batchSize {
+		batch := problems[start:min(start+batchSize, len(problems))]
+		var counts ImportResult
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			keys := make([]string, len(batch))
+			for i, p := range batch {
+				keys[i] = p.Key
+			}
+			var stored []Problem
+			if err := tx.Where("key IN ?", keys).Find(&stored).Error; err != nil {
+				return err
+			}
+			byKey := make(map[string]*Problem, len(stored))
+			for i := range stored {
+				byKey[stored[i].Key] = &stored[i]
+			}
+
+			created := []Problem{}
+			for _, p := range batch {
+				if seen[p.Key] {
+					counts.Skipped++
+					continue
+				}
+				seen[p.Key] = true
+				existing, ok := byKey[p.Key]
+				if !ok {
+					p.ID = 0
+					created = append(created, p)
+					continue
+				}
+				updates := existing.replaceWith(p)
+				if len(updates) == 0 {
+					counts.Skipped++
+					continue
+				}
+				if err := tx.Model(&Problem{}).Where("id = ?", existing.ID).Updates(updates).Error; err != nil {
+					return err
+				}
+				counts.Updated++
+			}
+			if len(created) > 0 {
+				if err := tx.Create(&created).Error; err != nil {
+					return err
+				}
+			}
+			counts.Created = len(created)
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Created += counts.Created
+		result.Updated += counts.Updated
+		result.Skipped += counts.Skipped
+	}
+	return result, nil
+}
+
+func (m *GormProblemModel) GetSolvers(problemID, userID int) []Solver {
+	var solves []Solver
+	m.db.Table("activities").
+		Select("activities.creator_id AS user_id, users.name AS name, activities.id AS activity_id, activities.date AS solved_at").
+		Joins("JOIN users ON users.id = activities.creator_id AND users.deleted_at IS NULL").
+		Where("activities.catalog_problem_id = ? AND activities.deleted_at IS NULL AND "+activity.SolvedCondition, problemID).
+		Where("activities.creator_id = ? OR activities.creator_id IN (?)", userID, m.groupmates(userID)).
+		Order("activities.date, activities.id").
+		Scan(&solves)
+	return firstSolves(solves)
+}
+
+// GetGroupProblems names solvers by their nickname in the group and counts solves between the group's
+// start and end dates, like the leaderboard does.
+func (m *GormProblemModel) GetGroupProblems(groupID int) []GroupProblem {
+	var solves []struct {
+		ProblemID int
+		Solver
+	}
+	m.db.Table("activities").
+		Select("activities.catalog_problem_id AS problem_id, activities.creator_id AS user_id, "+
+			"COALESCE(NULLIF(group_members.nickname, ''), users.name) AS name, activities.id AS activity_id, activities.date AS solved_at").
+		Joins("JOIN group_members ON group_members.user_id = activities.creator_id AND group_members.group_id = ?", groupID).
+		Joins("JOIN groups ON groups.id = group_members.group_id").
+		Joins("JOIN users ON users.id = activities.creator_id AND users.deleted_at IS NULL").
+		Where("activities.catalog_problem_id IS NOT NULL AND activities.deleted_at IS NULL AND " + activity.SolvedCondition).
+		Where("activities.date BETWEEN groups.start_date AND groups.end_date").
+		Order("activities.date, activities.id").
+		Scan(&solves)
+
+	byProblem := map[int][]Solver{}
+	for _, s := range solves {
+		byProblem[s.ProblemID] = append(byProblem[s.ProblemID], s.Solver)
+	}
+	ids := make([]int, 0, len(byProblem))
+	for id := range byProblem {
+		ids = append(ids, id)
+	}
+	var problems []Problem
+	if len(ids) > 0 {
+		m.db.Where("id IN ?", ids).Find(&problems)
+	}
+
+	result := make([]GroupProblem, 0, len(problems))
+	for _, p := range problems {
+		solvers := firstSolves(byProblem[p.ID])
+		result = append(result, GroupProblem{Problem: p, SolveCount: len(solvers), FirstSolver: solvers[0]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SolveCount != result[j].SolveCount {
+			return result[i].SolveCount > result[j].SolveCount
+		}
+		return result[i].Problem.Key < result[j].Problem.Key
+	})
+	return result
+}
+
+func (m *GormProblemModel) GetRatedProblems(minRating, maxRating int, tags []string) []Problem {
+	query := m.db.Where("rating BETWEEN ? AND ?", minRating, maxRating)
+	if len(tags) > 0 {
+		// Tags are stored as a JSON array, and valid tags cannot contain quotes or wildcards
+		matches := m.db.Where("tags LIKE ?", `%"`+tags[0]+`"%`)
+		for _, tag := range tags[1:] {
+			matches = matches.Or("tags LIKE ?", `%"`+tag+`"%`)
+		}
+		query = query.Where(matches)
+	}
+
+	problems := []Problem{}
+	query.Order("id").Find(&problems)
+	return problems
+}
+
+func (m *GormProblemModel) CountGroupmateSolves(userID int) map[int]int {
+	var counts []struct {
+		ProblemID int
+		Solves    int
+	}
+	m.db.Table("activities").
+		Select("activities.catalog_problem_id AS problem_id, COUNT(DISTINCT activities.creator_id) AS solves").
+		Where("activities.catalog_problem_id IS NOT NULL AND activities.deleted_at IS NULL AND "+activity.SolvedCondition).
+		Where("activities.creator_id <> ? AND activities.creator_id IN (?)", userID, m.groupmates(userID)).
+		Group("activities.catalog_problem_id").
+		Scan(&counts)
+
+	result := make(map[int]int, len(counts))
+	for _, c := range counts {
+		result[c.ProblemID] = c.Solves
+	}
+	return result
+}
+
+// groupmates selects the user IDs of the members of userID's groups, userID included.
+func (m *GormProblemModel) groupmates(userID int) *gorm.DB {
+	return m.db.Table("group_members AS mine").
+		Select("mates.user_id").
+		Joins("JOIN group_members AS mates ON mates.group_id = mine.group_id").
+		Joins("JOIN groups ON groups.id = mine.group_id AND groups.deleted_at IS NULL").
+		Where("mine.user_id = ?", userID)
+}
+
+func (m *GormProblemModel) LinkActivities() {
+	var activities []activity.Activity
+	if err := m.db.Where("problem_key IS NOT NULL AND catalog_problem_id IS NULL").Find(&activities).Error; err != nil {
+		return
+	}
+	for _, a := range activities {
+		Link(m, &a)
+		if a.CatalogProblemID != nil {
+			m.db.Model(&activity.Activity{}).Where("id = ?", a.ID).Update("catalog_problem_id", *a.CatalogProblemID)
+		}
+	}
+}
+
+func (m *GormProblemModel) Clear() {
+	m.db.Exec("DELETE FROM problems")
+	m.db.Exec("ALTER SEQUENCE problems_id_seq RESTART WITH 1")
+}
@@ -0,0 +1,132 @@
+package problem
+
+import (
+	"slices"
+	"time"
+
+	"backend/models/activity"
+)
+
+// Problem is a problem on an online judge, shared by every activity logged on it. Key is the activity
+// problem key it is found by, e.g. "codeforces:1352A", and ExternalID the judge's own ID for it.
+type Problem struct {
+	ID         int           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Judge      string        `gorm:"type:text;not null;index" json:"judge"`
+	ExternalID string        `gorm:"type:text;not null" json:"external_id"`
+	Key        string        `gorm:"type:text;not null;uniqueIndex" json:"key"`
+	Name       string        `gorm:"type:text;not null;default:''" json:"name"`
+	URL        string        `gorm:"type:text;not null;default:''" json:"url"`
+	Rating     *int          `json:"rating,omitempty"`
+	Difficulty *string       `gorm:"type:text" json:"difficulty,omitempty"`
+	Tags       activity.Tags `gorm:"type:text" json:"tags,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// Solver is a user who solved a problem, with the activity they first solved it in.
+type Solver struct {
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	ActivityID int       `json:"activity_id"`
+	SolvedAt   time.Time `json:"solved_at"`
+}
+
+// GroupProblem is a problem solved by members of a group.
+type GroupProblem struct {
+	Problem     Problem `json:"problem"`
+	SolveCount  int     `json:"solve_count"`
+	FirstSolver Solver  `json:"first_solver"`
+}
+
+// ImportResult counts what an import did with the problems it was given.
+type ImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// FromActivity is the catalog entry for the activity's problem, if the problem is identified. Users
+// describe problems in their own words, so only imported activities, whose metadata comes from the
+// judge, give the problem its name, rating and tags.
+func FromActivity(a activity.Activity) (Problem, bool) {
+	if a.ProblemKey == nil || a.Judge == nil || a.ProblemID == nil {
+		return Problem{}, false
+	}
+	p := Problem{Judge: *a.Judge, ExternalID: *a.ProblemID, Key: *a.ProblemKey}
+	if a.ProblemURL != nil {
+		p.URL = *a.ProblemURL
+	}
+	if a.SubmissionID != nil {
+		p.Name, p.Rating, p.Difficulty, p.Tags = a.Title, a.Rating, a.Difficulty, a.Tags
+	}
+	return p, true
+}
+
+// Link points a at its problem in the catalog, adding the problem when it is new. Activities whose
+// problem is not identified are not linked.
+func Link(model ProblemModel, a *activity.Activity) {
+	a.CatalogProblemID = nil
+	p, ok := FromActivity(*a)
+	if !ok {
+		return
+	}
+	if p, ok = model.EnsureProblem(p); ok {
+		a.CatalogProblemID = &p.ID
+	}
+}
+
+// fillIn copies the fields p is missing from other and returns the columns it changed.
+func (p *Problem) fillIn(other Problem) map[string]interface{} {
+	updates := map[string]interface{}{}
+	if p.Name == "" && other.Name != "" {
+		p.Name, updates["name"] = other.Name, other.Name
+	}
+	if p.URL == "" && other.URL != "" {
+		p.URL, updates["url"] = other.URL, other.URL
+	}
+	if p.Rating == nil && other.Rating != nil {
+		p.Rating, updates["rating"] = other.Rating, other.Rating
+	}
+	if p.Difficulty == nil && other.Difficulty != nil {
+		p.Difficulty, updates["difficulty"] = other.Difficulty, other.Difficulty
+	}
+	if len(p.Tags) == 0 && len(other.Tags) > 0 {
+		p.Tags, updates["tags"] = other.Tags, other.Tags
+	}
+	return updates
+}
+
+// replaceWith copies the fields other has into p, where they differ, and returns the columns it changed.
+// Fields other leaves empty are kept.
+func (p *Problem) replaceWith(other Problem) map[string]interface{} {
+	updates := map[string]interface{}{}
+	if other.Name != "" && p.Name != other.Name {
+		p.Name, updates["name"] = other.Name, other.Name
+	}
+	if other.URL != "" && p.URL != other.URL {
+		p.URL, updates["url"] = other.URL, other.URL
+	}
+	if other.Rating != nil && (p.Rating == nil || *p.Rating != *other.Rating) {
+		p.Rating, updates["rating"] = other.Rating, other.Rating
+	}
+	if other.Difficulty != nil && (p.Difficulty == nil || *p.Difficulty != *other.Difficulty) {
+		p.Difficulty, updates["difficulty"] = other.Difficulty, other.Difficulty
+	}
+	if len(other.Tags) > 0 && !slices.Equal(p.Tags, other.Tags) {
+		p.Tags, updates["tags"] = other.Tags, other.Tags
+	}
+	return updates
+}
+
+// firstSolves keeps the first solve of every user from solves ordered oldest first.
+func firstSolves(solves []Solver) []Solver {
+	result := []Solver{}
+	seen := map[int]bool{}
+	for _, s := range solves {
+		if !seen[s.UserID] {
+			seen[s.UserID] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package problem
+
+type ProblemModel interface {
+	GetProblemByID(id int) (Problem, bool)
+	GetProblemByKey(key string) (Problem, bool)
+	// EnsureProblem returns the problem with p's key, creating it from p when there is none yet. Fields
+	// the stored problem lacks are filled in from p; the ones it has are kept.
+	EnsureProblem(p Problem) (Problem, bool)
+	// ImportProblems saves problems from a judge's own data, batchSize to a transaction. Unlike with
+	// EnsureProblem, the data replaces what is stored, except for fields it leaves empty. Problems that
+	// would not change are skipped, so importing the same data again changes nothing.
+	ImportProblems(problems []Problem, batchSize int) (ImportResult, error)
+	// GetSolvers returns who solved the problem among userID and the members of userID's groups, in the
+	// order they first solved it.
+	GetSolvers(problemID, userID int) []Solver
+	// GetGroupProblems returns the problems the group's current members solved between the group's start
+	// and end dates, most solved first.
+	GetGroupProblems(groupID int) []GroupProblem
+	// GetRatedProblems returns the problems rated between minRating and maxRating inclusive; with tags,
+	// only those with at least one of them.
+	GetRatedProblems(minRating, maxRating int, tags []string) []Problem
+	// CountGroupmateSolves maps problem IDs to how many members of userID's groups, other than userID, solved them.
+	CountGroupmateSolves(userID int) map[int]int
+	// LinkActivities adds the problems of activities that are not linked to the catalog yet and links them.
+	LinkActivities()
+}
+
+// DefaultProblemModel must be set in main.go after DB initialization
+var DefaultProblemModel ProblemModel
@@ -0,0 +1,109 @@
+package problem
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"backend/models/activity"
+)
+
+const (
+	// defaultRating is the estimate for users without rated solves, the easiest Codeforces rating
+	defaultRating = 800
+	// estimateSolves is how many of the hardest rated solves the estimate averages
+	estimateSolves = 10
+	// RecommendBelow and RecommendAbove bound the ratings of recommended problems around the estimate
+	RecommendBelow = 200
+	RecommendAbove = 400
+	// targetAbove is how far above the estimate the best next problem is rated
+	targetAbove = 100
+)
+
+// How much each part of a recommendation's score weighs. The random part only breaks near ties.
+const (
+	weightRating    = 0.5
+	weightWeakTag   = 0.3
+	weightGroupmate = 0.2
+	weightRandom    = 0.05
+)
+
+type Recommendation struct {
+	Problem         Problem `json:"problem"`
+	Score           float64 `json:"score"`
+	GroupmateSolves int     `json:"groupmate_solves"`
+}
+
+// EstimateRating estimates a user's rating from their activities as the average rating of their hardest
+// solves, or defaultRating without rated solves.
+func EstimateRating(history []activity.Activity) int {
+	var ratings []int
+	for _, a := range history {
+		if a.Solved() && a.Rating != nil && *a.Rating > 0 {
+			ratings = append(ratings, *a.Rating)
+		}
+	}
+	if len(ratings) == 0 {
+		return defaultRating
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ratings)))
+	ratings = ratings[:min(len(ratings), estimateSolves)]
+	sum := 0
+	for _, r := range ratings {
+		sum += r
+	}
+	return int(math.Round(float64(sum) / float64(len(ratings))))
+}
+
+// Recommend picks up to count of the rated candidates for a user with the given activities, leaving out
+// problems they solved. Problems score higher the closer they are to a bit above the user's estimated
+// rating, the fewer problems the user solved with one of their tags, and the more groupmates solved them;
+// groupmateSolves maps problem IDs to those counts. The seed decides the small random part of every
+// score, so the same seed gives the same recommendations.
+func Recommend(history []activity.Activity, candidates []Problem, groupmateSolves map[int]int, count int, seed int64) []Recommendation {
+	solvedIDs := map[int]bool{}
+	solvedKeys := map[string]bool{}
+	tagSolves := map[string]int{}
+	for _, a := range history {
+		if !a.Solved() {
+			continue
+		}
+		if a.CatalogProblemID != nil {
+			solvedIDs[*a.CatalogProblemID] = true
+		}
+		if a.ProblemKey != nil {
+			solvedKeys[*a.ProblemKey] = true
+		}
+		for _, tag := range a.Tags {
+			tagSolves[activity.NormalizeTag(tag)]++
+		}
+	}
+	target := float64(EstimateRating(history) + targetAbove)
+
+	// Scores draw random numbers in ID order, so they do not depend on the order of candidates
+	ordered := append([]Problem(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+	random := rand.New(rand.NewSource(seed))
+
+	recommendations := []Recommendation{}
+	for _, p := range ordered {
+		if p.Rating == nil || solvedIDs[p.ID] || solvedKeys[p.Key] {
+			continue
+		}
+		closeness := math.Max(0, 1-math.Abs(float64(*p.Rating)-target)/float64(RecommendAbove-targetAbove))
+		weakness := 0.0
+		for _, tag := range p.Tags {
+			weakness = math.Max(weakness, 1/float64(1+tagSolves[tag]))
+		}
+		mates := groupmateSolves[p.ID]
+		popularity := float64(mates) / float64(mates+2)
+
+		score := weightRating*closeness + weightWeakTag*weakness + weightGroupmate*popularity + weightRandom*random.Float64()
+		recommendations = append(recommendations, Recommendation{Problem: p, Score: math.Round(score*1000) / 1000, GroupmateSolves: mates})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	return recommendations[:min(len(recommendations), count)]
+}
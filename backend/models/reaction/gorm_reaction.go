@@ -0,0 +1,64 @@
+package reaction
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type GormReactionModel struct {
+	db *gorm.DB
+}
+
+func NewGormReactionModel(db *gorm.DB) *GormReactionModel {
+	return &GormReactionModel{db: db}
+}
+
+func (m *GormReactionModel) AddReaction(r Reaction) bool {
+	return m.db.Create(&r).Error == nil
+}
+
+func (m *GormReactionModel) RemoveReaction(activityID, userID int, kind string) bool {
+	result := m.db.Delete(&Reaction{}, "activity_id = ? AND user_id = ? AND kind = ?", activityID, userID, kind)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormReactionModel) GetSummaries(activityIDs []int, viewerID int) map[int][]Summary {
+	summaries := map[int][]Summary{}
+	if len(activityIDs) == 0 {
+		return summaries
+	}
+
+	var rows []struct {
+		ActivityID int
+		Kind       string
+		Count      int
+		Reacted    int
+	}
+	m.db.Model(&Reaction{}).
+		Select("activity_id, kind, COUNT(*) AS count, MAX(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS reacted", viewerID).
+		Where("activity_id IN ?", activityIDs).
+		Group("activity_id, kind").
+		Scan(&rows)
+
+	for _, row := range rows {
+		summaries[row.ActivityID] = append(summaries[row.ActivityID], Summary{Kind: row.Kind, Count: row.Count, Reacted: row.Reacted == 1})
+	}
+	for _, list := range summaries {
+		sort.Slice(list, func(i, j int) bool { return kindOrder(list[i].Kind) < kindOrder(list[j].Kind) })
+	}
+	return summaries
+}
+
+func kindOrder(kind string) int {
+	for i, k := range Kinds {
+		if k == kind {
+			return i
+		}
+	}
+	return len(Kinds)
+}
+
+func (m *GormReactionModel) Clear() {
+	m.db.Exec("DELETE FROM reactions")
+}
@@ -0,0 +1,38 @@
+package reaction
+
+import "time"
+
+// Kinds of reactions a user can leave on an activity.
+const (
+	KindLike      = "like"
+	KindFire      = "fire"
+	KindClap      = "clap"
+	KindMindBlown = "mind_blown"
+)
+
+// Kinds lists every reaction kind, in the order summaries show them.
+var Kinds = []string{KindLike, KindFire, KindClap, KindMindBlown}
+
+func ValidKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Reaction is one user's reaction of one kind to an activity. A user can leave several kinds on the same activity.
+type Reaction struct {
+	ActivityID int       `gorm:"primaryKey;autoIncrement:false" json:"activity_id"`
+	UserID     int       `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	Kind       string    `gorm:"primaryKey;type:text" json:"kind"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Summary counts the reactions of one kind on an activity. Reacted tells whether the viewing user left one.
+type Summary struct {
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
@@ -0,0 +1,12 @@
+package reaction
+
+type ReactionModel interface {
+	// AddReaction fails if the user already left a reaction of the same kind on the activity.
+	AddReaction(r Reaction) bool
+	RemoveReaction(activityID, userID int, kind string) bool
+	// GetSummaries returns the reaction summary of each activity that has reactions, as seen by viewerID.
+	GetSummaries(activityIDs []int, viewerID int) map[int][]Summary
+}
+
+// DefaultReactionModel must be set in main.go after DB initialization
+var DefaultReactionModel ReactionModel
@@ -1,8 +1,14 @@
 package responses
 
 import (
+	"backend/models/activity"
 	"backend/models/comment"
 	"backend/models/group"
+	"backend/models/linkedaccount"
+	"backend/models/pat"
+	"backend/models/problem"
+	"backend/models/reaction"
+	"backend/models/session"
 	"backend/models/user"
 )
 
@@ -26,25 +32,50 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	User  user.User `json:"user"`
-	Token string    `json:"token" example:"dummy-jwt-token-1"`
+	User         user.User `json:"user"`
+	Token        string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string    `json:"token_type" example:"Bearer"`
+	ExpiresAt    string    `json:"expires_at" example:"2025-12-31T23:59:59Z"`
+	RefreshToken string    `json:"refresh_token" example:"2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
 }
 
-type GroupCreateRequest struct {
-	Name        string  `json:"name" example:"Study Group"`
-	EndDate     string  `json:"end_date" example:"2025-12-31"`
-	GroupImage  *string `json:"group_image,omitempty" example:"https://example.com/image.jpg"`
-	Description *string `json:"description,omitempty" example:"A group for studying algorithms"`
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
 }
 
-type GroupUpdateRequest struct {
-	EndDate     *string `json:"end_date,omitempty" example:"2025-12-31"`
-	GroupImage  *string `json:"group_image,omitempty" example:"https://example.com/image.jpg"`
-	Description *string `json:"description,omitempty" example:"Updated description"`
+type TokenResponse struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresAt    string `json:"expires_at" example:"2025-12-31T23:59:59Z"`
+	RefreshToken string `json:"refresh_token" example:"2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
+}
+
+type SessionsResponse struct {
+	Sessions []session.Session `json:"sessions"`
 }
 
-type GroupDeleteRequest struct {
-	CreatorID string `json:"creator_id" example:"user123"`
+type GroupCreateRequest struct {
+	Name                    string  `json:"name" example:"Study Group"`
+	EndDate                 string  `json:"end_date" example:"2025-12-31"`
+	GroupImage              *string `json:"group_image,omitempty" example:"https://example.com/image.jpg"`
+	Description             *string `json:"description,omitempty" example:"A group for studying algorithms"`
+	RequireVerifiedEmail    bool    `json:"require_verified_email,omitempty" example:"false"`
+	ScoringMode             string  `json:"scoring_mode,omitempty" example:"activities"`
+	GoalTotalActivities     *int    `json:"goal_total_activities,omitempty" example:"300"`
+	GoalActivitiesPerMember *int    `json:"goal_activities_per_member,omitempty" example:"50"`
+	GoalActiveDaysPerWeek   *int    `json:"goal_active_days_per_week,omitempty" example:"5"`
+}
+
+type GroupUpdateRequest struct {
+	EndDate              *string `json:"end_date,omitempty" example:"2025-12-31"`
+	GroupImage           *string `json:"group_image,omitempty" example:"https://example.com/image.jpg"`
+	Description          *string `json:"description,omitempty" example:"Updated description"`
+	RequireVerifiedEmail *bool   `json:"require_verified_email,omitempty" example:"true"`
+	ScoringMode          *string `json:"scoring_mode,omitempty" example:"active_days"`
+	// Set a goal to null to remove it
+	GoalTotalActivities     *int `json:"goal_total_activities,omitempty" example:"300"`
+	GoalActivitiesPerMember *int `json:"goal_activities_per_member,omitempty" example:"50"`
+	GoalActiveDaysPerWeek   *int `json:"goal_active_days_per_week,omitempty" example:"5"`
 }
 
 type AddUserToGroupRequest struct {
@@ -52,8 +83,7 @@ type AddUserToGroupRequest struct {
 }
 
 type RemoveUserFromGroupRequest struct {
-	UserID      string `json:"user_id" example:"user123"`
-	RequesterID string `json:"requester_id" example:"user456"`
+	UserID string `json:"user_id" example:"user123"`
 }
 
 type AddUserToGroupResponse struct {
@@ -68,54 +98,126 @@ type GroupMembersResponse struct {
 	MemberCount int                 `json:"member_count" example:"3"`
 }
 
-type CreateInviteRequest struct {
-	CreatorID string  `json:"creator_id" example:"user123"`
-	ExpiresAt *string `json:"expires_at,omitempty" example:"2025-12-31T23:59:59Z"`
+type SetMemberRoleRequest struct {
+	UserID string `json:"user_id" example:"user123"`
+	Role   string `json:"role" example:"moderator"`
 }
 
-type JoinGroupRequest struct {
+type SetMemberRoleResponse struct {
+	Message string `json:"message" example:"Role updated successfully"`
+	GroupID string `json:"group_id" example:"group123"`
+	UserID  string `json:"user_id" example:"user123"`
+	Role    string `json:"role" example:"moderator"`
+}
+
+type TransferOwnershipRequest struct {
 	UserID string `json:"user_id" example:"user123"`
 }
 
-type DeactivateInviteRequest struct {
-	RequesterID string `json:"requester_id" example:"user123"`
+type OwnershipTransfersResponse struct {
+	GroupID       string                    `json:"group_id" example:"group123"`
+	Transfers     []group.OwnershipTransfer `json:"transfers"`
+	TransferCount int                       `json:"transfer_count" example:"1"`
+}
+
+type LeaderboardResponse struct {
+	GroupID     string                   `json:"group_id" example:"group123"`
+	ScoringMode string                   `json:"scoring_mode" example:"active_days"`
+	StartDate   string                   `json:"start_date" example:"2025-01-01T00:00:00Z"`
+	EndDate     string                   `json:"end_date" example:"2025-12-31T00:00:00Z"`
+	Entries     []group.LeaderboardEntry `json:"entries"`
+}
+
+type GroupProgressResponse struct {
+	GroupID   int            `json:"group_id" example:"1"`
+	StartDate string         `json:"start_date" example:"2025-01-01T00:00:00Z"`
+	EndDate   string         `json:"end_date" example:"2025-12-31T00:00:00Z"`
+	Today     string         `json:"today" example:"2025-07-01T00:00:00Z"`
+	Progress  group.Progress `json:"progress"`
+}
+
+type FeedResponse struct {
+	Items      []group.FeedItem `json:"items"`
+	NextCursor *string          `json:"next_cursor" example:"MjAyNS0wNy0wMS80Mg"`
+}
+
+type ReactionsResponse struct {
+	ActivityID int                `json:"activity_id" example:"1"`
+	Reactions  []reaction.Summary `json:"reactions"`
+}
+
+type CreateInviteRequest struct {
+	ExpiresAt *string `json:"expires_at,omitempty" example:"2025-12-31T23:59:59Z"`
+}
+
+type JoinGroupRequest struct {
+	Nickname *string `json:"nickname,omitempty" example:"Cool Coder"`
 }
 
 type SetNicknameRequest struct {
-	UserID      string `json:"user_id" example:"user123"`
-	RequesterID string `json:"requester_id" example:"user456"`
-	Nickname    string `json:"nickname" example:"Cool Coder"`
+	UserID   string `json:"user_id,omitempty" example:"user123"`
+	Nickname string `json:"nickname" example:"Cool Coder"`
 }
 
 type DeleteNicknameRequest struct {
-	UserID      string `json:"user_id" example:"user123"`
-	RequesterID string `json:"requester_id" example:"user456"`
+	UserID string `json:"user_id,omitempty" example:"user123"`
 }
 
 type ActivityCreateRequest struct {
-	Title         string  `json:"title" example:"Algorithm Contest"`
-	Date          string  `json:"date" example:"2025-12-31"`
-	ActivityImage *string `json:"activity_image,omitempty" example:"https://example.com/image.jpg"`
-	Description   *string `json:"description,omitempty" example:"A competitive programming contest"`
+	Title            string   `json:"title" example:"Algorithm Contest"`
+	Date             string   `json:"date" example:"2025-12-31"`
+	ActivityImage    *string  `json:"activity_image,omitempty" example:"https://example.com/image.jpg"`
+	Description      *string  `json:"description,omitempty" example:"A competitive programming contest"`
+	Judge            *string  `json:"judge,omitempty" example:"codeforces"`
+	ProblemID        *string  `json:"problem_id,omitempty" example:"1850A"`
+	ProblemURL       *string  `json:"problem_url,omitempty" example:"https://codeforces.com/problemset/problem/1850/A"`
+	Rating           *int     `json:"rating,omitempty" example:"1600"`
+	Difficulty       *string  `json:"difficulty,omitempty" example:"medium"`
+	Tags             []string `json:"tags,omitempty" example:"dp,greedy"`
+	Verdict          *string  `json:"verdict,omitempty" example:"accepted"`
+	Language         *string  `json:"language,omitempty" example:"C++17"`
+	TimeSpentMinutes *int     `json:"time_spent_minutes,omitempty" example:"45"`
+	GroupIDs         []int    `json:"group_ids,omitempty"`
 }
 
 type ActivityUpdateRequest struct {
-	Date          *string `json:"date,omitempty" example:"2025-12-31"`
-	ActivityImage *string `json:"activity_image,omitempty" example:"https://example.com/image.jpg"`
-	Description   *string `json:"description,omitempty" example:"Updated description"`
+	Date             *string  `json:"date,omitempty" example:"2025-12-31"`
+	ActivityImage    *string  `json:"activity_image,omitempty" example:"https://example.com/image.jpg"`
+	Description      *string  `json:"description,omitempty" example:"Updated description"`
+	Judge            *string  `json:"judge,omitempty" example:"codeforces"`
+	ProblemID        *string  `json:"problem_id,omitempty" example:"1850A"`
+	ProblemURL       *string  `json:"problem_url,omitempty" example:"https://codeforces.com/problemset/problem/1850/A"`
+	Rating           *int     `json:"rating,omitempty" example:"1600"`
+	Difficulty       *string  `json:"difficulty,omitempty" example:"medium"`
+	Tags             []string `json:"tags,omitempty" example:"dp,greedy"`
+	Verdict          *string  `json:"verdict,omitempty" example:"accepted"`
+	Language         *string  `json:"language,omitempty" example:"C++17"`
+	TimeSpentMinutes *int     `json:"time_spent_minutes,omitempty" example:"45"`
 }
 
-type ActivityDeleteRequest struct {
-	CreatorID string `json:"creator_id" example:"user123"`
+type ActivityGroupsResponse struct {
+	ActivityID string `json:"activity_id" example:"activity123"`
+	GroupIDs   []int  `json:"group_ids"`
 }
 
-type CommentCreateRequest struct {
-	UserID  string `json:"user_id" example:"user123"`
-	Content string `json:"content" example:"Great activity!"`
+type ActivityGroupRequest struct {
+	GroupID string `json:"group_id" example:"group123"`
+}
+
+type ActivityGroupResponse struct {
+	Message    string `json:"message" example:"Activity posted to group successfully"`
+	ActivityID string `json:"activity_id" example:"activity123"`
+	GroupID    string `json:"group_id" example:"group123"`
 }
 
-type CommentDeleteRequest struct {
-	RequesterID string `json:"requester_id" example:"user123"`
+type GroupActivitiesResponse struct {
+	GroupID       string                 `json:"group_id" example:"group123"`
+	Activities    []group.PostedActivity `json:"activities"`
+	ActivityCount int                    `json:"activity_count" example:"3"`
+}
+
+type CommentCreateRequest struct {
+	Content string `json:"content" example:"Great activity!"`
 }
 
 type CommentsResponse struct {
@@ -128,3 +230,117 @@ type CommentDeleteResponse struct {
 	Message   string `json:"message" example:"Comment deleted successfully"`
 	CommentID string `json:"comment_id" example:"comment123"`
 }
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" example:"2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
+	Password string `json:"password" example:"new-password123"`
+}
+
+type VerifyEmailRequest struct {
+	Token *string `json:"token,omitempty" example:"2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
+}
+
+type ProvidersResponse struct {
+	Providers []string `json:"providers" example:"github,google"`
+}
+
+type TokenCreateRequest struct {
+	Name      string   `json:"name" example:"vim plugin"`
+	Scopes    []string `json:"scopes" example:"activities:write,groups:read"`
+	ExpiresAt *string  `json:"expires_at,omitempty" example:"2026-12-31T23:59:59Z"`
+}
+
+type TokenCreateResponse struct {
+	Token   string                  `json:"token" example:"cdk_2vVt3aX1k9yQb0mR7nH5cJ8wL4eZ6uP2sD1fG3hT0oA"`
+	Details pat.PersonalAccessToken `json:"details"`
+}
+
+type TokensResponse struct {
+	Tokens []pat.PersonalAccessToken `json:"tokens"`
+}
+
+type LoginChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required" example:"true"`
+	Challenge         string `json:"challenge" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt         string `json:"expires_at" example:"2025-12-31T23:59:59Z"`
+}
+
+type TwoFactorLoginRequest struct {
+	Challenge string `json:"challenge" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Code      string `json:"code" example:"123456"`
+}
+
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/CODECK:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=CODECK"`
+	QRCode     string `json:"qr_code" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3f9a-2mzq7,p8x2c-4tn6d"`
+}
+
+type StreakResponse struct {
+	UserID        int     `json:"user_id" example:"1"`
+	Timezone      string  `json:"timezone" example:"America/Sao_Paulo"`
+	Today         string  `json:"today" example:"2025-07-03T00:00:00Z"`
+	CurrentStreak int     `json:"current_streak" example:"3"`
+	LongestStreak int     `json:"longest_streak" example:"12"`
+	LastActiveDay *string `json:"last_active_day" example:"2025-07-03T00:00:00Z"`
+}
+
+type CalendarResponse struct {
+	UserID   int                    `json:"user_id" example:"1"`
+	Timezone string                 `json:"timezone" example:"America/Sao_Paulo"`
+	From     string                 `json:"from" example:"2025-01-01T00:00:00Z"`
+	To       string                 `json:"to" example:"2025-12-31T00:00:00Z"`
+	Days     []activity.CalendarDay `json:"days"`
+}
+
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" example:"America/Sao_Paulo"`
+}
+
+type StreakFreezeRequest struct {
+	Date string `json:"date" example:"2025-07-04"`
+}
+
+type LinkAccountRequest struct {
+	Handle   string `json:"handle" example:"tourist"`
+	GroupIDs []int  `json:"group_ids" example:"1,2"`
+}
+
+type LinkedAccountsResponse struct {
+	UserID   int                           `json:"user_id" example:"1"`
+	Accounts []linkedaccount.LinkedAccount `json:"accounts"`
+}
+
+type SyncStatusResponse struct {
+	Accounts []linkedaccount.SyncStatus `json:"accounts"`
+}
+
+type ProblemResponse struct {
+	Problem     problem.Problem  `json:"problem"`
+	SolveCount  int              `json:"solve_count" example:"2"`
+	FirstSolver *problem.Solver  `json:"first_solver"`
+	Solvers     []problem.Solver `json:"solvers"`
+}
+
+type GroupProblemsResponse struct {
+	GroupID  int                    `json:"group_id" example:"1"`
+	Problems []problem.GroupProblem `json:"problems"`
+}
+
+type RecommendationsResponse struct {
+	EstimatedRating int                      `json:"estimated_rating" example:"1400"`
+	Seed            int64                    `json:"seed" example:"20378"`
+	Recommendations []problem.Recommendation `json:"recommendations"`
+}
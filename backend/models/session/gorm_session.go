@@ -0,0 +1,111 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormSessionModel struct {
+	db *gorm.DB
+}
+
+func NewGormSessionModel(db *gorm.DB) *GormSessionModel {
+	return &GormSessionModel{db: db}
+}
+
+func (m *GormSessionModel) CreateSession(s Session, refreshTokenHash string) (Session, bool) {
+	s.LastUsedAt = time.Now()
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&s).Error; err != nil {
+			return err
+		}
+		return tx.Create(&RefreshToken{SessionID: s.ID, TokenHash: refreshTokenHash}).Error
+	})
+	if err != nil {
+		return Session{}, false
+	}
+	return s, true
+}
+
+func (m *GormSessionModel) GetSessionByID(id int) (Session, bool) {
+	var s Session
+	if err := m.db.First(&s, "id = ?", id).Error; err != nil {
+		return Session{}, false
+	}
+	return s, true
+}
+
+func (m *GormSessionModel) GetActiveSessionsByUserID(userID int) []Session {
+	var list []Session
+	m.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&list)
+	return list
+}
+
+func (m *GormSessionModel) RotateRefreshToken(oldHash, newHash string, expiresAt time.Time) (Session, error) {
+	var s Session
+	reused := false
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var token RefreshToken
+		if err := tx.First(&token, "token_hash = ?", oldHash).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenNotFound
+			}
+			return err
+		}
+		if err := tx.First(&s, "id = ?", token.SessionID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !s.IsActive(now) {
+			return ErrSessionInactive
+		}
+
+		// Claim the token atomically so two concurrent refreshes cannot both succeed
+		result := tx.Model(&RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL", token.ID).
+			Update("rotated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused = true
+			return ErrRefreshTokenReused
+		}
+
+		if err := tx.Create(&RefreshToken{SessionID: s.ID, TokenHash: newHash}).Error; err != nil {
+			return err
+		}
+		s.LastUsedAt = now
+		s.ExpiresAt = expiresAt
+		return tx.Model(&s).Updates(map[string]interface{}{"last_used_at": now, "expires_at": expiresAt}).Error
+	})
+	if reused {
+		m.RevokeSession(s.ID)
+	}
+	if err != nil {
+		return Session{}, err
+	}
+	return s, nil
+}
+
+func (m *GormSessionModel) RevokeSession(id int) bool {
+	result := m.db.Model(&Session{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+	return result.RowsAffected > 0
+}
+
+func (m *GormSessionModel) RevokeUserSessions(userID int) int {
+	result := m.db.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now())
+	return int(result.RowsAffected)
+}
+
+func (m *GormSessionModel) Clear() {
+	m.db.Exec("DELETE FROM refresh_tokens")
+	m.db.Exec("ALTER SEQUENCE refresh_tokens_id_seq RESTART WITH 1")
+	m.db.Exec("DELETE FROM sessions")
+	m.db.Exec("ALTER SEQUENCE sessions_id_seq RESTART WITH 1")
+}
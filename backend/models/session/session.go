@@ -0,0 +1,30 @@
+package session
+
+import "time"
+
+// Session is a logged-in device. It stays valid until it expires or is revoked, and is renewed through refresh tokens.
+type Session struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     int        `gorm:"not null;index" json:"user_id"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	IPAddress  string     `gorm:"type:text" json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Current    bool       `gorm:"-" json:"current"`
+}
+
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken stores the hash of a refresh token issued for a session. Rotated tokens are kept
+// so that presenting one again can be detected as reuse.
+type RefreshToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID int        `gorm:"not null;index" json:"session_id"`
+	TokenHash string     `gorm:"type:text;uniqueIndex;not null" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
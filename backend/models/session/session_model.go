@@ -0,0 +1,27 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+	ErrSessionInactive      = errors.New("session expired or revoked")
+)
+
+type SessionModel interface {
+	CreateSession(s Session, refreshTokenHash string) (Session, bool)
+	GetSessionByID(id int) (Session, bool)
+	GetActiveSessionsByUserID(userID int) []Session
+	// RotateRefreshToken consumes oldHash and stores newHash for the same session, extending it until expiresAt.
+	// Presenting an already rotated token revokes the whole session and returns ErrRefreshTokenReused.
+	RotateRefreshToken(oldHash, newHash string, expiresAt time.Time) (Session, error)
+	RevokeSession(id int) bool
+	// RevokeUserSessions revokes every active session of userID and returns how many there were.
+	RevokeUserSessions(userID int) int
+}
+
+// DefaultSessionModel must be set in main.go after DB initialization
+var DefaultSessionModel SessionModel
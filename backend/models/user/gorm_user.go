@@ -1,6 +1,11 @@
 package user
 
-import "gorm.io/gorm"
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type GormUserModel struct {
 	db *gorm.DB
@@ -27,6 +32,12 @@ func (m *GormUserModel) GetUserByEmail(email string) (User, bool) {
 }
 
 func (m *GormUserModel) CreateUser(u User) User {
+	hash, err := HashPassword(u.Password)
+	if err != nil {
+		log.Printf("Failed to hash password for email=%s: %v", u.Email, err)
+		return User{}
+	}
+	u.Password = hash
 	m.db.Create(&u)
 	return u
 }
@@ -34,22 +45,235 @@ func (m *GormUserModel) CreateUser(u User) User {
 func (m *GormUserModel) ValidateCredentials(email, password string) (User, bool) {
 	var u User
 	if err := m.db.First(&u, "email = ?", email).Error; err != nil {
+		checkPassword(string(dummyHash), password)
 		return User{}, false
 	}
-	if u.Password != password {
+
+	valid, needsRehash := checkPassword(u.Password, password)
+	if !valid {
+		m.RecordLoginFailure(u.ID)
 		return User{}, false
 	}
+
+	if u.FailedLogins > 0 {
+		m.db.Model(&u).Updates(map[string]interface{}{"failed_logins": 0, "locked_until": nil})
+	}
+
+	if needsRehash {
+		if hash, err := HashPassword(password); err != nil {
+			log.Printf("Failed to rehash password for user_id=%d: %v", u.ID, err)
+		} else if err := m.db.Model(&u).Update("password", hash).Error; err != nil {
+			log.Printf("Failed to store rehashed password for user_id=%d: %v", u.ID, err)
+		}
+	}
 	return u, true
 }
 
+func (m *GormUserModel) LockedUntil(email string) (time.Time, bool) {
+	var u User
+	if err := m.db.Select("locked_until").First(&u, "email = ?", email).Error; err != nil {
+		return time.Time{}, false
+	}
+	if u.LockedUntil == nil || !time.Now().Before(*u.LockedUntil) {
+		return time.Time{}, false
+	}
+	return *u.LockedUntil, true
+}
+
+func (m *GormUserModel) RecordLoginFailure(userID int) {
+	if err := m.db.Model(&User{}).Where("id = ?", userID).
+		Update("failed_logins", gorm.Expr("failed_logins + 1")).Error; err != nil {
+		log.Printf("Failed to record login failure for user_id=%d: %v", userID, err)
+		return
+	}
+
+	var u User
+	if err := m.db.Select("failed_logins").First(&u, "id = ?", userID).Error; err != nil {
+		return
+	}
+	if d := lockoutDuration(u.FailedLogins); d > 0 {
+		m.db.Model(&User{}).Where("id = ?", userID).Update("locked_until", time.Now().Add(d))
+		log.Printf("Locked user_id=%d for %s after %d failed logins", userID, d, u.FailedLogins)
+	}
+}
+
+func (m *GormUserModel) UpdatePassword(userID int, password string) bool {
+	hash, err := HashPassword(password)
+	if err != nil {
+		log.Printf("Failed to hash password for user_id=%d: %v", userID, err)
+		return false
+	}
+	// A reset proves control of the mailbox, so it also lifts any lockout
+	result := m.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"password":      hash,
+		"failed_logins": 0,
+		"locked_until":  nil,
+	})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormUserModel) MarkEmailVerified(userID int) (User, bool) {
+	var u User
+	if err := m.db.First(&u, "id = ?", userID).Error; err != nil {
+		return User{}, false
+	}
+	if u.EmailVerifiedAt != nil {
+		return u, true
+	}
+	now := time.Now()
+	if err := m.db.Model(&u).Update("email_verified_at", now).Error; err != nil {
+		return User{}, false
+	}
+	u.EmailVerifiedAt = &now
+	return u, true
+}
+
+func (m *GormUserModel) CreateUserToken(token UserToken) bool {
+	return m.db.Create(&token).Error == nil
+}
+
+func (m *GormUserModel) ConsumeUserToken(purpose, tokenHash string) (UserToken, bool) {
+	return m.consumeUserToken(purpose, tokenHash, nil)
+}
+
+func (m *GormUserModel) ConsumeUserTokenOf(userID int, purpose, tokenHash string) (UserToken, bool) {
+	return m.consumeUserToken(purpose, tokenHash, &userID)
+}
+
+// consumeUserToken consumes the token with tokenHash, only if it belongs to userID when that is set.
+func (m *GormUserModel) consumeUserToken(purpose, tokenHash string, userID *int) (UserToken, bool) {
+	var token UserToken
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		query := tx.Where("token_hash = ? AND purpose = ?", tokenHash, purpose)
+		if userID != nil {
+			query = query.Where("user_id = ?", *userID)
+		}
+		if err := query.First(&token).Error; err != nil {
+			return err
+		}
+
+		// The used_at check makes concurrent attempts with the same token race for a single row update
+		result := tx.Model(&UserToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		token.UsedAt = &now
+
+		return tx.Model(&UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, purpose).
+			Update("used_at", now).Error
+	})
+	if err != nil {
+		return UserToken{}, false
+	}
+	return token, true
+}
+
+func (m *GormUserModel) SetTOTPSecret(userID int, secret string) bool {
+	result := m.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":     secret,
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormUserModel) EnableTOTP(userID int, recoveryCodeHashes []string) bool {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]RecoveryCode, len(recoveryCodeHashes))
+		for i, hash := range recoveryCodeHashes {
+			codes[i] = RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		if len(codes) > 0 {
+			if err := tx.Create(&codes).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&User{}).Where("id = ?", userID).Update("totp_enabled_at", time.Now()).Error
+	})
+	return err == nil
+}
+
+func (m *GormUserModel) DisableTOTP(userID int) bool {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error
+	})
+	return err == nil
+}
+
+func (m *GormUserModel) RecordTOTPStep(userID int, step int64) bool {
+	result := m.db.Model(&User{}).Where("id = ? AND totp_last_step < ?", userID, step).Update("totp_last_step", step)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormUserModel) ConsumeRecoveryCode(userID int, codeHash string) bool {
+	result := m.db.Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormUserModel) CountRecoveryCodes(userID int) int {
+	var count int64
+	m.db.Model(&RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	return int(count)
+}
+
+func (m *GormUserModel) SetTimezone(userID int, timezone string) (User, bool) {
+	var u User
+	if err := m.db.First(&u, "id = ?", userID).Error; err != nil {
+		return User{}, false
+	}
+	if err := m.db.Model(&u).Update("timezone", timezone).Error; err != nil {
+		return User{}, false
+	}
+	u.Timezone = timezone
+	return u, true
+}
+
+func (m *GormUserModel) AddStreakFreeze(freeze StreakFreeze) bool {
+	return m.db.Create(&freeze).Error == nil
+}
+
+func (m *GormUserModel) DeleteStreakFreeze(userID int, day time.Time) bool {
+	result := m.db.Where("user_id = ? AND day = ?", userID, day).Delete(&StreakFreeze{})
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+func (m *GormUserModel) GetStreakFreezes(userID int) []StreakFreeze {
+	var freezes []StreakFreeze
+	m.db.Where("user_id = ?", userID).Order("day").Find(&freezes)
+	return freezes
+}
+
 func (m *GormUserModel) Clear() {
+	m.db.Exec("DELETE FROM streak_freezes")
+	m.db.Exec("DELETE FROM recovery_codes")
+	m.db.Exec("ALTER SEQUENCE recovery_codes_id_seq RESTART WITH 1")
+	m.db.Exec("DELETE FROM user_tokens")
+	m.db.Exec("ALTER SEQUENCE user_tokens_id_seq RESTART WITH 1")
 	m.db.Exec("DELETE FROM users")
 	m.db.Exec("ALTER SEQUENCE users_id_seq RESTART WITH 1")
 }
 
 func (m *GormUserModel) SeedDefaultData() {
 	m.CreateUser(User{
-		ID:       1,
 		Email:    "user@example.com",
 		Name:     "Test User",
 		Password: "password123",
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordCost is the bcrypt cost used for new hashes. Stored hashes with a lower cost are upgraded on login.
+var PasswordCost = bcrypt.DefaultCost
+
+const (
+	// LockoutThreshold is how many failed logins in a row lock an account.
+	LockoutThreshold = 5
+	// LockoutBase is the first lockout; each further failure doubles it up to LockoutMax.
+	LockoutBase = 30 * time.Second
+	LockoutMax  = time.Hour
+)
+
+// lockoutDuration returns how long an account is locked after failures consecutive failed logins.
+func lockoutDuration(failures int) time.Duration {
+	if failures < LockoutThreshold {
+		return 0
+	}
+	d := LockoutBase
+	for i := LockoutThreshold; i < failures && d < LockoutMax; i++ {
+		d *= 2
+	}
+	if d > LockoutMax {
+		d = LockoutMax
+	}
+	return d
+}
+
+// dummyHash is compared against when an email is unknown so failed lookups take as long as failed passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("codeck-dummy-password"), bcrypt.DefaultCost)
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func isPasswordHash(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// checkPassword reports whether password matches stored and whether stored should be replaced by a fresh hash,
+// which is the case for legacy plaintext rows and hashes created with an older cost.
+func checkPassword(stored, password string) (valid bool, needsRehash bool) {
+	if !isPasswordHash(stored) {
+		valid = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return valid, valid
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || cost < PasswordCost
+}
@@ -7,11 +7,72 @@ import (
 )
 
 type User struct {
-	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
-	Email     string         `gorm:"type:text;unique;not null" json:"email"`
-	Name      string         `gorm:"type:text;not null" json:"name"`
-	Password  string         `gorm:"type:text;not null" json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email           string     `gorm:"type:text;unique;not null" json:"email"`
+	Name            string     `gorm:"type:text;not null" json:"name"`
+	Password        string     `gorm:"type:text;not null" json:"-"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Timezone        string     `gorm:"type:text;not null;default:'UTC'" json:"timezone"`
+	// TOTPSecret is set on enrollment; two-factor login is only required once TOTPEnabledAt is set as well
+	TOTPSecret    string         `gorm:"type:text" json:"-"`
+	TOTPEnabledAt *time.Time     `json:"totp_enabled_at,omitempty"`
+	TOTPLastStep  int64          `gorm:"not null;default:0" json:"-"`
+	FailedLogins  int            `gorm:"not null;default:0" json:"-"`
+	LockedUntil   *time.Time     `json:"-"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (u User) TOTPEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// Location returns the user's IANA time zone, which decides where their days start for streaks.
+// It falls back to UTC when none is set or the zone is unknown.
+func (u User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// MaxStreakFreezesPerMonth limits how many days a user can freeze in one calendar month.
+const MaxStreakFreezesPerMonth = 2
+
+// StreakFreeze is a day the user took off; it keeps their streak going without counting towards it.
+type StreakFreeze struct {
+	UserID    int       `gorm:"primaryKey" json:"user_id"`
+	Day       time.Time `gorm:"primaryKey;type:date" json:"day"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the authenticator is lost.
+type RecoveryCode struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:text;not null" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use, time-limited token mailed to a user, e.g. for a password reset.
+// Only the hash of the token is stored.
+type UserToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	Purpose   string     `gorm:"type:text;not null" json:"purpose"`
+	TokenHash string     `gorm:"type:text;uniqueIndex;not null" json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
 }
@@ -1,10 +1,42 @@
 package user
 
+import "time"
+
 type UserModel interface {
 	GetUserByID(id int) (User, bool)
 	GetUserByEmail(email string) (User, bool)
 	CreateUser(user User) User
+	// ValidateCredentials counts failed attempts against the account; see RecordLoginFailure.
 	ValidateCredentials(email, password string) (User, bool)
+	// LockedUntil reports whether the account for email is locked out and until when.
+	LockedUntil(email string) (time.Time, bool)
+	// RecordLoginFailure counts a failed login step and locks the account once LockoutThreshold is reached.
+	RecordLoginFailure(userID int)
+	UpdatePassword(userID int, password string) bool
+	MarkEmailVerified(userID int) (User, bool)
+	CreateUserToken(token UserToken) bool
+	// ConsumeUserToken marks the unexpired token with tokenHash as used and returns it. It also
+	// invalidates the user's other outstanding tokens for the same purpose.
+	ConsumeUserToken(purpose, tokenHash string) (UserToken, bool)
+	// ConsumeUserTokenOf is ConsumeUserToken for a token that must belong to userID; other users'
+	// tokens are left unused.
+	ConsumeUserTokenOf(userID int, purpose, tokenHash string) (UserToken, bool)
+	// SetTOTPSecret stores a new secret awaiting confirmation and turns two-factor login off until EnableTOTP.
+	SetTOTPSecret(userID int, secret string) bool
+	// EnableTOTP turns on two-factor login and replaces the user's recovery codes.
+	EnableTOTP(userID int, recoveryCodeHashes []string) bool
+	DisableTOTP(userID int) bool
+	// RecordTOTPStep stores step as the last used TOTP time step. It fails if the step is not newer,
+	// which stops a code from being used twice.
+	RecordTOTPStep(userID int, step int64) bool
+	ConsumeRecoveryCode(userID int, codeHash string) bool
+	CountRecoveryCodes(userID int) int
+	SetTimezone(userID int, timezone string) (User, bool)
+	// AddStreakFreeze fails if the day is already frozen.
+	AddStreakFreeze(freeze StreakFreeze) bool
+	DeleteStreakFreeze(userID int, day time.Time) bool
+	// GetStreakFreezes returns all of the user's frozen days, oldest first.
+	GetStreakFreezes(userID int) []StreakFreeze
 }
 
 // DefaultUserModel must be set in main.go after DB initialization
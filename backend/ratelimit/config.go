@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config holds the rate of every limited endpoint group.
+type Config struct {
+	LoginPerIP      Rate
+	LoginPerAccount Rate
+	SignupPerIP     Rate
+	Comments        Rate
+	Activities      Rate
+}
+
+func DefaultConfig() Config {
+	return Config{
+		LoginPerIP:      Rate{Burst: 20, Per: time.Minute},
+		LoginPerAccount: Rate{Burst: 5, Per: time.Minute},
+		SignupPerIP:     Rate{Burst: 5, Per: time.Hour},
+		Comments:        Rate{Burst: 10, Per: time.Minute},
+		Activities:      Rate{Burst: 30, Per: time.Minute},
+	}
+}
+
+// ConfigFromEnv overrides the defaults with RATE_LIMIT_LOGIN_IP, RATE_LIMIT_LOGIN_ACCOUNT,
+// RATE_LIMIT_SIGNUP_IP, RATE_LIMIT_COMMENTS and RATE_LIMIT_ACTIVITIES, each in ParseRate format.
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig()
+	for name, rate := range map[string]*Rate{
+		"RATE_LIMIT_LOGIN_IP":      &config.LoginPerIP,
+		"RATE_LIMIT_LOGIN_ACCOUNT": &config.LoginPerAccount,
+		"RATE_LIMIT_SIGNUP_IP":     &config.SignupPerIP,
+		"RATE_LIMIT_COMMENTS":      &config.Comments,
+		"RATE_LIMIT_ACTIVITIES":    &config.Activities,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := ParseRate(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", name, err)
+		}
+		*rate = parsed
+	}
+	return config, nil
+}
+
+// Limits are the limiters the routes apply, built from a Config over one Store.
+type Limits struct {
+	LoginPerIP      *Limiter
+	LoginPerAccount *Limiter
+	SignupPerIP     *Limiter
+	Comments        *Limiter
+	Activities      *Limiter
+}
+
+func NewLimits(store Store, config Config) *Limits {
+	return &Limits{
+		LoginPerIP:      NewLimiter("login-ip", store, config.LoginPerIP, ByIP),
+		LoginPerAccount: NewLimiter("login-account", store, config.LoginPerAccount, ByJSONField("email")),
+		SignupPerIP:     NewLimiter("signup-ip", store, config.SignupPerIP, ByIP),
+		Comments:        NewLimiter("comments", store, config.Comments, ByUser),
+		Activities:      NewLimiter("activities", store, config.Activities, ByUser),
+	}
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many Take calls pass between removals of full, idle buckets.
+const sweepEvery = 1024
+
+// MemoryStore keeps buckets in process memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	rates   map[string]Rate
+	calls   int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, rates: map[string]Rate{}}
+}
+
+func (s *MemoryStore) Take(key string, rate Rate, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(rate.Burst), updated: now}
+		s.buckets[key] = b
+		s.rates[key] = rate
+	}
+	return b.take(rate, now)
+}
+
+// sweep drops buckets that have refilled completely, since a fresh bucket behaves the same.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= s.rates[key].Per {
+			delete(s.buckets, key)
+			delete(s.rates, key)
+		}
+	}
+}
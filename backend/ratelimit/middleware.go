@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/auth"
+)
+
+// maxPeekBytes bounds how much of a request body ByJSONField reads.
+const maxPeekBytes = 1 << 20
+
+// KeyFunc returns the bucket key for a request. Requests without a key are not limited.
+type KeyFunc func(r *http.Request) (string, bool)
+
+// Limiter applies one Rate to requests grouped by Key.
+type Limiter struct {
+	Name  string
+	Store Store
+	Rate  Rate
+	Key   KeyFunc
+	Now   func() time.Time
+}
+
+func NewLimiter(name string, store Store, rate Rate, key KeyFunc) *Limiter {
+	return &Limiter{Name: name, Store: store, Rate: rate, Key: key, Now: time.Now}
+}
+
+// Middleware answers 429 with Retry-After once the caller's bucket is empty. It has the shape of a
+// mux.MiddlewareFunc, so it can be used with Router.Use as well as on single routes.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l == nil || l.Rate.Disabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, ok := l.Key(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter := l.Store.Take(l.Name+":"+key, l.Rate, l.Now())
+		if !allowed {
+			log.Printf("Rate limit %s exceeded for %s", l.Name, key)
+			TooManyRequests(w, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Limit wraps a single handler with Middleware. The result is a HandlerFunc so it can be passed on to
+// the authenticator's Require.
+func (l *Limiter) Limit(handler http.HandlerFunc) http.HandlerFunc {
+	return l.Middleware(handler).ServeHTTP
+}
+
+// TooManyRequests writes a 429 response telling the client to retry after d, rounded up to whole seconds.
+func TooManyRequests(w http.ResponseWriter, d time.Duration) {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+// ByIP keys requests by the client address of the connection.
+func ByIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr, r.RemoteAddr != ""
+	}
+	return host, true
+}
+
+// ByUser keys requests by the authenticated user, so it must run inside the authenticator.
+func ByUser(r *http.Request) (string, bool) {
+	u, ok := auth.CurrentUser(r)
+	if !ok {
+		return "", false
+	}
+	return strconv.Itoa(u.ID), true
+}
+
+// ByJSONField keys requests by a string field of the JSON body, e.g. the email of a login attempt.
+// The body is restored for the handler.
+func ByJSONField(field string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		if r.Body == nil {
+			return "", false
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBytes))
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return "", false
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return "", false
+		}
+		value, _ := payload[field].(string)
+		value = strings.ToLower(strings.TrimSpace(value))
+		return value, value != ""
+	}
+}
@@ -0,0 +1,77 @@
+// Package ratelimit throttles requests with token buckets. Buckets live in a pluggable Store so that
+// several API instances can share them; MemoryStore is the single-instance default.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate allows Burst requests at once, refilled evenly over Per. "5/1m" is five requests a minute.
+type Rate struct {
+	Burst int
+	Per   time.Duration
+}
+
+// ParseRate parses "<burst>/<duration>", e.g. "10/1m". "0" or "off" disables the limit.
+func ParseRate(s string) (Rate, error) {
+	if s == "0" || s == "off" {
+		return Rate{}, nil
+	}
+	burstStr, perStr, found := strings.Cut(s, "/")
+	if !found {
+		return Rate{}, fmt.Errorf("invalid rate %q, want <burst>/<duration>", s)
+	}
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 0 {
+		return Rate{}, fmt.Errorf("invalid burst in rate %q", s)
+	}
+	per, err := time.ParseDuration(perStr)
+	if err != nil || per <= 0 {
+		return Rate{}, fmt.Errorf("invalid duration in rate %q", s)
+	}
+	return Rate{Burst: burst, Per: per}, nil
+}
+
+func (r Rate) Disabled() bool {
+	return r.Burst == 0
+}
+
+func (r Rate) String() string {
+	if r.Disabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", r.Burst, r.Per)
+}
+
+// Store keeps token buckets by key.
+type Store interface {
+	// Take removes one token from the bucket for key. When the bucket is empty it reports false and how
+	// long until a token is available.
+	Take(key string, rate Rate, now time.Time) (allowed bool, retryAfter time.Duration)
+}
+
+// bucket is the state of one token bucket: tokens left as of updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills b for the time passed since it was last updated and tries to remove one token.
+func (b *bucket) take(rate Rate, now time.Time) (bool, time.Duration) {
+	perToken := rate.Per / time.Duration(rate.Burst)
+	elapsed := now.Sub(b.updated)
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(rate.Burst), b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) * float64(perToken))
+}
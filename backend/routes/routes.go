@@ -1,47 +1,115 @@
 package routes
 
 import (
+	"backend/auth"
 	"backend/controllers"
+	"backend/models/pat"
+	"backend/ratelimit"
 
 	"github.com/gorilla/mux"
 )
 
-func RegisterGroupRoutes(r *mux.Router, groupController *controllers.GroupController) {
-	r.HandleFunc("/groups/{id}", groupController.GetGroup).Methods("GET")
-	r.HandleFunc("/groups", groupController.CreateGroup).Methods("POST")
-	r.HandleFunc("/groups/{id}", groupController.UpdateGroup).Methods("PUT")
-	r.HandleFunc("/groups/{id}", groupController.DeleteGroup).Methods("DELETE")
-	r.HandleFunc("/groups/{id}/members", groupController.GetGroupMembers).Methods("GET")
-	r.HandleFunc("/groups/{id}/members", groupController.AddUserToGroup).Methods("POST")
-	r.HandleFunc("/groups/{id}/members", groupController.RemoveUserFromGroup).Methods("DELETE")
-	r.HandleFunc("/groups/{id}/members/nickname", groupController.SetUserNickname).Methods("PUT")
-	r.HandleFunc("/groups/{id}/members/nickname", groupController.DeleteUserNickname).Methods("DELETE")
-	r.HandleFunc("/groups/{id}/activities", groupController.GetGroupActivities).Methods("GET")
-	r.HandleFunc("/groups/{id}/invites", groupController.CreateInviteLink).Methods("POST")
-	r.HandleFunc("/groups/{id}/invites", groupController.GetGroupInvites).Methods("GET")
-	r.HandleFunc("/invites/{invite_code}/join", groupController.JoinGroupByInvite).Methods("POST")
-	r.HandleFunc("/invites/{invite_code}/deactivate", groupController.DeactivateInvite).Methods("DELETE")
-}
-
-func RegisterActivityRoutes(r *mux.Router, activityController *controllers.ActivityController) {
+func RegisterGroupRoutes(r *mux.Router, groupController *controllers.GroupController, authenticator *auth.Authenticator) {
+	r.Handle("/groups/{id}", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetGroup)).Methods("GET")
+	r.Handle("/groups", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.CreateGroup)).Methods("POST")
+	r.Handle("/groups/{id}", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.UpdateGroup)).Methods("PUT")
+	r.Handle("/groups/{id}", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.DeleteGroup)).Methods("DELETE")
+	r.Handle("/groups/{id}/members", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetGroupMembers)).Methods("GET")
+	r.Handle("/groups/{id}/members", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.AddUserToGroup)).Methods("POST")
+	r.Handle("/groups/{id}/members", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.RemoveUserFromGroup)).Methods("DELETE")
+	r.Handle("/groups/{id}/members/nickname", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.SetUserNickname)).Methods("PUT")
+	r.Handle("/groups/{id}/members/nickname", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.DeleteUserNickname)).Methods("DELETE")
+	r.Handle("/groups/{id}/members/role", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.SetMemberRole)).Methods("PUT")
+	r.Handle("/groups/{id}/transfer", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.TransferOwnership)).Methods("POST")
+	r.Handle("/groups/{id}/transfer/accept", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.AcceptOwnershipTransfer)).Methods("POST")
+	r.Handle("/groups/{id}/transfer", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.CancelOwnershipTransfer)).Methods("DELETE")
+	r.Handle("/groups/{id}/transfers", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetOwnershipTransfers)).Methods("GET")
+	r.Handle("/groups/{id}/leaderboard", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetLeaderboard)).Methods("GET")
+	r.Handle("/groups/{id}/progress", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetGroupProgress)).Methods("GET")
+	r.Handle("/groups/{id}/activities", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetGroupActivities)).Methods("GET")
+	r.Handle("/groups/{id}/invites", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.CreateInviteLink)).Methods("POST")
+	r.Handle("/groups/{id}/invites", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetGroupInvites)).Methods("GET")
+	r.Handle("/users/me/feed", authenticator.RequireScope(pat.ScopeGroupsRead, groupController.GetFeed)).Methods("GET")
+	r.Handle("/invites/{invite_code}/join", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.JoinGroupByInvite)).Methods("POST")
+	r.Handle("/invites/{invite_code}/deactivate", authenticator.RequireScope(pat.ScopeGroupsWrite, groupController.DeactivateInvite)).Methods("DELETE")
+}
+
+func RegisterActivityRoutes(r *mux.Router, activityController *controllers.ActivityController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
 	r.HandleFunc("/activities/{id}", activityController.GetActivity).Methods("GET")
-	r.HandleFunc("/activities", activityController.CreateActivity).Methods("POST")
-	r.HandleFunc("/activities/{id}", activityController.UpdateActivity).Methods("PUT")
-	r.HandleFunc("/activities/{id}", activityController.DeleteActivity).Methods("DELETE")
+	r.Handle("/activities", authenticator.RequireScope(pat.ScopeActivitiesWrite, limits.Activities.Limit(activityController.CreateActivity))).Methods("POST")
+	r.Handle("/activities/{id}", authenticator.RequireScope(pat.ScopeActivitiesWrite, activityController.UpdateActivity)).Methods("PUT")
+	r.Handle("/activities/{id}", authenticator.RequireScope(pat.ScopeActivitiesWrite, activityController.DeleteActivity)).Methods("DELETE")
+	r.HandleFunc("/activities/{id}/groups", activityController.GetActivityGroups).Methods("GET")
+	r.Handle("/activities/{id}/groups", authenticator.RequireScope(pat.ScopeActivitiesWrite, activityController.AddActivityToGroup)).Methods("POST")
+	r.Handle("/activities/{id}/groups/{group_id}", authenticator.RequireScope(pat.ScopeActivitiesWrite, activityController.RemoveActivityFromGroup)).Methods("DELETE")
 }
 
-func RegisterUserRoutes(r *mux.Router, userController *controllers.UserController) {
+func RegisterUserRoutes(r *mux.Router, userController *controllers.UserController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
 	r.HandleFunc("/users/{id}", userController.GetUser).Methods("GET")
-	r.HandleFunc("/users", userController.CreateUser).Methods("POST")
+	r.Handle("/users", limits.SignupPerIP.Limit(userController.CreateUser)).Methods("POST")
 	r.HandleFunc("/users/{id}/activities", userController.GetUserActivities).Methods("GET")
+	r.HandleFunc("/users/{id}/streak", userController.GetUserStreak).Methods("GET")
+	r.HandleFunc("/users/{id}/calendar", userController.GetUserCalendar).Methods("GET")
+	r.Handle("/users/me/timezone", authenticator.Require(userController.SetTimezone)).Methods("PUT")
+	r.Handle("/users/me/streak/freezes", authenticator.Require(userController.AddStreakFreeze)).Methods("POST")
+	r.Handle("/users/me/streak/freezes/{date}", authenticator.Require(userController.DeleteStreakFreeze)).Methods("DELETE")
+}
+
+func RegisterReactionRoutes(r *mux.Router, reactionController *controllers.ReactionController, authenticator *auth.Authenticator) {
+	r.HandleFunc("/activities/{activity_id}/reactions", reactionController.GetReactions).Methods("GET")
+	r.Handle("/activities/{activity_id}/reactions/{kind}", authenticator.RequireScope(pat.ScopeCommentsWrite, reactionController.AddReaction)).Methods("PUT")
+	r.Handle("/activities/{activity_id}/reactions/{kind}", authenticator.RequireScope(pat.ScopeCommentsWrite, reactionController.RemoveReaction)).Methods("DELETE")
 }
 
-func RegisterLoginRoutes(r *mux.Router, loginController *controllers.LoginController) {
-	r.HandleFunc("/login", loginController.Login).Methods("POST")
+func RegisterLoginRoutes(r *mux.Router, loginController *controllers.LoginController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
+	r.Handle("/login", limits.LoginPerIP.Limit(limits.LoginPerAccount.Limit(loginController.Login))).Methods("POST")
+	r.HandleFunc("/auth/refresh", loginController.Refresh).Methods("POST")
+	r.Handle("/logout", authenticator.Require(loginController.Logout)).Methods("POST")
+	r.Handle("/users/me/sessions", authenticator.Require(loginController.GetSessions)).Methods("GET")
+	r.Handle("/users/me/sessions/{id}", authenticator.Require(loginController.RevokeSession)).Methods("DELETE")
 }
 
-func RegisterCommentRoutes(r *mux.Router, commentController *controllers.CommentController) {
+func RegisterCommentRoutes(r *mux.Router, commentController *controllers.CommentController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
 	r.HandleFunc("/activities/{activity_id}/comments", commentController.GetCommentsByActivity).Methods("GET")
-	r.HandleFunc("/activities/{activity_id}/comments", commentController.CreateComment).Methods("POST")
-	r.HandleFunc("/comments/{comment_id}", commentController.DeleteComment).Methods("DELETE")
+	r.Handle("/activities/{activity_id}/comments", authenticator.RequireScope(pat.ScopeCommentsWrite, limits.Comments.Limit(commentController.CreateComment))).Methods("POST")
+	r.Handle("/comments/{comment_id}", authenticator.RequireScope(pat.ScopeCommentsWrite, commentController.DeleteComment)).Methods("DELETE")
+}
+
+func RegisterAccountRoutes(r *mux.Router, accountController *controllers.AccountController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
+	r.Handle("/password/forgot", limits.LoginPerIP.Limit(limits.LoginPerAccount.Limit(accountController.ForgotPassword))).Methods("POST")
+	r.Handle("/password/reset", limits.LoginPerIP.Limit(accountController.ResetPassword)).Methods("POST")
+	r.Handle("/users/me/verify-email", authenticator.Require(accountController.VerifyEmail)).Methods("POST")
+}
+
+func RegisterOAuthRoutes(r *mux.Router, oauthController *controllers.OAuthController) {
+	r.HandleFunc("/auth/providers", oauthController.GetProviders).Methods("GET")
+	r.HandleFunc("/auth/{provider}/login", oauthController.StartLogin).Methods("GET")
+	r.HandleFunc("/auth/{provider}/callback", oauthController.Callback).Methods("GET")
+}
+
+func RegisterTokenRoutes(r *mux.Router, tokenController *controllers.TokenController, authenticator *auth.Authenticator) {
+	r.Handle("/users/me/tokens", authenticator.Require(tokenController.GetTokens)).Methods("GET")
+	r.Handle("/users/me/tokens", authenticator.Require(tokenController.CreateToken)).Methods("POST")
+	r.Handle("/users/me/tokens/{id}", authenticator.Require(tokenController.DeleteToken)).Methods("DELETE")
+}
+
+func RegisterTwoFactorRoutes(r *mux.Router, twoFactorController *controllers.TwoFactorController, authenticator *auth.Authenticator, limits *ratelimit.Limits) {
+	r.Handle("/login/2fa", limits.LoginPerIP.Limit(twoFactorController.CompleteLogin)).Methods("POST")
+	r.Handle("/users/me/2fa/totp", authenticator.Require(twoFactorController.EnrollTOTP)).Methods("POST")
+	r.Handle("/users/me/2fa/totp/confirm", authenticator.Require(twoFactorController.ConfirmTOTP)).Methods("POST")
+	r.Handle("/users/me/2fa/totp", authenticator.Require(twoFactorController.DisableTOTP)).Methods("DELETE")
+}
+
+func RegisterLinkedAccountRoutes(r *mux.Router, linkedAccountController *controllers.LinkedAccountController, authenticator *auth.Authenticator) {
+	r.HandleFunc("/users/{id}/accounts", linkedAccountController.GetUserAccounts).Methods("GET")
+	r.Handle("/users/me/accounts/{judge}", authenticator.Require(linkedAccountController.LinkAccount)).Methods("PUT")
+	r.Handle("/users/me/accounts/{judge}", authenticator.Require(linkedAccountController.UnlinkAccount)).Methods("DELETE")
+	r.Handle("/users/me/sync", authenticator.Require(linkedAccountController.TriggerSync)).Methods("POST")
+	r.Handle("/users/me/sync-status", authenticator.Require(linkedAccountController.GetSyncStatus)).Methods("GET")
+}
+
+func RegisterProblemRoutes(r *mux.Router, problemController *controllers.ProblemController, authenticator *auth.Authenticator) {
+	r.Handle("/problems/{id}", authenticator.RequireScope(pat.ScopeGroupsRead, problemController.GetProblem)).Methods("GET")
+	r.Handle("/groups/{id}/problems", authenticator.RequireScope(pat.ScopeGroupsRead, problemController.GetGroupProblems)).Methods("GET")
+	r.Handle("/users/me/recommendations", authenticator.Require(problemController.GetRecommendations)).Methods("GET")
 }
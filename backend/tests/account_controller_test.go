@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/models/user"
+)
+
+func setupAccountTest() {
+	setupLoginTest()
+	testMailer.Reset()
+}
+
+// mailedToken returns the token from the link in the last mail sent to address.
+func mailedToken(t *testing.T, address string) string {
+	msg, ok := testMailer.Last(address)
+	if !ok {
+		t.Fatalf("No mail sent to %s", address)
+	}
+	_, rest, found := strings.Cut(msg.Body, "?token=")
+	if !found {
+		t.Fatalf("No token link in mail: %q", msg.Body)
+	}
+	token, _, _ := strings.Cut(rest, "\n")
+	return token
+}
+
+func postAccount(path string, payload map[string]interface{}, userID int) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != 0 {
+		authorize(req, userID)
+	}
+
+	recorder := httptest.NewRecorder()
+	testAccountRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestForgotPasswordSendsMail(t *testing.T) {
+	setupAccountTest()
+
+	recorder := postAccount("/password/forgot", map[string]interface{}{"email": "user@example.com"}, 0)
+	if status := recorder.Code; status != http.StatusAccepted {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+
+	msg, ok := testMailer.Last("user@example.com")
+	if !ok {
+		t.Fatal("Expected a password reset mail")
+	}
+	if !strings.Contains(msg.Body, "http://codeck.test/reset-password?token=") {
+		t.Errorf("Reset mail should link to the frontend, got %q", msg.Body)
+	}
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	setupAccountTest()
+
+	// Same answer as for a known address, but nothing is sent
+	recorder := postAccount("/password/forgot", map[string]interface{}{"email": "nobody@example.com"}, 0)
+	if status := recorder.Code; status != http.StatusAccepted {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+	if len(testMailer.Messages()) != 0 {
+		t.Error("No mail should be sent for an unknown address")
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	setupAccountTest()
+	oldSession := login(t, "old-device")
+
+	postAccount("/password/forgot", map[string]interface{}{"email": "user@example.com"}, 0)
+	token := mailedToken(t, "user@example.com")
+
+	recorder := postAccount("/password/reset", map[string]interface{}{"token": token, "password": "brand-new-password"}, 0)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if _, valid := testUserModel.ValidateCredentials("user@example.com", "brand-new-password"); !valid {
+		t.Error("New password should validate")
+	}
+	if _, valid := testUserModel.ValidateCredentials("user@example.com", "password123"); valid {
+		t.Error("Old password should no longer validate")
+	}
+
+	// Existing sessions are logged out
+	if status := withToken("GET", "/users/me/sessions", oldSession["token"].(string)).Code; status != http.StatusUnauthorized {
+		t.Errorf("Session from before the reset returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Tokens are single-use
+	recorder = postAccount("/password/reset", map[string]interface{}{"token": token, "password": "another-password"}, 0)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("Reused token returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestResetPasswordInvalidToken(t *testing.T) {
+	setupAccountTest()
+
+	recorder := postAccount("/password/reset", map[string]interface{}{"token": "not-a-token", "password": "brand-new-password"}, 0)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	recorder = postAccount("/password/reset", map[string]interface{}{"token": "not-a-token"}, 0)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestResetPasswordOnlyLatestTokenSurvivesUse(t *testing.T) {
+	setupAccountTest()
+
+	postAccount("/password/forgot", map[string]interface{}{"email": "user@example.com"}, 0)
+	first := mailedToken(t, "user@example.com")
+	postAccount("/password/forgot", map[string]interface{}{"email": "user@example.com"}, 0)
+	second := mailedToken(t, "user@example.com")
+
+	if status := postAccount("/password/reset", map[string]interface{}{"token": second, "password": "brand-new-password"}, 0).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	// Using one reset token invalidates the others that were mailed earlier
+	if status := postAccount("/password/reset", map[string]interface{}{"token": first, "password": "another-password"}, 0).Code; status != http.StatusBadRequest {
+		t.Errorf("Older token returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	setupAccountTest()
+
+	recorder := postAccount("/users/me/verify-email", map[string]interface{}{}, 1)
+	if status := recorder.Code; status != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+	token := mailedToken(t, "user@example.com")
+
+	recorder = postAccount("/users/me/verify-email", map[string]interface{}{"token": token}, 1)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var verified user.User
+	if err := json.NewDecoder(recorder.Body).Decode(&verified); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	if verified.EmailVerifiedAt == nil {
+		t.Error("Expected email_verified_at to be set")
+	}
+
+	// Nothing left to verify
+	recorder = postAccount("/users/me/verify-email", map[string]interface{}{}, 1)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestVerifyEmailTokenOfOtherUser(t *testing.T) {
+	setupAccountTest()
+
+	postAccount("/users/me/verify-email", map[string]interface{}{}, 1)
+	token := mailedToken(t, "user@example.com")
+
+	recorder := postAccount("/users/me/verify-email", map[string]interface{}{"token": token}, 2)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if u, _ := testUserModel.GetUserByID(2); u.EmailVerifiedAt != nil {
+		t.Error("Another user's token should not verify the requester")
+	}
+
+	// The token still works for its owner
+	if status := postAccount("/users/me/verify-email", map[string]interface{}{"token": token}, 1).Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestVerifyEmailMissingToken(t *testing.T) {
+	setupAccountTest()
+
+	recorder := postAccount("/users/me/verify-email", map[string]interface{}{}, 0)
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
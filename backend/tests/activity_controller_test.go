@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"backend/models/activity"
+	"backend/models/group"
 )
 
 func setupActivityTest() {
@@ -29,6 +31,7 @@ func TestCreateActivityValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -49,6 +52,7 @@ func TestCreateActivityInvalid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -95,6 +99,7 @@ func TestUpdateActivityValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -116,6 +121,7 @@ func TestUpdateActivityInvalid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -128,16 +134,11 @@ func TestUpdateActivityInvalid(t *testing.T) {
 
 func TestDeleteActivityInvalid(t *testing.T) {
 	setupActivityTest()
-	invalidRequest := map[string]interface{}{
-		"creator_id": 2,
-	}
-
-	body, _ := json.Marshal(invalidRequest)
-	req, err := http.NewRequest("DELETE", "/activities/1", bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/activities/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2)
 
 	recorder := httptest.NewRecorder()
 	testActivityRouter.ServeHTTP(recorder, req)
@@ -149,21 +150,364 @@ func TestDeleteActivityInvalid(t *testing.T) {
 
 func TestDeleteActivityValid(t *testing.T) {
 	setupActivityTest()
-	validRequest := map[string]interface{}{
-		"creator_id": 1,
+	req, err := http.NewRequest("DELETE", "/activities/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorize(req, 1)
+
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+}
+
+func TestCreateActivityUsesAuthenticatedCreator(t *testing.T) {
+	setupActivityTest()
+	validActivity := map[string]interface{}{
+		"title":      "Spoofed Activity",
+		"date":       "2025-12-31",
+		"creator_id": 1, // Ignored, the creator comes from the token
 	}
 
-	body, _ := json.Marshal(validRequest)
-	req, err := http.NewRequest("DELETE", "/activities/1", bytes.NewBuffer(body))
+	body, _ := json.Marshal(validActivity)
+	req, err := http.NewRequest("POST", "/activities", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
 	testActivityRouter.ServeHTTP(recorder, req)
 
-	if status := recorder.Code; status != http.StatusNoContent {
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created activity.Activity
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+
+	if created.CreatorID != 2 {
+		t.Errorf("Expected CreatorID to be 2, got %v", created.CreatorID)
+	}
+}
+
+func TestCreateActivityMissingToken(t *testing.T) {
+	setupActivityTest()
+	validActivity := map[string]interface{}{
+		"title": "Anonymous Activity",
+		"date":  "2025-12-31",
+	}
+
+	body, _ := json.Marshal(validActivity)
+	req, err := http.NewRequest("POST", "/activities", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func postActivity(requesterID int, payload map[string]interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/activities", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	authorize(req, requesterID)
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCreateActivityPostsToGroups(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+
+	recorder := postActivity(1, map[string]interface{}{
+		"title":     "Posted Activity",
+		"date":      "2025-12-31",
+		"group_ids": []int{1, 1},
+	})
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created activity.Activity
+	json.NewDecoder(recorder.Body).Decode(&created)
+
+	groupIDs := testGroupModel.GetActivityGroupIDs(created.ID)
+	if len(groupIDs) != 1 || groupIDs[0] != 1 {
+		t.Errorf("Expected activity to be posted to group 1, got %v", groupIDs)
+	}
+}
+
+func TestCreateActivityRejectsForeignGroup(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+
+	recorder := postActivity(2, map[string]interface{}{
+		"title":     "Intruder Activity",
+		"date":      "2025-12-31",
+		"group_ids": []int{1},
+	})
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// Nothing is created when a group is rejected
+	if activities := testActivityModel.GetActivitiesByCreatorID(2); len(activities) != 0 {
+		t.Errorf("Expected no activity to be created, got %d", len(activities))
+	}
+
+	recorder = postActivity(1, map[string]interface{}{
+		"title":     "Lost Activity",
+		"date":      "2025-12-31",
+		"group_ids": []int{999},
+	})
+	if status := recorder.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestCreatePostedActivityIsAllOrNothing(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+	before := len(testActivityModel.GetActivitiesByCreatorID(1))
+
+	// The second link to the same group fails, which must undo the activity and the first link
+	a := activity.Activity{Title: "Half Posted", CreatorID: 1, Date: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)}
+	if _, ok := testGroupModel.CreatePostedActivity(a, []int{1, 1}); ok {
+		t.Fatal("Expected posting twice to the same group to fail")
+	}
+	if after := len(testActivityModel.GetActivitiesByCreatorID(1)); after != before {
+		t.Errorf("Expected no activity to be left behind, got %d more", after-before)
+	}
+	if posted, _ := testGroupModel.GetGroupActivities(1); len(posted) != 0 {
+		t.Errorf("Expected nothing posted to group 1, got %d activities", len(posted))
+	}
+}
+
+func TestAddAndRemoveActivityGroup(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+
+	post := func(requesterID int) int {
+		body, _ := json.Marshal(map[string]interface{}{"group_id": 1})
+		req, _ := http.NewRequest("POST", "/activities/1/groups", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		authorize(req, requesterID)
+		recorder := httptest.NewRecorder()
+		testActivityRouter.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	remove := func(requesterID int) int {
+		req, _ := http.NewRequest("DELETE", "/activities/1/groups/1", nil)
+		authorize(req, requesterID)
+		recorder := httptest.NewRecorder()
+		testActivityRouter.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if status := post(2); status != http.StatusForbidden {
+		t.Errorf("non-creator posted the activity: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := post(1); status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if status := post(1); status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+
+	req, _ := http.NewRequest("GET", "/activities/1/groups", nil)
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+	var response struct {
+		GroupIDs []int `json:"group_ids"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if len(response.GroupIDs) != 1 || response.GroupIDs[0] != 1 {
+		t.Errorf("Expected group_ids [1], got %v", response.GroupIDs)
+	}
+
+	// Plain members cannot take other members' activities out of the group, moderators can
+	testGroupModel.AddUserToGroup(1, 2)
+	if status := remove(2); status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+	testGroupModel.SetMemberRole(1, 2, group.RoleModerator)
+	if status := remove(2); status != http.StatusNoContent {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
 	}
+	if status := remove(1); status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+
+	if _, exists := testActivityModel.GetActivityByID(1); !exists {
+		t.Error("Removing from a group should not delete the activity")
+	}
+}
+
+func TestCreateActivityWithProblemMetadata(t *testing.T) {
+	setupActivityTest()
+
+	recorder := postActivity(1, map[string]interface{}{
+		"title":              "Sum of Round Numbers",
+		"date":               "2025-12-31",
+		"judge":              "Codeforces",
+		"problem_id":         "1352A",
+		"problem_url":        "https://codeforces.com/problemset/problem/1352/A",
+		"rating":             800,
+		"tags":               []string{"Implementation", " math ", "implementation"},
+		"verdict":            "Accepted",
+		"language":           "C++17",
+		"time_spent_minutes": 25,
+	})
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusCreated, recorder.Body.String())
+	}
+
+	var created activity.Activity
+	json.NewDecoder(recorder.Body).Decode(&created)
+
+	stored, _ := testActivityModel.GetActivityByID(created.ID)
+	if stored.Judge == nil || *stored.Judge != activity.JudgeCodeforces || stored.Verdict == nil || *stored.Verdict != activity.VerdictAccepted {
+		t.Errorf("Expected normalized judge and verdict, got %v and %v", stored.Judge, stored.Verdict)
+	}
+	if len(stored.Tags) != 2 || stored.Tags[0] != "implementation" || stored.Tags[1] != "math" {
+		t.Errorf("Expected tags [implementation math], got %v", stored.Tags)
+	}
+	if stored.TimeSpentMinutes == nil || *stored.TimeSpentMinutes != 25 {
+		t.Errorf("Expected 25 minutes spent, got %v", stored.TimeSpentMinutes)
+	}
+}
+
+func TestCreateActivityRejectsInvalidMetadata(t *testing.T) {
+	setupActivityTest()
+
+	for _, fields := range []map[string]interface{}{
+		{"judge": "hackerrank"},
+		{"problem_id": "1352A"},
+		{"judge": "atcoder", "problem_id": "abc 300 a"},
+		{"problem_url": "javascript:alert(1)"},
+		{"rating": -100},
+		{"difficulty": "impossible"},
+		{"tags": []string{"100%"}},
+		{"verdict": "almost"},
+		{"time_spent_minutes": 0},
+		{"time_spent_minutes": 2000},
+	} {
+		payload := map[string]interface{}{"title": "Bad Metadata", "date": "2025-12-31"}
+		for k, v := range fields {
+			payload[k] = v
+		}
+		if status := postActivity(1, payload).Code; status != http.StatusBadRequest {
+			t.Errorf("%v: handler returned wrong status code: got %v want %v", fields, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestUpdateActivityMetadata(t *testing.T) {
+	setupActivityTest()
+
+	update := func(payload map[string]interface{}) int {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("PUT", "/activities/1", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		authorize(req, 1)
+		recorder := httptest.NewRecorder()
+		testActivityRouter.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	// The seeded activity has no judge yet
+	if status := update(map[string]interface{}{"problem_id": "1352A"}); status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := update(map[string]interface{}{"problem_url": "ftp://codeforces.com/1352A"}); status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	status := update(map[string]interface{}{
+		"judge":      "LeetCode",
+		"problem_id": "two-sum",
+		"difficulty": "Easy",
+		"tags":       []string{"Hash Table", "Array"},
+	})
+	if status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	stored, _ := testActivityModel.GetActivityByID(1)
+	if stored.Judge == nil || *stored.Judge != activity.JudgeLeetCode || stored.Difficulty == nil || *stored.Difficulty != activity.DifficultyEasy {
+		t.Errorf("Expected normalized judge and difficulty, got %v and %v", stored.Judge, stored.Difficulty)
+	}
+	if len(stored.Tags) != 2 || stored.Tags[0] != "hash table" || stored.Tags[1] != "array" {
+		t.Errorf("Expected tags [hash table array], got %v", stored.Tags)
+	}
+}
+
+func TestCreateActivityIdentifiesProblemURL(t *testing.T) {
+	setupActivityTest()
+
+	create := func(payload map[string]interface{}) activity.Activity {
+		payload["title"], payload["date"] = "Way Too Long Words", "2025-12-31"
+		recorder := postActivity(1, payload)
+		if status := recorder.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusCreated, recorder.Body.String())
+		}
+		var created activity.Activity
+		json.NewDecoder(recorder.Body).Decode(&created)
+		stored, _ := testActivityModel.GetActivityByID(created.ID)
+		return stored
+	}
+
+	fromProblemset := create(map[string]interface{}{"problem_url": "https://codeforces.com/problemset/problem/71/A"})
+	fromContest := create(map[string]interface{}{"problem_url": "https://mirror.codeforces.com/contest/71/problem/a", "judge": "codeforces"})
+	fromID := create(map[string]interface{}{"judge": "codeforces", "problem_id": "71a", "problem_key": "made-up"})
+
+	if fromProblemset.Judge == nil || *fromProblemset.Judge != activity.JudgeCodeforces || fromProblemset.ProblemID == nil || *fromProblemset.ProblemID != "71A" {
+		t.Errorf("Expected judge and problem id to be filled in from the URL, got %v and %v", fromProblemset.Judge, fromProblemset.ProblemID)
+	}
+	for _, a := range []activity.Activity{fromProblemset, fromContest, fromID} {
+		if a.ProblemKey == nil || *a.ProblemKey != "codeforces:71A" {
+			t.Errorf("Expected problem key codeforces:71A, got %v", a.ProblemKey)
+		}
+		if a.ProblemURL == nil || *a.ProblemURL != "https://codeforces.com/problemset/problem/71/A" {
+			t.Errorf("Expected the canonical problem URL, got %v", a.ProblemURL)
+		}
+	}
+
+	unknown := create(map[string]interface{}{"problem_url": "https://example.com/problems/71A"})
+	if unknown.Judge != nil || unknown.ProblemKey != nil || unknown.ProblemURL == nil || *unknown.ProblemURL != "https://example.com/problems/71A" {
+		t.Errorf("Expected an unrecognised URL to be kept as is, got %v", unknown)
+	}
+	// The judge and problem id still identify the problem
+	mirrored := create(map[string]interface{}{"problem_url": "https://example.com/cf/71A", "judge": "codeforces", "problem_id": "71a"})
+	if mirrored.ProblemKey == nil || *mirrored.ProblemKey != "codeforces:71A" || mirrored.CatalogProblemID == nil || *mirrored.CatalogProblemID != *fromID.CatalogProblemID {
+		t.Errorf("Expected problem key codeforces:71A and the catalog problem of the others, got %v and %v", mirrored.ProblemKey, mirrored.CatalogProblemID)
+	}
+	if mirrored.ProblemURL == nil || *mirrored.ProblemURL != "https://example.com/cf/71A" {
+		t.Errorf("Expected the unrecognised URL to be kept, got %v", mirrored.ProblemURL)
+	}
+
+	for _, fields := range []map[string]interface{}{
+		{"problem_url": "https://codeforces.com/problemset/problem/71/A", "judge": "atcoder"},
+		{"problem_url": "https://codeforces.com/problemset/problem/71/A", "problem_id": "71B"},
+		{"judge": "cses", "problem_id": "two-sum"},
+	} {
+		fields["title"], fields["date"] = "Mismatch", "2025-12-31"
+		if status := postActivity(1, fields).Code; status != http.StatusBadRequest {
+			t.Errorf("%v: handler returned wrong status code: got %v want %v", fields, status, http.StatusBadRequest)
+		}
+	}
 }
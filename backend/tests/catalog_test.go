@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"backend/judge"
+	"backend/models/activity"
+	"backend/models/problem"
+)
+
+const codeforcesProblemsetDump = `{"status": "OK", "result": {"problems": [
+	{"contestId": 1352, "index": "A", "name": "Sum of Round Numbers", "type": "PROGRAMMING", "rating": 800, "tags": ["implementation", "Math", "math", "*special problem"]},
+	{"contestId": 4, "index": "A", "name": "Watermelon", "type": "PROGRAMMING", "rating": 800, "tags": ["brute force"]},
+	{"contestId": 2000, "index": "F1", "name": "Unrated Yet", "type": "PROGRAMMING", "tags": []},
+	{"problemsetName": "acmsguru", "index": "100", "name": "A+B", "type": "PROGRAMMING", "tags": []}
+], "problemStatistics": []}}`
+
+const atcoderMergedProblemsDump = `[
+	{"id": "abc100_a", "contest_id": "abc100", "problem_index": "A", "name": "Happy Birthday!", "title": "A. Happy Birthday!"},
+	{"id": "abc100_d", "contest_id": "abc100", "problem_index": "D", "name": "Patisserie ABC", "title": "D. Patisserie ABC"},
+	{"id": "not a task", "contest_id": "abc100", "problem_index": "Z", "name": "Broken"}
+]`
+
+const atcoderProblemModelsDump = `{"abc100_a": {"difficulty": -1000.5, "is_experimental": false}, "abc100_d": {"difficulty": 1230.7}}`
+
+func TestParseCodeforcesProblemset(t *testing.T) {
+	problems, skipped, err := judge.ParseCodeforcesProblemset(strings.NewReader(codeforcesProblemsetDump))
+	if err != nil {
+		t.Fatalf("Failed to parse dump: %v", err)
+	}
+	if len(problems) != 3 || skipped != 1 {
+		t.Fatalf("Expected 3 problems and 1 skipped, got %d and %d", len(problems), skipped)
+	}
+	first := problems[0]
+	if first.Key != "codeforces:1352A" || first.ExternalID != "1352A" || first.Name != "Sum of Round Numbers" ||
+		first.URL != "https://codeforces.com/problemset/problem/1352/A" || first.Rating == nil || *first.Rating != 800 {
+		t.Errorf("Unexpected problem: %+v", first)
+	}
+	if strings.Join(first.Tags, ",") != "implementation,math,*special problem" {
+		t.Errorf("Expected tags implementation, math and *special problem, got %v", first.Tags)
+	}
+	if problems[2].Rating != nil {
+		t.Errorf("Expected no rating for an unrated problem, got %d", *problems[2].Rating)
+	}
+
+	if _, _, err := judge.ParseCodeforcesProblemset(strings.NewReader(`{"status": "FAILED", "comment": "Call limit exceeded"}`)); err == nil {
+		t.Error("Expected a failed response to be rejected")
+	}
+}
+
+func TestParseAtCoderProblems(t *testing.T) {
+	problems, skipped, err := judge.ParseAtCoderProblems(strings.NewReader(atcoderMergedProblemsDump), strings.NewReader(atcoderProblemModelsDump))
+	if err != nil {
+		t.Fatalf("Failed to parse dump: %v", err)
+	}
+	if len(problems) != 2 || skipped != 1 {
+		t.Fatalf("Expected 2 problems and 1 skipped, got %d and %d", len(problems), skipped)
+	}
+	easy, hard := problems[0], problems[1]
+	if easy.Key != "atcoder:abc100_a" || easy.Name != "Happy Birthday!" || easy.URL != "https://atcoder.jp/contests/abc100/tasks/abc100_a" {
+		t.Errorf("Unexpected problem: %+v", easy)
+	}
+	if easy.Rating == nil || *easy.Rating != judge.AtCoderRating(-1000.5) || hard.Rating == nil || *hard.Rating != 1231 {
+		t.Errorf("Expected ratings %d and 1231, got %v and %v", judge.AtCoderRating(-1000.5), easy.Rating, hard.Rating)
+	}
+
+	withoutModels, _, err := judge.ParseAtCoderProblems(strings.NewReader(atcoderMergedProblemsDump), nil)
+	if err != nil || len(withoutModels) != 2 || withoutModels[0].Rating != nil {
+		t.Errorf("Expected unrated problems without models, got %+v, %v", withoutModels, err)
+	}
+}
+
+func TestImportProblems(t *testing.T) {
+	testProblemModel.Clear()
+	// Logged by a user before the import, so it has no name yet
+	logged, _ := testProblemModel.EnsureProblem(problem.Problem{Judge: activity.JudgeCodeforces, ExternalID: "4A", Key: "codeforces:4A"})
+
+	problems, _, err := judge.ParseCodeforcesProblemset(strings.NewReader(codeforcesProblemsetDump))
+	if err != nil {
+		t.Fatalf("Failed to parse dump: %v", err)
+	}
+	// A repeated problem is only saved once, even across batches
+	problems = append(problems, problems[0])
+
+	result, err := testProblemModel.ImportProblems(problems, 2)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result != (problem.ImportResult{Created: 2, Updated: 1, Skipped: 1}) {
+		t.Errorf("Expected 2 created, 1 updated and 1 skipped, got %+v", result)
+	}
+	if stored, _ := testProblemModel.GetProblemByKey("codeforces:4A"); stored.ID != logged.ID || stored.Name != "Watermelon" || stored.Rating == nil || *stored.Rating != 800 {
+		t.Errorf("Expected the logged problem to be filled in, got %+v", stored)
+	}
+
+	// Importing the same dump again changes nothing
+	result, err = testProblemModel.ImportProblems(problems, 500)
+	if err != nil || result != (problem.ImportResult{Skipped: 4}) {
+		t.Errorf("Expected everything to be skipped, got %+v, %v", result, err)
+	}
+
+	rating := 900
+	problems[1].Rating = &rating
+	problems[2].Rating = nil
+	result, _ = testProblemModel.ImportProblems(problems[:3], 500)
+	if result != (problem.ImportResult{Updated: 1, Skipped: 2}) {
+		t.Errorf("Expected only the rerated problem to be updated, got %+v", result)
+	}
+	if stored, _ := testProblemModel.GetProblemByKey("codeforces:4A"); stored.Rating == nil || *stored.Rating != 900 {
+		t.Errorf("Expected the new rating to replace the old one, got %v", stored.Rating)
+	}
+}
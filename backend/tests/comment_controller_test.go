@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"backend/models/comment"
+	"backend/models/group"
 )
 
 func setupCommentTest() {
@@ -63,7 +64,6 @@ func TestGetCommentsByActivityNotFound(t *testing.T) {
 func TestCreateCommentValid(t *testing.T) {
 	setupCommentTest()
 	validComment := map[string]interface{}{
-		"user_id": 2,
 		"content": "This is a test comment!",
 	}
 
@@ -72,6 +72,7 @@ func TestCreateCommentValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -98,7 +99,6 @@ func TestCreateCommentValid(t *testing.T) {
 func TestCreateCommentInvalidPayload(t *testing.T) {
 	setupCommentTest()
 	invalidComment := map[string]interface{}{
-		"user_id": "2",
 		// Missing content
 	}
 
@@ -107,6 +107,7 @@ func TestCreateCommentInvalidPayload(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -120,7 +121,6 @@ func TestCreateCommentInvalidPayload(t *testing.T) {
 func TestCreateCommentActivityNotFound(t *testing.T) {
 	setupCommentTest()
 	validComment := map[string]interface{}{
-		"user_id": 2,
 		"content": "This is a test comment!",
 	}
 
@@ -129,6 +129,7 @@ func TestCreateCommentActivityNotFound(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -149,16 +150,11 @@ func TestDeleteCommentByAuthor(t *testing.T) {
 		Content:    "Comment to be deleted",
 	})
 
-	deleteRequest := map[string]interface{}{
-		"requester_id": 2, // Same user who created the comment
-	}
-
-	body, _ := json.Marshal(deleteRequest)
-	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2) // Same user who created the comment
 
 	recorder := httptest.NewRecorder()
 	testCommentRouter.ServeHTTP(recorder, req)
@@ -184,16 +180,11 @@ func TestDeleteCommentByActivityCreator(t *testing.T) {
 		Content:    "Comment to be deleted by activity creator",
 	})
 
-	deleteRequest := map[string]interface{}{
-		"requester_id": 1, // Activity creator (different from comment author)
-	}
-
-	body, _ := json.Marshal(deleteRequest)
-	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1) // Activity creator (different from comment author)
 
 	recorder := httptest.NewRecorder()
 	testCommentRouter.ServeHTTP(recorder, req)
@@ -219,16 +210,11 @@ func TestDeleteCommentForbidden(t *testing.T) {
 		Content:    "Comment that should not be deletable by unauthorized user",
 	})
 
-	deleteRequest := map[string]interface{}{
-		"requester_id": 3, // Different user (not comment author or activity creator)
-	}
-
-	body, _ := json.Marshal(deleteRequest)
-	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 3) // Different user (not comment author or activity creator)
 
 	recorder := httptest.NewRecorder()
 	testCommentRouter.ServeHTTP(recorder, req)
@@ -244,19 +230,43 @@ func TestDeleteCommentForbidden(t *testing.T) {
 	}
 }
 
-func TestDeleteCommentNotFound(t *testing.T) {
+func TestDeleteCommentByGroupModerator(t *testing.T) {
 	setupCommentTest()
+	setupGroupTest()
+
+	// User 3 moderates group 1, which activity 1 is posted to
+	testGroupModel.AddActivityToGroup(1, 1)
+	testGroupModel.AddUserToGroup(1, 3)
+	testGroupModel.SetMemberRole(1, 3, group.RoleModerator)
+
+	newComment := testCommentModel.CreateComment(comment.Comment{
+		ActivityID: 1,
+		UserID:     2,
+		Content:    "Off-topic comment",
+	})
 
-	deleteRequest := map[string]interface{}{
-		"requester_id": 1,
+	req, err := http.NewRequest("DELETE", "/comments/"+strconv.Itoa(newComment.ID), nil)
+	if err != nil {
+		t.Fatal(err)
 	}
+	authorize(req, 3)
+
+	recorder := httptest.NewRecorder()
+	testCommentRouter.ServeHTTP(recorder, req)
 
-	body, _ := json.Marshal(deleteRequest)
-	req, err := http.NewRequest("DELETE", "/comments/999", bytes.NewBuffer(body))
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestDeleteCommentNotFound(t *testing.T) {
+	setupCommentTest()
+
+	req, err := http.NewRequest("DELETE", "/comments/999", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1)
 
 	recorder := httptest.NewRecorder()
 	testCommentRouter.ServeHTTP(recorder, req)
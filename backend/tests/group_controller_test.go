@@ -3,11 +3,16 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"backend/models/activity"
+	"backend/models/comment"
 	"backend/models/group"
+	"backend/models/reaction"
 )
 
 func setupGroupTest() {
@@ -29,6 +34,7 @@ func TestCreateGroupValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -50,6 +56,7 @@ func TestCreateGroupInvalid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -62,10 +69,11 @@ func TestCreateGroupInvalid(t *testing.T) {
 
 func TestReadGroup(t *testing.T) {
 	setupGroupTest()
-	req, err := http.NewRequest("GET", "/groups/1?requester_id=1", nil)
+	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -97,6 +105,7 @@ func TestUpdateGroupValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -119,6 +128,7 @@ func TestUpdateGroupInvalid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -131,16 +141,11 @@ func TestUpdateGroupInvalid(t *testing.T) {
 
 func TestDeleteGroupInvalid(t *testing.T) {
 	setupGroupTest()
-	invalidRequest := map[string]interface{}{
-		"creator_id": 2,
-	}
-
-	body, _ := json.Marshal(invalidRequest)
-	req, err := http.NewRequest("DELETE", "/groups/1", bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -152,16 +157,11 @@ func TestDeleteGroupInvalid(t *testing.T) {
 
 func TestDeleteGroupValid(t *testing.T) {
 	setupGroupTest()
-	validRequest := map[string]interface{}{
-		"creator_id": 1,
-	}
-
-	body, _ := json.Marshal(validRequest)
-	req, err := http.NewRequest("DELETE", "/groups/1", bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -183,6 +183,7 @@ func TestAddUserToGroupValid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -204,6 +205,7 @@ func TestAddUserToGroupInvalid(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -222,6 +224,7 @@ func TestAddUserToGroupDuplicate(t *testing.T) {
 	}
 	body, _ := json.Marshal(validRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -232,6 +235,7 @@ func TestAddUserToGroupDuplicate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -250,20 +254,21 @@ func TestRemoveUserFromGroupValid(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now remove the user
 	removeRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 1, // Group creator
+		"user_id": 2,
 	}
 	body, _ = json.Marshal(removeRequest)
 	req, err := http.NewRequest("DELETE", "/groups/1/members", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1) // Group creator
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -282,20 +287,21 @@ func TestRemoveUserFromGroupForbidden(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Try to remove user with unauthorized requester
 	removeRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 3, // Not group creator or the user themselves
+		"user_id": 2,
 	}
 	body, _ = json.Marshal(removeRequest)
 	req, err := http.NewRequest("DELETE", "/groups/1/members", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 3) // Not group creator or the user themselves
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -316,16 +322,18 @@ func TestGetGroupMembers(t *testing.T) {
 		}
 		body, _ := json.Marshal(addRequest)
 		req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+		authorize(req, 1)
 		req.Header.Set("Content-Type", "application/json")
 		recorder := httptest.NewRecorder()
 		testGroupRouter.ServeHTTP(recorder, req)
 	}
 
 	// Get group members
-	req, err := http.NewRequest("GET", "/groups/1/members?requester_id=1", nil)
+	req, err := http.NewRequest("GET", "/groups/1/members", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -346,16 +354,11 @@ func TestGetGroupMembers(t *testing.T) {
 
 func TestCreateInviteLinkValid(t *testing.T) {
 	setupGroupTest()
-	validRequest := map[string]interface{}{
-		"creator_id": 1, // Group creator
-	}
-
-	body, _ := json.Marshal(validRequest)
-	req, err := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1) // Group creator
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -382,16 +385,11 @@ func TestCreateInviteLinkValid(t *testing.T) {
 
 func TestCreateInviteLinkForbidden(t *testing.T) {
 	setupGroupTest()
-	invalidRequest := map[string]interface{}{
-		"creator_id": 2, // Not group creator
-	}
-
-	body, _ := json.Marshal(invalidRequest)
-	req, err := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2) // Not group creator
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -405,12 +403,8 @@ func TestJoinGroupByInviteValid(t *testing.T) {
 	setupGroupTest()
 
 	// First create an invite
-	createRequest := map[string]interface{}{
-		"creator_id": 1,
-	}
-	body, _ := json.Marshal(createRequest)
-	req, _ := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 1)
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
@@ -419,14 +413,14 @@ func TestJoinGroupByInviteValid(t *testing.T) {
 
 	// Now use the invite to join
 	joinRequest := map[string]interface{}{
-		"user_id":  2,
 		"nickname": "NewMember",
 	}
-	body, _ = json.Marshal(joinRequest)
+	body, _ := json.Marshal(joinRequest)
 	req, err := http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -437,10 +431,11 @@ func TestJoinGroupByInviteValid(t *testing.T) {
 	}
 
 	// Check if nickname was set
-	req, err = http.NewRequest("GET", "/groups/1/members?requester_id=1", nil)
+	req, err = http.NewRequest("GET", "/groups/1/members", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
@@ -475,15 +470,11 @@ func TestJoinGroupByInviteValid(t *testing.T) {
 func TestJoinGroupByInviteInvalidCode(t *testing.T) {
 	setupGroupTest()
 
-	joinRequest := map[string]interface{}{
-		"user_id": 2,
-	}
-	body, _ := json.Marshal(joinRequest)
-	req, err := http.NewRequest("POST", "/invites/INVALID123/join", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/invites/INVALID123/join", http.NoBody)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -497,12 +488,8 @@ func TestJoinGroupByInviteDuplicateUser(t *testing.T) {
 	setupGroupTest()
 
 	// First create an invite
-	createRequest := map[string]interface{}{
-		"creator_id": 1,
-	}
-	body, _ := json.Marshal(createRequest)
-	req, _ := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 1)
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
@@ -510,22 +497,17 @@ func TestJoinGroupByInviteDuplicateUser(t *testing.T) {
 	json.NewDecoder(recorder.Body).Decode(&invite)
 
 	// Join first time
-	joinRequest := map[string]interface{}{
-		"user_id": 2,
-	}
-	body, _ = json.Marshal(joinRequest)
-	req, _ = http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ = http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", http.NoBody)
+	authorize(req, 2)
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Try to join again with same user
-	body, _ = json.Marshal(joinRequest)
-	req, err := http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", http.NoBody)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 2)
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -540,12 +522,8 @@ func TestGetGroupInvites(t *testing.T) {
 
 	// Create a couple of invites
 	for i := 0; i < 2; i++ {
-		createRequest := map[string]interface{}{
-			"creator_id": 1,
-		}
-		body, _ := json.Marshal(createRequest)
-		req, _ := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
+		req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+		authorize(req, 1)
 		recorder := httptest.NewRecorder()
 		testGroupRouter.ServeHTTP(recorder, req)
 	}
@@ -555,6 +533,7 @@ func TestGetGroupInvites(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -577,12 +556,8 @@ func TestDeactivateInviteValid(t *testing.T) {
 	setupGroupTest()
 
 	// First create an invite
-	createRequest := map[string]interface{}{
-		"creator_id": 1,
-	}
-	body, _ := json.Marshal(createRequest)
-	req, _ := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 1)
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
@@ -590,15 +565,11 @@ func TestDeactivateInviteValid(t *testing.T) {
 	json.NewDecoder(recorder.Body).Decode(&invite)
 
 	// Deactivate the invite
-	deactivateRequest := map[string]interface{}{
-		"requester_id": 1, // Group creator
-	}
-	body, _ = json.Marshal(deactivateRequest)
-	req, err := http.NewRequest("DELETE", "/invites/"+invite.InviteCode+"/deactivate", bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/invites/"+invite.InviteCode+"/deactivate", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1) // Group creator
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -612,12 +583,8 @@ func TestDeactivateInviteForbidden(t *testing.T) {
 	setupGroupTest()
 
 	// First create an invite
-	createRequest := map[string]interface{}{
-		"creator_id": 1,
-	}
-	body, _ := json.Marshal(createRequest)
-	req, _ := http.NewRequest("POST", "/groups/1/invites", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 1)
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
@@ -625,15 +592,11 @@ func TestDeactivateInviteForbidden(t *testing.T) {
 	json.NewDecoder(recorder.Body).Decode(&invite)
 
 	// Try to deactivate with unauthorized user
-	deactivateRequest := map[string]interface{}{
-		"requester_id": 3, // Not group creator or invite creator
-	}
-	body, _ = json.Marshal(deactivateRequest)
-	req, err := http.NewRequest("DELETE", "/invites/"+invite.InviteCode+"/deactivate", bytes.NewBuffer(body))
+	req, err := http.NewRequest("DELETE", "/invites/"+invite.InviteCode+"/deactivate", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 3) // Not group creator or invite creator
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -651,21 +614,22 @@ func TestSetUserNicknameValid(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now set the nickname
 	nicknameRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 2, // User themselves
-		"nickname":     "CoolNickname",
+		"user_id":  2,
+		"nickname": "CoolNickname",
 	}
 	body, _ = json.Marshal(nicknameRequest)
 	req, err := http.NewRequest("PUT", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2) // User themselves
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -693,21 +657,22 @@ func TestSetUserNicknameByGroupCreator(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now set the nickname as group creator
 	nicknameRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 1, // Group creator
-		"nickname":     "AssignedByOwner",
+		"user_id":  2,
+		"nickname": "AssignedByOwner",
 	}
 	body, _ = json.Marshal(nicknameRequest)
 	req, err := http.NewRequest("PUT", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1) // Group creator
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -726,21 +691,22 @@ func TestSetUserNicknameForbidden(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Try to set nickname as unauthorized user
 	nicknameRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 3, // Neither the user nor group creator
-		"nickname":     "Unauthorized",
+		"user_id":  2,
+		"nickname": "Unauthorized",
 	}
 	body, _ = json.Marshal(nicknameRequest)
 	req, err := http.NewRequest("PUT", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 3) // Neither the user nor group creator
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -756,15 +722,15 @@ func TestSetUserNicknameUserNotInGroup(t *testing.T) {
 
 	// Try to set nickname for a user not in the group
 	nicknameRequest := map[string]interface{}{
-		"user_id":      3, // User not in group
-		"requester_id": 1, // Group creator
-		"nickname":     "NotInGroup",
+		"user_id":  3, // User not in group
+		"nickname": "NotInGroup",
 	}
 	body, _ := json.Marshal(nicknameRequest)
 	req, err := http.NewRequest("PUT", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 1) // Group creator
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -783,6 +749,7 @@ func TestSetUserNicknameTooLong(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -794,15 +761,15 @@ func TestSetUserNicknameTooLong(t *testing.T) {
 	}
 
 	nicknameRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 2,
-		"nickname":     string(longNickname),
+		"user_id":  2,
+		"nickname": string(longNickname),
 	}
 	body, _ = json.Marshal(nicknameRequest)
 	req, err := http.NewRequest("PUT", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -822,20 +789,21 @@ func TestDeleteUserNicknameValid(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now delete the nickname
 	deleteRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 2, // User themselves
+		"user_id": 2,
 	}
 	body, _ = json.Marshal(deleteRequest)
 	req, err := http.NewRequest("DELETE", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2) // User themselves
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -864,20 +832,21 @@ func TestDeleteUserNicknameForbidden(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Try to delete nickname as unauthorized user
 	deleteRequest := map[string]interface{}{
-		"user_id":      2,
-		"requester_id": 3, // Neither the user nor group creator
+		"user_id": 2,
 	}
 	body, _ = json.Marshal(deleteRequest)
 	req, err := http.NewRequest("DELETE", "/groups/1/members/nickname", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 3) // Neither the user nor group creator
 	req.Header.Set("Content-Type", "application/json")
 
 	recorder = httptest.NewRecorder()
@@ -891,10 +860,11 @@ func TestDeleteUserNicknameForbidden(t *testing.T) {
 func TestGetGroupForbidden(t *testing.T) {
 	setupGroupTest()
 
-	req, err := http.NewRequest("GET", "/groups/1?requester_id=999", nil)
+	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 999)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -913,15 +883,17 @@ func TestGetGroupAsMember(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now try to get group as member
-	req, err := http.NewRequest("GET", "/groups/1?requester_id=2", nil)
+	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -934,10 +906,11 @@ func TestGetGroupAsMember(t *testing.T) {
 func TestGetGroupMembersForbidden(t *testing.T) {
 	setupGroupTest()
 
-	req, err := http.NewRequest("GET", "/groups/1/members?requester_id=999", nil)
+	req, err := http.NewRequest("GET", "/groups/1/members", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 999)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -956,15 +929,17 @@ func TestGetGroupActivitiesAsMember(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Get group activities as member
-	req, err := http.NewRequest("GET", "/groups/1/activities?requester_id=2", nil)
+	req, err := http.NewRequest("GET", "/groups/1/activities", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -992,10 +967,11 @@ func TestGetGroupActivitiesAsMember(t *testing.T) {
 func TestGetGroupActivitiesForbidden(t *testing.T) {
 	setupGroupTest()
 
-	req, err := http.NewRequest("GET", "/groups/1/activities?requester_id=999", nil)
+	req, err := http.NewRequest("GET", "/groups/1/activities", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 999)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -1009,10 +985,11 @@ func TestGetGroupRequiresAuthorization(t *testing.T) {
 	setupGroupTest()
 
 	// Try to get group without being a member
-	req, err := http.NewRequest("GET", "/groups/1?requester_id=999", nil)
+	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 999)
 
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -1031,15 +1008,17 @@ func TestGetGroupAllowsGroupMembers(t *testing.T) {
 	}
 	body, _ := json.Marshal(addRequest)
 	req, _ := http.NewRequest("POST", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 1)
 	req.Header.Set("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
 	// Now try to get group as a member
-	req, err := http.NewRequest("GET", "/groups/1?requester_id=2", nil)
+	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	authorize(req, 2)
 
 	recorder = httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
@@ -1049,10 +1028,10 @@ func TestGetGroupAllowsGroupMembers(t *testing.T) {
 	}
 }
 
-func TestGetGroupMissingRequesterID(t *testing.T) {
+func TestGetGroupMissingToken(t *testing.T) {
 	setupGroupTest()
 
-	// Try to get group without requester_id
+	// Try to get group without a bearer token
 	req, err := http.NewRequest("GET", "/groups/1", nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1061,15 +1040,15 @@ func TestGetGroupMissingRequesterID(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
-	if status := recorder.Code; status != http.StatusBadRequest {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
 	}
 }
 
-func TestGetGroupMembersMissingRequesterID(t *testing.T) {
+func TestGetGroupMembersMissingToken(t *testing.T) {
 	setupGroupTest()
 
-	// Try to get group members without requester_id
+	// Try to get group members without a bearer token
 	req, err := http.NewRequest("GET", "/groups/1/members", nil)
 	if err != nil {
 		t.Fatal(err)
@@ -1078,7 +1057,813 @@ func TestGetGroupMembersMissingRequesterID(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	testGroupRouter.ServeHTTP(recorder, req)
 
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestDeleteGroupRejectsInvalidToken(t *testing.T) {
+	setupGroupTest()
+
+	req, err := http.NewRequest("DELETE", "/groups/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer dummy-jwt-token-1")
+
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	if _, exists := testGroupModel.GetGroupByID(1); !exists {
+		t.Error("Group should not have been deleted")
+	}
+}
+
+func TestCreateGroupAddsCreatorAsMember(t *testing.T) {
+	setupGroupTest()
+	validGroup := map[string]interface{}{
+		"name":       "Creator Group",
+		"end_date":   "2025-12-31",
+		"creator_id": 999, // Ignored, the creator comes from the token
+	}
+
+	body, _ := json.Marshal(validGroup)
+	req, err := http.NewRequest("POST", "/groups", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorize(req, 2)
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created group.Group
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+
+	if created.CreatorID != 2 {
+		t.Errorf("Expected CreatorID to be 2, got %v", created.CreatorID)
+	}
+	if !testGroupModel.IsUserInGroup(created.ID, 2) {
+		t.Error("Creator should be a member of the new group")
+	}
+}
+
+func TestJoinGroupByInviteRequiresVerifiedEmail(t *testing.T) {
+	setupGroupTest()
+	setupUserTest()
+
+	// Creator turns the requirement on
+	body, _ := json.Marshal(map[string]interface{}{"require_verified_email": true})
+	req, _ := http.NewRequest("PUT", "/groups/1", bytes.NewBuffer(body))
+	authorize(req, 1)
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	req, _ = http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 1)
+	recorder = httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+
+	var invite group.GroupInvite
+	json.NewDecoder(recorder.Body).Decode(&invite)
+
+	req, _ = http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", http.NoBody)
+	authorize(req, 3)
+	recorder = httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// Same invite works once the address is verified
+	testUserModel.MarkEmailVerified(3)
+	req, _ = http.NewRequest("POST", "/invites/"+invite.InviteCode+"/join", http.NoBody)
+	authorize(req, 3)
+	recorder = httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func setMemberRole(requesterID, userID int, role string) int {
+	body, _ := json.Marshal(map[string]interface{}{"user_id": userID, "role": role})
+	req, _ := http.NewRequest("PUT", "/groups/1/members/role", bytes.NewBuffer(body))
+	authorize(req, requesterID)
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestCreatorIsOwner(t *testing.T) {
+	setupGroupTest()
+
+	member, exists := testGroupModel.GetMember(1, 1)
+	if !exists || member.Role != group.RoleOwner {
+		t.Errorf("Expected creator to be owner, got %q", member.Role)
+	}
+
+	testGroupModel.AddUserToGroup(1, 2)
+	member, _ = testGroupModel.GetMember(1, 2)
+	if member.Role != group.RoleMember {
+		t.Errorf("Expected new member to have role member, got %q", member.Role)
+	}
+}
+
+func TestAdminCanManageInvitesAndMembers(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	testGroupModel.AddUserToGroup(1, 3)
+
+	if status := setMemberRole(1, 2, group.RoleAdmin); status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 2)
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Errorf("admin could not create invite: got %v want %v", status, http.StatusCreated)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"user_id": 3})
+	req, _ = http.NewRequest("DELETE", "/groups/1/members", bytes.NewBuffer(body))
+	authorize(req, 2)
+	recorder = httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("admin could not remove member: got %v want %v", status, http.StatusOK)
+	}
+
+	// Admins still cannot touch the group itself
+	req, _ = http.NewRequest("DELETE", "/groups/1", nil)
+	authorize(req, 2)
+	recorder = httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("admin deleted the group: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestMemberCannotManageRolesOrInvites(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	testGroupModel.AddUserToGroup(1, 3)
+
+	if status := setMemberRole(2, 3, group.RoleModerator); status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// Moderators can delete comments but not create invites
+	setMemberRole(1, 2, group.RoleModerator)
+	req, _ := http.NewRequest("POST", "/groups/1/invites", http.NoBody)
+	authorize(req, 2)
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestAdminCannotAppointAdminsOrDemoteOwner(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	testGroupModel.AddUserToGroup(1, 3)
+	testGroupModel.AddUserToGroup(1, 4)
+	setMemberRole(1, 2, group.RoleAdmin)
+	setMemberRole(1, 4, group.RoleAdmin)
+
+	if status := setMemberRole(2, 3, group.RoleAdmin); status != http.StatusForbidden {
+		t.Errorf("admin appointed an admin: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := setMemberRole(2, 4, group.RoleMember); status != http.StatusForbidden {
+		t.Errorf("admin demoted another admin: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := setMemberRole(2, 1, group.RoleMember); status != http.StatusForbidden {
+		t.Errorf("admin demoted the owner: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := setMemberRole(2, 3, group.RoleModerator); status != http.StatusOK {
+		t.Errorf("admin could not appoint a moderator: got %v want %v", status, http.StatusOK)
+	}
+	if status := setMemberRole(1, 3, group.RoleOwner); status != http.StatusBadRequest {
+		t.Errorf("owner role was assignable: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestOwnerCannotBeRemoved(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	setMemberRole(1, 2, group.RoleAdmin)
+
+	for _, requesterID := range []int{1, 2} {
+		body, _ := json.Marshal(map[string]interface{}{"user_id": 1})
+		req, _ := http.NewRequest("DELETE", "/groups/1/members", bytes.NewBuffer(body))
+		authorize(req, requesterID)
+		recorder := httptest.NewRecorder()
+		testGroupRouter.ServeHTTP(recorder, req)
+		if status := recorder.Code; status != http.StatusForbidden {
+			t.Errorf("requester %d removed the owner: got %v want %v", requesterID, status, http.StatusForbidden)
+		}
+	}
+
+	if !testGroupModel.IsUserInGroup(1, 1) {
+		t.Error("Owner should still be a member")
+	}
+}
+
+func groupRequest(method, path string, requesterID int, payload interface{}) *httptest.ResponseRecorder {
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		encoded, _ := json.Marshal(payload)
+		body = bytes.NewBuffer(encoded)
+	}
+	req, _ := http.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	authorize(req, requesterID)
+	recorder := httptest.NewRecorder()
+	testGroupRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestTransferOwnership(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+
+	recorder := groupRequest("POST", "/groups/1/transfer", 1, map[string]interface{}{"user_id": 2})
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	// Only the nominee can accept
+	if status := groupRequest("POST", "/groups/1/transfer/accept", 1, nil).Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	recorder = groupRequest("POST", "/groups/1/transfer/accept", 2, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var updated group.Group
+	if err := json.NewDecoder(recorder.Body).Decode(&updated); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	if updated.CreatorID != 2 {
+		t.Errorf("Expected CreatorID to be 2, got %v", updated.CreatorID)
+	}
+
+	newOwner, _ := testGroupModel.GetMember(1, 2)
+	previousOwner, _ := testGroupModel.GetMember(1, 1)
+	if newOwner.Role != group.RoleOwner || previousOwner.Role != group.RoleAdmin {
+		t.Errorf("Expected roles owner/admin, got %q/%q", newOwner.Role, previousOwner.Role)
+	}
+
+	// Owner-only actions follow the new owner
+	if status := groupRequest("PUT", "/groups/1", 1, map[string]interface{}{"description": "Old owner"}).Code; status != http.StatusForbidden {
+		t.Errorf("previous owner updated the group: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := groupRequest("PUT", "/groups/1", 2, map[string]interface{}{"description": "New owner"}).Code; status != http.StatusOK {
+		t.Errorf("new owner could not update the group: got %v want %v", status, http.StatusOK)
+	}
+
+	// The previous owner is free to leave now
+	if status := groupRequest("DELETE", "/groups/1/members", 1, map[string]interface{}{"user_id": 1}).Code; status != http.StatusOK {
+		t.Errorf("previous owner could not leave: got %v want %v", status, http.StatusOK)
+	}
+
+	recorder = groupRequest("GET", "/groups/1/transfers", 2, nil)
+	var history struct {
+		Transfers []group.OwnershipTransfer `json:"transfers"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&history)
+	if len(history.Transfers) != 1 || history.Transfers[0].Status != group.TransferAccepted ||
+		history.Transfers[0].FromUserID != 1 || history.Transfers[0].RespondedAt == nil {
+		t.Errorf("Expected one accepted transfer from user 1 in the history, got %+v", history.Transfers)
+	}
+}
+
+func TestTransferOwnershipOnlyByOwner(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	testGroupModel.AddUserToGroup(1, 3)
+	testGroupModel.SetMemberRole(1, 2, group.RoleAdmin)
+
+	if status := groupRequest("POST", "/groups/1/transfer", 2, map[string]interface{}{"user_id": 3}).Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := groupRequest("POST", "/groups/1/transfer", 1, map[string]interface{}{"user_id": 4}).Code; status != http.StatusNotFound {
+		t.Errorf("non-member was nominated: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestDeclineOwnershipTransfer(t *testing.T) {
+	setupGroupTest()
+	testGroupModel.AddUserToGroup(1, 2)
+	testGroupModel.AddUserToGroup(1, 3)
+
+	groupRequest("POST", "/groups/1/transfer", 1, map[string]interface{}{"user_id": 2})
+
+	// A bystander cannot cancel it
+	if status := groupRequest("DELETE", "/groups/1/transfer", 3, nil).Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	recorder := groupRequest("DELETE", "/groups/1/transfer", 2, nil)
+	var transfer group.OwnershipTransfer
+	json.NewDecoder(recorder.Body).Decode(&transfer)
+	if recorder.Code != http.StatusOK || transfer.Status != group.TransferDeclined {
+		t.Errorf("Expected declined transfer, got %v %q", recorder.Code, transfer.Status)
+	}
+
+	if status := groupRequest("POST", "/groups/1/transfer/accept", 2, nil).Code; status != http.StatusNotFound {
+		t.Errorf("declined transfer was accepted: got %v want %v", status, http.StatusNotFound)
+	}
+
+	g, _ := testGroupModel.GetGroupByID(1)
+	if g.CreatorID != 1 {
+		t.Errorf("Expected CreatorID to stay 1, got %v", g.CreatorID)
+	}
+}
+
+func TestGetGroupActivitiesListsPostedActivities(t *testing.T) {
+	setupCommentTest()
+	setupGroupTest()
+	testGroupModel.UpdateGroup(1, map[string]interface{}{
+		"start_date": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"end_date":   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	// Seeded activity 1 is dated 2025-12-31 and has one comment
+	testGroupModel.AddActivityToGroup(1, 1)
+	outside := testActivityModel.CreateActivity(activity.Activity{
+		Title:     "Too Late",
+		CreatorID: 1,
+		Date:      time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	testGroupModel.AddActivityToGroup(1, outside.ID)
+	testActivityModel.CreateActivity(activity.Activity{
+		Title:     "Not Posted",
+		CreatorID: 1,
+		Date:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	recorder := groupRequest("GET", "/groups/1/activities", 1, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response struct {
+		Activities    []group.PostedActivity `json:"activities"`
+		ActivityCount int                    `json:"activity_count"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+
+	if response.ActivityCount != 1 || len(response.Activities) != 1 {
+		t.Fatalf("Expected only the posted activity inside the group dates, got %+v", response.Activities)
+	}
+	posted := response.Activities[0]
+	if posted.ID != 1 || posted.Title != "New Activity" {
+		t.Errorf("Expected activity 1, got %d %q", posted.ID, posted.Title)
+	}
+	if posted.Author.ID != 1 || posted.Author.Name == "" {
+		t.Errorf("Expected author 1 with a name, got %+v", posted.Author)
+	}
+	if posted.CommentCount != 1 {
+		t.Errorf("Expected 1 comment, got %d", posted.CommentCount)
+	}
+}
+
+func TestGroupLeaderboard(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+	testGroupModel.UpdateGroup(1, map[string]interface{}{
+		"start_date": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"end_date":   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+	for _, userID := range []int{2, 3, 4, 5} {
+		ensureUser(userID)
+		testGroupModel.AddUserToGroup(1, userID)
+	}
+	nickname := "Ace"
+	testGroupModel.SetUserNickname(1, 2, &nickname)
+
+	rating := 1500
+	post := func(userID int, date time.Time, rating *int) {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Solve", CreatorID: userID, Date: date, Rating: rating})
+		testGroupModel.AddActivityToGroup(1, a.ID)
+	}
+	// User 1: two hard problems on one day
+	post(1, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), &rating)
+	post(1, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), &rating)
+	// User 2: three unrated problems on three days, plus one outside the window
+	post(2, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), nil)
+	post(2, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), nil)
+	post(2, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), nil)
+	post(2, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), nil)
+	// User 3 only fails hard problems, which score nothing
+	hardest, wrongAnswer := 3000, activity.VerdictWrongAnswer
+	for i := 0; i < 10; i++ {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Attempt", CreatorID: 3, Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Rating: &hardest, Verdict: &wrongAnswer})
+		testGroupModel.AddActivityToGroup(1, a.ID)
+	}
+	// User 4 leaves after posting
+	post(4, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), nil)
+	testGroupModel.RemoveUserFromGroup(1, 4)
+
+	leaderboard := func(mode string) []group.LeaderboardEntry {
+		recorder := groupRequest("GET", "/groups/1/leaderboard?mode="+mode, 3, nil)
+		if status := recorder.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		var response struct {
+			Entries []group.LeaderboardEntry `json:"entries"`
+		}
+		json.NewDecoder(recorder.Body).Decode(&response)
+		return response.Entries
+	}
+
+	type placing struct{ rank, userID, score int }
+	check := func(mode string, want []placing) {
+		entries := leaderboard(mode)
+		if len(entries) != len(want) {
+			t.Fatalf("%s: expected %d entries, got %+v", mode, len(want), entries)
+		}
+		for i, w := range want {
+			got := entries[i]
+			if got.Rank != w.rank || got.UserID != w.userID || got.Score != w.score {
+				t.Errorf("%s: entry %d = rank %d user %d score %d, want rank %d user %d score %d",
+					mode, i, got.Rank, got.UserID, got.Score, w.rank, w.userID, w.score)
+			}
+		}
+	}
+
+	// Users 3 and 5 tie at zero and are ordered by name
+	check(group.ScoringActivities, []placing{{1, 2, 3}, {2, 1, 2}, {3, 3, 0}, {3, 5, 0}})
+	check(group.ScoringActiveDays, []placing{{1, 2, 3}, {2, 1, 1}, {3, 3, 0}, {3, 5, 0}})
+	check(group.ScoringDifficulty, []placing{{1, 1, 30}, {2, 2, 24}, {3, 3, 0}, {3, 5, 0}})
+
+	if entries := leaderboard(group.ScoringActivities); entries[0].DisplayName != "Ace" {
+		t.Errorf("Expected the nickname to be shown, got %q", entries[0].DisplayName)
+	}
+}
+
+func TestGroupLeaderboardScoresLeetCodeDifficulty(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+	testGroupModel.UpdateGroup(1, map[string]interface{}{
+		"start_date": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"end_date":   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+	ensureUser(2)
+	testGroupModel.AddUserToGroup(1, 2)
+
+	judge := activity.JudgeLeetCode
+	hard, easy := activity.DifficultyHard, activity.DifficultyEasy
+	rating := 900
+	post := func(userID int, rating *int, difficulty *string) {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Solve", CreatorID: userID, Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Judge: &judge, Rating: rating, Difficulty: difficulty})
+		testGroupModel.AddActivityToGroup(1, a.ID)
+	}
+	// LeetCode imports only have a difficulty; a rating takes precedence over it
+	post(1, nil, &hard)
+	post(2, nil, &easy)
+	post(2, &rating, &hard)
+
+	recorder := groupRequest("GET", "/groups/1/leaderboard?mode="+group.ScoringDifficulty, 1, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response struct {
+		Entries []group.LeaderboardEntry `json:"entries"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if len(response.Entries) != 2 || response.Entries[0].UserID != 1 || response.Entries[0].Score != 20 ||
+		response.Entries[1].UserID != 2 || response.Entries[1].Score != 19 {
+		t.Errorf("Expected user 1 with 20 points, then user 2 with 19, got %+v", response.Entries)
+	}
+}
+
+func TestGroupLeaderboardUsesGroupScoringMode(t *testing.T) {
+	setupGroupTest()
+
+	if status := groupRequest("PUT", "/groups/1", 1, map[string]interface{}{"scoring_mode": "fastest"}).Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := groupRequest("PUT", "/groups/1", 1, map[string]interface{}{"scoring_mode": group.ScoringActiveDays}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	recorder := groupRequest("GET", "/groups/1/leaderboard", 1, nil)
+	var response struct {
+		ScoringMode string `json:"scoring_mode"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response.ScoringMode != group.ScoringActiveDays {
+		t.Errorf("Expected scoring mode %q, got %q", group.ScoringActiveDays, response.ScoringMode)
+	}
+
+	if status := groupRequest("GET", "/groups/1/leaderboard", 999, nil).Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestGroupProgress(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+	ensureUser(2)
+	testGroupModel.AddUserToGroup(1, 2)
+
+	// A four-week window on its tenth day
+	today := activity.Today(time.Now(), time.UTC)
+	testGroupModel.UpdateGroup(1, map[string]interface{}{
+		"start_date": today.AddDate(0, 0, -9),
+		"end_date":   today.AddDate(0, 0, 18),
+	})
+
+	goals := map[string]interface{}{
+		"goal_total_activities":      40,
+		"goal_activities_per_member": 10,
+		"goal_active_days_per_week":  2,
+	}
+	if status := groupRequest("PUT", "/groups/1", 2, goals).Code; status != http.StatusForbidden {
+		t.Errorf("member set the group's goals: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := groupRequest("PUT", "/groups/1", 1, goals).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	post := func(userID int, date time.Time) {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Solve", CreatorID: userID, Date: date})
+		testGroupModel.AddActivityToGroup(1, a.ID)
+	}
+	// User 1 solves one problem on each of five days, user 2 ten problems on a single day
+	for d := 9; d >= 5; d-- {
+		post(1, today.AddDate(0, 0, -d))
+	}
+	// Failed attempts count towards no goal
+	wrongAnswer := activity.VerdictWrongAnswer
+	for d := 4; d >= 1; d-- {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Attempt", CreatorID: 1, Date: today.AddDate(0, 0, -d), Verdict: &wrongAnswer})
+		testGroupModel.AddActivityToGroup(1, a.ID)
+	}
+	for i := 0; i < 10; i++ {
+		post(2, today.AddDate(0, 0, -2))
+	}
+
+	recorder := groupRequest("GET", "/groups/1/progress", 2, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response struct {
+		Progress group.Progress `json:"progress"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body:", err)
+	}
+	progress := response.Progress
+
+	if progress.TotalDays != 28 || progress.ElapsedDays != 10 || progress.TimePercent != 35.7 {
+		t.Errorf("Expected day 10 of 28 (35.7%%), got day %d of %d (%v%%)", progress.ElapsedDays, progress.TotalDays, progress.TimePercent)
+	}
+	if progress.Percent != 43.8 {
+		t.Errorf("Expected overall progress 43.8%%, got %v%%", progress.Percent)
+	}
+
+	type goalWant struct {
+		done, required int
+		percent        float64
+		finishIn       int
+		onTrack        bool
+	}
+	want := map[string]goalWant{
+		// 15 of 40 at 1.5 a day
+		group.GoalTotalActivities: {15, 40, 37.5, 17, true},
+		// User 2's extra problems do not count towards user 1's share
+		group.GoalActivitiesPerMember: {15, 20, 75, 4, true},
+		// 2 active days each in each of four weeks; user 1's five days in the first week count as 2
+		group.GoalActiveDaysPerWeek: {3, 16, 18.8, 44, false},
+	}
+	if len(progress.Goals) != len(want) {
+		t.Fatalf("Expected %d goals, got %+v", len(want), progress.Goals)
+	}
+	for _, goal := range progress.Goals {
+		w := want[goal.Goal]
+		if goal.Done != w.done || goal.Required != w.required || goal.Percent != w.percent {
+			t.Errorf("%s: got %d of %d (%v%%), want %d of %d (%v%%)", goal.Goal, goal.Done, goal.Required, goal.Percent, w.done, w.required, w.percent)
+		}
+		if goal.ProjectedFinish == nil || !goal.ProjectedFinish.Equal(today.AddDate(0, 0, w.finishIn)) || goal.OnTrack != w.onTrack {
+			t.Errorf("%s: expected to finish in %d days (on track %v), got %v (on track %v)", goal.Goal, w.finishIn, w.onTrack, goal.ProjectedFinish, goal.OnTrack)
+		}
+	}
+
+	if len(progress.Members) != 2 || progress.Members[0].UserID != 2 || progress.Members[1].UserID != 1 {
+		t.Fatalf("Expected members ordered by completion (2, 1), got %+v", progress.Members)
+	}
+	if m := progress.Members[0]; m.Percent != 54.2 || m.Goals[group.GoalActivitiesPerMember] != 100 || m.Goals[group.GoalActiveDaysPerWeek] != 12.5 {
+		t.Errorf("Unexpected progress for user 2: %+v", m)
+	}
+	if m := progress.Members[1]; m.Percent != 33.3 || m.Goals[group.GoalTotalActivities] != 25 || m.Goals[group.GoalActiveDaysPerWeek] != 25 {
+		t.Errorf("Unexpected progress for user 1: %+v", m)
+	}
+
+	if status := groupRequest("GET", "/groups/1/progress", 3, nil).Code; status != http.StatusForbidden {
+		t.Errorf("non-member viewed the progress: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestGroupProgressCountsActiveDaysWeekly(t *testing.T) {
+	// A seven-week group on its last day
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 48)
+	target := 5
+	g := group.Group{StartDate: start, EndDate: end, GoalActiveDaysPerWeek: &target}
+	members := []group.MemberProfile{{GroupMember: group.GroupMember{UserID: 1}, Name: "Binger"}}
+
+	// Active every day for five weeks, then not at all
+	var activities []group.PostedActivity
+	for d := 0; d < 35; d++ {
+		activities = append(activities, group.PostedActivity{Activity: activity.Activity{CreatorID: 1, Date: start.AddDate(0, 0, d)}})
+	}
+
+	progress := group.BuildProgress(g, members, activities, end)
+	goal := progress.Goals[0]
+	if goal.Done != 25 || goal.Required != 35 || goal.Percent != 71.4 || goal.OnTrack {
+		t.Errorf("Expected 25 of 35 active days (71.4%%) and off track, got %+v", goal)
+	}
+	if m := progress.Members[0]; m.ActiveDays != 35 || m.Goals[group.GoalActiveDaysPerWeek] != 71.4 {
+		t.Errorf("Expected 35 active days counting as 71.4%%, got %+v", m)
+	}
+
+	// A short last week asks for no more days than it has
+	g.EndDate = start.AddDate(0, 0, 8)
+	if goal := group.BuildProgress(g, members, activities, g.EndDate).Goals[0]; goal.Done != 7 || goal.Required != 7 || !goal.OnTrack {
+		t.Errorf("Expected 7 of 7 active days over a week and two days, got %+v", goal)
+	}
+}
+
+func TestGroupProgressOrdersTiesByUserID(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	target := 5
+	g := group.Group{StartDate: start, EndDate: start.AddDate(0, 0, 6), GoalActivitiesPerMember: &target}
+
+	// Members alike in completion and name are ordered by user id, whatever order they come in
+	twins := []group.MemberProfile{{GroupMember: group.GroupMember{UserID: 3}, Name: "Twin"}, {GroupMember: group.GroupMember{UserID: 2}, Name: "twin"}}
+	if ordered := group.BuildProgress(g, twins, nil, start).Members; ordered[0].UserID != 2 || ordered[1].UserID != 3 {
+		t.Errorf("Expected users 2 and 3 in order, got %+v", ordered)
+	}
+}
+
+func TestGroupProgressUsesRequesterTimezone(t *testing.T) {
+	setupGroupTest()
+	for _, timezone := range []string{"Pacific/Kiritimati", "Pacific/Pago_Pago"} {
+		testUserModel.SetTimezone(1, timezone)
+		recorder := groupRequest("GET", "/groups/1/progress", 1, nil)
+		if status := recorder.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		var response struct {
+			Today time.Time `json:"today"`
+		}
+		json.NewDecoder(recorder.Body).Decode(&response)
+		loc, _ := time.LoadLocation(timezone)
+		if want := activity.Today(time.Now(), loc); !response.Today.Equal(want) {
+			t.Errorf("%s: expected today to be %v, got %v", timezone, want, response.Today)
+		}
+	}
+	testUserModel.SetTimezone(1, "UTC")
+}
+
+func TestGroupGoalValidation(t *testing.T) {
+	setupGroupTest()
+
+	for _, goals := range []map[string]interface{}{
+		{"goal_active_days_per_week": 8},
+		{"goal_total_activities": 0},
+		{"goal_activities_per_member": 2.5},
+		{"goal_total_activities": "lots"},
+	} {
+		if status := groupRequest("PUT", "/groups/1", 1, goals).Code; status != http.StatusBadRequest {
+			t.Errorf("%v: handler returned wrong status code: got %v want %v", goals, status, http.StatusBadRequest)
+		}
+	}
+
+	recorder := groupRequest("POST", "/groups", 1, map[string]interface{}{
+		"name":                      "Weekly Grind",
+		"end_date":                  "2030-12-31",
+		"goal_active_days_per_week": 0,
+	})
 	if status := recorder.Code; status != http.StatusBadRequest {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
 	}
+
+	// Setting a goal to null removes it
+	groupRequest("PUT", "/groups/1", 1, map[string]interface{}{"goal_total_activities": 100})
+	groupRequest("PUT", "/groups/1", 1, map[string]interface{}{"goal_total_activities": nil})
+	if g, _ := testGroupModel.GetGroupByID(1); g.GoalTotalActivities != nil {
+		t.Errorf("Expected the goal to be removed, got %v", *g.GoalTotalActivities)
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	setupActivityTest()
+	setupGroupTest()
+	testReactionModel.Clear()
+	testCommentModel.Clear()
+
+	start, end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	testGroupModel.UpdateGroup(1, map[string]interface{}{"start_date": start, "end_date": end})
+	runners := testGroupModel.CreateGroup(group.Group{Name: "Runners", CreatorID: 3, StartDate: start, EndDate: end})
+	strangers := testGroupModel.CreateGroup(group.Group{Name: "Strangers", CreatorID: 3, StartDate: start, EndDate: end})
+	for _, m := range []struct{ groupID, userID int }{{1, 2}, {runners.ID, 3}, {runners.ID, 1}, {runners.ID, 2}, {strangers.ID, 3}} {
+		ensureUser(m.userID)
+		testGroupModel.AddUserToGroup(m.groupID, m.userID)
+	}
+	nickname := "Ace"
+	testGroupModel.SetUserNickname(1, 2, &nickname)
+
+	post := func(userID int, date time.Time, groupIDs ...int) int {
+		a := testActivityModel.CreateActivity(activity.Activity{Title: "Solve", CreatorID: userID, Date: date})
+		for _, groupID := range groupIDs {
+			testGroupModel.AddActivityToGroup(groupID, a.ID)
+		}
+		return a.ID
+	}
+	first := post(2, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 1)
+	shared := post(2, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), 1, runners.ID)
+	post(3, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), strangers.ID)
+	latest := post(1, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), 1)
+	post(2, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	testReactionModel.AddReaction(reaction.Reaction{ActivityID: shared, UserID: 1, Kind: reaction.KindFire})
+	testReactionModel.AddReaction(reaction.Reaction{ActivityID: shared, UserID: 2, Kind: reaction.KindFire})
+	testReactionModel.AddReaction(reaction.Reaction{ActivityID: shared, UserID: 2, Kind: reaction.KindClap})
+	testCommentModel.CreateComment(comment.Comment{ActivityID: latest, UserID: 2, Content: "Nice"})
+
+	type page struct {
+		Items      []group.FeedItem `json:"items"`
+		NextCursor *string          `json:"next_cursor"`
+	}
+	getPage := func(query string) page {
+		recorder := groupRequest("GET", "/users/me/feed?"+query, 1, nil)
+		if status := recorder.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		var p page
+		if err := json.NewDecoder(recorder.Body).Decode(&p); err != nil {
+			t.Fatal("Failed to decode response body:", err)
+		}
+		return p
+	}
+
+	firstPage := getPage("limit=2")
+	if len(firstPage.Items) != 2 || firstPage.Items[0].ID != latest || firstPage.Items[1].ID != shared {
+		t.Fatalf("Expected activities %d and %d, got %+v", latest, shared, firstPage.Items)
+	}
+	if firstPage.NextCursor == nil {
+		t.Fatal("Expected a cursor for the next page")
+	}
+	if item := firstPage.Items[0]; item.CommentCount != 1 || item.Author.ID != 1 {
+		t.Errorf("Unexpected author or comment count: %+v", item)
+	}
+
+	item := firstPage.Items[1]
+	if len(item.Groups) != 2 || item.Groups[0].Name != "New Group" || item.Groups[1].Name != "Runners" {
+		t.Errorf("Expected the shared activity in both groups, got %+v", item.Groups)
+	} else if item.Groups[0].AuthorNickname == nil || *item.Groups[0].AuthorNickname != "Ace" || item.Groups[1].AuthorNickname != nil {
+		t.Errorf("Expected the author's nickname in the first group only, got %+v", item.Groups)
+	}
+	wantReactions := []reaction.Summary{{Kind: reaction.KindFire, Count: 2, Reacted: true}, {Kind: reaction.KindClap, Count: 1, Reacted: false}}
+	if len(item.Reactions) != 2 || item.Reactions[0] != wantReactions[0] || item.Reactions[1] != wantReactions[1] {
+		t.Errorf("Expected reactions %+v, got %+v", wantReactions, item.Reactions)
+	}
+
+	// Activities from groups the user is not in, or outside a group's dates, are left out
+	secondPage := getPage("limit=2&cursor=" + *firstPage.NextCursor)
+	if len(secondPage.Items) != 1 || secondPage.Items[0].ID != first || secondPage.NextCursor != nil {
+		t.Errorf("Expected only activity %d on the last page, got %+v", first, secondPage)
+	}
+
+	for _, query := range []string{"cursor=not-a-cursor", "limit=0", "limit=51"} {
+		if status := groupRequest("GET", "/users/me/feed?"+query, 1, nil).Code; status != http.StatusBadRequest {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", query, status, http.StatusBadRequest)
+		}
+	}
 }
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"testing"
+
+	"backend/judge"
+)
+
+func TestParseProblemURL(t *testing.T) {
+	cases := []struct {
+		url       string
+		key       string
+		canonical string
+	}{
+		{"https://codeforces.com/problemset/problem/1352/A", "codeforces:1352A", "https://codeforces.com/problemset/problem/1352/A"},
+		{"http://www.codeforces.com/contest/1352/problem/a?locale=en", "codeforces:1352A", "https://codeforces.com/problemset/problem/1352/A"},
+		{"https://mirror.codeforces.com/contest/1521/problem/F2/", "codeforces:1521F2", "https://codeforces.com/problemset/problem/1521/F2"},
+		{"https://codeforces.com/gym/102942/problem/B", "codeforces:gym102942B", "https://codeforces.com/gym/102942/problem/B"},
+		{"https://codeforces.com/problemset/gymProblem/102942/B", "codeforces:gym102942B", "https://codeforces.com/gym/102942/problem/B"},
+		{"https://atcoder.jp/contests/abc300/tasks/abc300_a", "atcoder:abc300_a", "https://atcoder.jp/contests/abc300/tasks/abc300_a"},
+		{"https://atcoder.jp/contests/dp/tasks/dp_a?lang=en", "atcoder:dp_a", "https://atcoder.jp/contests/dp/tasks/dp_a"},
+		{"https://leetcode.com/problems/two-sum/description/", "leetcode:two-sum", "https://leetcode.com/problems/two-sum/"},
+		{"https://leetcode.cn/problems/Two-Sum", "leetcode:two-sum", "https://leetcode.com/problems/two-sum/"},
+		{"https://www.spoj.com/problems/prime1/", "spoj:PRIME1", "https://www.spoj.com/problems/PRIME1/"},
+		{"https://open.kattis.com/problems/hello", "kattis:hello", "https://open.kattis.com/problems/hello"},
+		{"https://nus.kattis.com/contests/abc123/problems/Hello", "kattis:hello", "https://open.kattis.com/problems/hello"},
+		{"https://cses.fi/problemset/view/1068/", "cses:1068", "https://cses.fi/problemset/task/1068"},
+		{"https://judge.beecrowd.com/en/problems/view/1001", "beecrowd:1001", "https://judge.beecrowd.com/en/problems/view/1001"},
+		{"https://www.urionlinejudge.com.br/judge/pt/problems/view/1001", "beecrowd:1001", "https://judge.beecrowd.com/en/problems/view/1001"},
+		{"https://resources.beecrowd.com.br/repository/UOJ_1001.html", "beecrowd:1001", "https://judge.beecrowd.com/en/problems/view/1001"},
+		{"https://onlinejudge.org/external/100/10055.pdf", "uva:10055", "https://onlinejudge.org/external/100/10055.pdf"},
+		{"https://uva.onlinejudge.org/external/1/100.pdf", "uva:100", "https://onlinejudge.org/external/1/100.pdf"},
+		{"https://onlinejudge.org/index.php?option=com_onlinejudge&Itemid=8&page=show_problem&problem=36", "uva:p36", "https://onlinejudge.org/index.php?option=com_onlinejudge&Itemid=8&page=show_problem&problem=36"},
+		{"https://vjudge.net/problem/CodeForces-1352A", "codeforces:1352A", "https://codeforces.com/problemset/problem/1352/A"},
+		{"https://vjudge.net/problem/Gym-102942B", "codeforces:gym102942B", "https://codeforces.com/gym/102942/problem/B"},
+		{"https://vjudge.net/problem/UVA-100", "uva:100", "https://onlinejudge.org/external/1/100.pdf"},
+		{"https://vjudge.net/problem/HDU-1000", "vjudge:hdu-1000", "https://vjudge.net/problem/hdu-1000"},
+		{"https://vjudge.net/problem/hdu-1000", "vjudge:hdu-1000", "https://vjudge.net/problem/hdu-1000"},
+	}
+	for _, c := range cases {
+		problem, err := judge.ParseURL(c.url)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.url, err)
+			continue
+		}
+		if problem.Key() != c.key || problem.URL != c.canonical {
+			t.Errorf("%s: expected %s at %s, got %s at %s", c.url, c.key, c.canonical, problem.Key(), problem.URL)
+		}
+	}
+
+	for _, url := range []string{
+		"not a url",
+		"ftp://codeforces.com/problemset/problem/1352/A",
+		"https://codeforces.com/blog/entry/1",
+		"https://codeforces.com/problemset/problem/1352/AB",
+		"https://atcoder.jp/contests/abc300",
+		"https://leetcode.com/problemset/all/",
+		"https://cses.fi/problemset/",
+		"https://vjudge.net/contest/123456#problem/A",
+		"https://example.com/problems/two-sum",
+		"https://evilbeecrowd.com/judge/en/problems/view/1001",
+		"https://notbeecrowd.com.br/repository/UOJ_1001.html",
+		"https://fakecodeforces.com/problemset/problem/1352/A",
+	} {
+		if _, err := judge.ParseURL(url); err != judge.ErrUnrecognised {
+			t.Errorf("%s: expected ErrUnrecognised, got %v", url, err)
+		}
+	}
+}
+
+func TestCanonicalProblem(t *testing.T) {
+	problem, err := judge.Canonical("codeforces", "1352a")
+	if err != nil || problem.Key() != "codeforces:1352A" {
+		t.Errorf("Expected codeforces:1352A, got %s (%v)", problem.Key(), err)
+	}
+	if problem, err := judge.Canonical("vjudge", "HDU-1000"); err != nil || problem.Key() != "vjudge:hdu-1000" {
+		t.Errorf("Expected vjudge:hdu-1000, got %s (%v)", problem.Key(), err)
+	}
+	if _, err := judge.Canonical("codeforces", "two-sum"); err == nil {
+		t.Errorf("Expected an error for an invalid Codeforces problem id")
+	}
+	if _, err := judge.Canonical("other", "anything"); err != judge.ErrNoCanonicalForm {
+		t.Errorf("Expected ErrNoCanonicalForm, got %v", err)
+	}
+}
@@ -0,0 +1,635 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/models/activity"
+	"backend/models/linkedaccount"
+)
+
+// fakeCodeforces serves user.info and user.status from submissions set by the test, newest first
+// as the real API returns them.
+type fakeCodeforces struct {
+	*httptest.Server
+	mu          sync.Mutex
+	submissions map[string][]map[string]interface{}
+}
+
+func newFakeCodeforces() *fakeCodeforces {
+	f := &fakeCodeforces{submissions: map[string][]map[string]interface{}{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user.info", f.userInfo)
+	mux.HandleFunc("/user.status", f.userStatus)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func (f *fakeCodeforces) reset(handles ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submissions = map[string][]map[string]interface{}{}
+	for _, handle := range handles {
+		f.submissions[handle] = nil
+	}
+}
+
+// submit records a submission by handle on contestID/index at the given time.
+func (f *fakeCodeforces) submit(handle string, id, contestID int, index, name, verdict string, rating int, tags []string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	submission := map[string]interface{}{
+		"id":                  id,
+		"creationTimeSeconds": at.Unix(),
+		"programmingLanguage": "GNU C++17",
+		"verdict":             verdict,
+		"problem": map[string]interface{}{
+			"contestId": contestID,
+			"index":     index,
+			"name":      name,
+			"rating":    rating,
+			"tags":      tags,
+		},
+	}
+	f.submissions[handle] = append([]map[string]interface{}{submission}, f.submissions[handle]...)
+}
+
+func (f *fakeCodeforces) handle(name string) (string, bool) {
+	for handle := range f.submissions {
+		if strings.EqualFold(handle, name) {
+			return handle, true
+		}
+	}
+	return "", false
+}
+
+func (f *fakeCodeforces) reply(w http.ResponseWriter, result interface{}, comment string) {
+	if comment != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "FAILED", "comment": comment})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "OK", "result": result})
+}
+
+func (f *fakeCodeforces) userInfo(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	handle, ok := f.handle(r.URL.Query().Get("handles"))
+	if !ok {
+		f.reply(w, nil, "handles: User with handle "+r.URL.Query().Get("handles")+" not found")
+		return
+	}
+	f.reply(w, []map[string]interface{}{{"handle": handle}}, "")
+}
+
+func (f *fakeCodeforces) userStatus(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	handle, ok := f.handle(r.URL.Query().Get("handle"))
+	if !ok {
+		f.reply(w, nil, "handle: User with handle "+r.URL.Query().Get("handle")+" not found")
+		return
+	}
+	from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	submissions := f.submissions[handle]
+	start := min(from-1, len(submissions))
+	f.reply(w, submissions[start:min(start+count, len(submissions))], "")
+}
+
+func setupLinkedAccountTest() {
+	testLinkedAccountModel.Clear()
+	testActivityModel.Clear()
+	testGroupModel.Clear()
+	testGroupModel.SeedDefaultData()
+	testProblemModel.Clear()
+	testCodeforces.reset("tourist", "Petr")
+}
+
+func linkedAccountRequest(method, path string, requesterID int, payload interface{}) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	if payload != nil {
+		json.NewEncoder(&body).Encode(payload)
+	}
+	req, _ := http.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	if requesterID != 0 {
+		authorize(req, requesterID)
+	}
+	recorder := httptest.NewRecorder()
+	testLinkedAccountRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func linkAccount(requesterID int, judge string, payload map[string]interface{}) *httptest.ResponseRecorder {
+	return linkedAccountRequest("PUT", "/users/me/accounts/"+judge, requesterID, payload)
+}
+
+func TestLinkAccount(t *testing.T) {
+	setupLinkedAccountTest()
+
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "nobody"}).Code; status != http.StatusBadRequest {
+		t.Errorf("unknown handle: handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": ""}).Code; status != http.StatusBadRequest {
+		t.Errorf("empty handle: handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := linkAccount(1, "spoj", map[string]interface{}{"handle": "tourist"}).Code; status != http.StatusBadRequest {
+		t.Errorf("unsupported judge: handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist", "group_ids": []int{99}}).Code; status != http.StatusNotFound {
+		t.Errorf("unknown group: handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+	if status := linkAccount(2, "codeforces", map[string]interface{}{"handle": "tourist", "group_ids": []int{1}}).Code; status != http.StatusForbidden {
+		t.Errorf("not a member: handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	recorder := linkAccount(1, "codeforces", map[string]interface{}{"handle": "TOURIST", "group_ids": []int{1, 1}})
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, recorder.Body.String())
+	}
+	var linked linkedaccount.LinkedAccount
+	json.NewDecoder(recorder.Body).Decode(&linked)
+	if linked.Handle != "tourist" || len(linked.GroupIDs) != 1 || linked.GroupIDs[0] != 1 {
+		t.Errorf("Expected handle tourist posting to group 1, got %q posting to %v", linked.Handle, linked.GroupIDs)
+	}
+
+	if status := linkAccount(2, "codeforces", map[string]interface{}{"handle": "Tourist"}).Code; status != http.StatusConflict {
+		t.Errorf("taken handle: handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+
+	// Linking again replaces the handle
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "petr"}).Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	recorder = linkedAccountRequest("GET", "/users/1/accounts", 0, nil)
+	var listed struct {
+		Accounts []linkedaccount.LinkedAccount `json:"accounts"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&listed)
+	if len(listed.Accounts) != 1 || listed.Accounts[0].Handle != "Petr" || len(listed.Accounts[0].GroupIDs) != 0 {
+		t.Errorf("Expected only the Petr account, got %v", listed.Accounts)
+	}
+
+	if status := linkedAccountRequest("DELETE", "/users/me/accounts/codeforces", 1, nil).Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+	if status := linkedAccountRequest("DELETE", "/users/me/accounts/codeforces", 1, nil).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestImportCodeforcesSubmissions(t *testing.T) {
+	setupLinkedAccountTest()
+	at := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	// Made before linking, so never imported
+	testCodeforces.submit("tourist", 100, 4, "A", "Watermelon", "OK", 800, []string{"math"}, at)
+
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist", "group_ids": []int{1}}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	importAccount := func() int {
+		account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+		created, err := testImporter.ImportAccount(context.Background(), account)
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		return created
+	}
+
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected the first import to only start the cursor, created %d", created)
+	}
+
+	testCodeforces.submit("tourist", 101, 1352, "A", "Sum of Round Numbers", "WRONG_ANSWER", 800, nil, at)
+	testCodeforces.submit("tourist", 102, 1352, "A", "Sum of Round Numbers", "OK", 800, []string{"implementation", "math"}, at.Add(time.Hour))
+	testCodeforces.submit("tourist", 103, 1352, "A", "Sum of Round Numbers", "OK", 800, nil, at.Add(2*time.Hour))
+	testCodeforces.submit("tourist", 104, 102942, "B", "Gym Problem", "OK", 0, []string{"*special problem"}, at.Add(3*time.Hour))
+
+	if created := importAccount(); created != 2 {
+		t.Fatalf("Expected 2 imported activities, got %d", created)
+	}
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected nothing new on the next import, got %d", created)
+	}
+
+	activities := testActivityModel.GetActivitiesByCreatorID(1)
+	sort.Slice(activities, func(i, j int) bool { return activities[i].ID < activities[j].ID })
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(activities))
+	}
+	first := activities[0]
+	if first.Title != "Sum of Round Numbers" || first.ProblemKey == nil || *first.ProblemKey != "codeforces:1352A" ||
+		first.SubmissionID == nil || *first.SubmissionID != "102" || first.Rating == nil || *first.Rating != 800 ||
+		first.Verdict == nil || *first.Verdict != activity.VerdictAccepted || len(first.Tags) != 2 {
+		t.Errorf("Unexpected imported activity: %+v", first)
+	}
+	// Imports name the problem in the catalog, as the judge knows it
+	if first.CatalogProblemID == nil {
+		t.Errorf("Expected the imported activity to be linked to the problem catalog")
+	} else if p, _ := testProblemModel.GetProblemByID(*first.CatalogProblemID); p.Name != "Sum of Round Numbers" || p.Rating == nil || *p.Rating != 800 {
+		t.Errorf("Unexpected catalog problem: %+v", p)
+	}
+	if !first.Date.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the activity on 2025-07-01, got %v", first.Date)
+	}
+	if second := activities[1]; second.ProblemKey == nil || *second.ProblemKey != "codeforces:gym102942B" || second.Rating != nil {
+		t.Errorf("Unexpected imported gym activity: %+v", second)
+	}
+	for _, a := range activities {
+		if groups := testGroupModel.GetActivityGroupIDs(a.ID); len(groups) != 1 || groups[0] != 1 {
+			t.Errorf("Expected activity %d to be posted to group 1, got %v", a.ID, groups)
+		}
+	}
+
+	// Submissions are never imported twice, even when the cursor is lost
+	account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	testLinkedAccountModel.SetCursor(account.ID, account.Handle, "100", time.Now())
+	testActivityModel.DeleteActivity(activities[0].ID)
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected already imported submissions to be skipped, got %d", created)
+	}
+}
+
+func TestImportCodeforcesBacklog(t *testing.T) {
+	setupLinkedAccountTest()
+	at := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	testCodeforces.submit("tourist", 100, 4, "A", "Watermelon", "WRONG_ANSWER", 800, nil, at)
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	importAccount := func() (int, string) {
+		account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+		created, err := testImporter.ImportAccount(context.Background(), account)
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		account, _ = testLinkedAccountModel.GetAccount(1, "codeforces")
+		return created, account.Cursor
+	}
+	importAccount()
+
+	// More new submissions than one import pages through, with solves at both ends
+	testCodeforces.submit("tourist", 101, 4, "A", "Watermelon", "OK", 800, nil, at)
+	for id := 102; id <= 1201; id++ {
+		testCodeforces.submit("tourist", id, 71, "A", "Way Too Long Words", "WRONG_ANSWER", 800, nil, at)
+	}
+	testCodeforces.submit("tourist", 1202, 71, "A", "Way Too Long Words", "OK", 800, nil, at.Add(time.Hour))
+
+	if created, cursor := importAccount(); created != 1 || cursor == "1202" {
+		t.Fatalf("Expected the newest solve to be imported and a backlog left, got %d and cursor %q", created, cursor)
+	}
+	// New submissions in between do not hide the backlog
+	testCodeforces.submit("tourist", 1203, 1352, "A", "Sum of Round Numbers", "OK", 800, nil, at.Add(2*time.Hour))
+	if created, cursor := importAccount(); created != 1 || cursor != "1202" {
+		t.Fatalf("Expected the oldest solve to be imported and the cursor at 1202, got %d and cursor %q", created, cursor)
+	}
+	if created, cursor := importAccount(); created != 1 || cursor != "1203" {
+		t.Errorf("Expected the new solve to be imported, got %d and cursor %q", created, cursor)
+	}
+	if activities := testActivityModel.GetActivitiesByCreatorID(1); len(activities) != 3 {
+		t.Errorf("Expected 3 imported activities, got %d", len(activities))
+	}
+}
+
+// fakeAtCoderProblems serves the AtCoder Problems endpoints the importer uses, with the users and
+// submissions set by the test. Like the real API, from_second is inclusive and results are oldest first.
+type fakeAtCoderProblems struct {
+	*httptest.Server
+	mu          sync.Mutex
+	submissions map[string][]map[string]interface{}
+}
+
+func newFakeAtCoderProblems() *fakeAtCoderProblems {
+	f := &fakeAtCoderProblems{submissions: map[string][]map[string]interface{}{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/atcoder-api/v3/user/ac_rank", f.acRank)
+	mux.HandleFunc("/atcoder-api/v3/user/submissions", f.userSubmissions)
+	mux.HandleFunc("/resources/problems.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": "abc300_a", "contest_id": "abc300", "name": "N-choice question"},
+			{"id": "abc300_e", "contest_id": "abc300", "name": "Dice Product 3"},
+			{"id": "dp_a", "contest_id": "dp", "name": "Frog 1"},
+		})
+	})
+	mux.HandleFunc("/resources/problem-models.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"abc300_a": map[string]interface{}{"difficulty": -300.0},
+			"abc300_e": map[string]interface{}{"difficulty": 1701.4},
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func (f *fakeAtCoderProblems) reset(users ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submissions = map[string][]map[string]interface{}{}
+	for _, user := range users {
+		f.submissions[user] = nil
+	}
+}
+
+func (f *fakeAtCoderProblems) submit(user string, id int, contestID, problemID, result string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submissions[user] = append(f.submissions[user], map[string]interface{}{
+		"id":           id,
+		"epoch_second": at.Unix(),
+		"problem_id":   problemID,
+		"contest_id":   contestID,
+		"user_id":      user,
+		"language":     "C++ 20 (gcc 12.2)",
+		"result":       result,
+	})
+}
+
+func (f *fakeAtCoderProblems) acRank(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.submissions[r.URL.Query().Get("user")]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"count": 1, "rank": 100})
+}
+
+func (f *fakeAtCoderProblems) userSubmissions(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	from, _ := strconv.ParseInt(r.URL.Query().Get("from_second"), 10, 64)
+	result := []map[string]interface{}{}
+	for _, s := range f.submissions[r.URL.Query().Get("user")] {
+		if s["epoch_second"].(int64) >= from {
+			result = append(result, s)
+		}
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func TestImportAtCoderSubmissions(t *testing.T) {
+	setupLinkedAccountTest()
+	testAtCoder.reset("chokudai")
+
+	if status := linkAccount(1, "atcoder", map[string]interface{}{"handle": "nobody"}).Code; status != http.StatusBadRequest {
+		t.Errorf("unknown user: handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if status := linkAccount(1, "atcoder", map[string]interface{}{"handle": "chokudai"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	importAccount := func() int {
+		account, _ := testLinkedAccountModel.GetAccount(1, "atcoder")
+		created, err := testImporter.ImportAccount(context.Background(), account)
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		return created
+	}
+
+	// The first import only records where to start from
+	testAtCoder.submit("chokudai", 1, "dp", "dp_a", "AC", time.Now().Add(-time.Hour))
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected the first import to only start the cursor, created %d", created)
+	}
+
+	at := time.Now().Add(time.Minute)
+	testAtCoder.submit("chokudai", 2, "abc300", "abc300_a", "AC", at)
+	testAtCoder.submit("chokudai", 3, "abc300", "abc300_e", "WA", at.Add(time.Second))
+	testAtCoder.submit("chokudai", 4, "abc300", "abc300_e", "AC", at.Add(2*time.Second))
+
+	if created := importAccount(); created != 2 {
+		t.Fatalf("Expected 2 imported activities, got %d", created)
+	}
+	// The last second is fetched again and skipped
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected nothing new on the next import, got %d", created)
+	}
+	account, _ := testLinkedAccountModel.GetAccount(1, "atcoder")
+	if account.Cursor != strconv.FormatInt(at.Add(2*time.Second).Unix(), 10) || account.LastImportedAt == nil {
+		t.Errorf("Expected the cursor at the last submission, got %q", account.Cursor)
+	}
+
+	ratings := map[string]int{}
+	for _, a := range testActivityModel.GetActivitiesByCreatorID(1) {
+		if a.Judge == nil || *a.Judge != activity.JudgeAtCoder || a.Rating == nil || a.ProblemURL == nil {
+			t.Fatalf("Unexpected imported activity: %+v", a)
+		}
+		ratings[a.Title] = *a.Rating
+		if a.Title == "Dice Product 3" && *a.ProblemURL != "https://atcoder.jp/contests/abc300/tasks/abc300_e" {
+			t.Errorf("Unexpected problem URL %s", *a.ProblemURL)
+		}
+	}
+	// Negative estimates are squashed below 400
+	if ratings["N-choice question"] != 70 || ratings["Dice Product 3"] != 1701 {
+		t.Errorf("Expected ratings 70 and 1701, got %v", ratings)
+	}
+}
+
+// fakeLeetCode answers the three GraphQL queries the importer sends, telling them apart by the field
+// they select. Recent submissions are listed newest first, at most limit of them.
+type fakeLeetCode struct {
+	*httptest.Server
+	mu          sync.Mutex
+	users       map[string][]map[string]interface{}
+	questionHit map[string]int
+}
+
+func newFakeLeetCode() *fakeLeetCode {
+	f := &fakeLeetCode{}
+	f.reset()
+	f.Server = httptest.NewServer(http.HandlerFunc(f.graphql))
+	return f
+}
+
+func (f *fakeLeetCode) reset(users ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users = map[string][]map[string]interface{}{}
+	f.questionHit = map[string]int{}
+	for _, user := range users {
+		f.users[user] = nil
+	}
+}
+
+func (f *fakeLeetCode) submit(user, id, title, titleSlug string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[user] = append([]map[string]interface{}{{
+		"id":        id,
+		"title":     title,
+		"titleSlug": titleSlug,
+		"timestamp": strconv.FormatInt(at.Unix(), 10),
+		"lang":      "python3",
+	}}, f.users[user]...)
+}
+
+func (f *fakeLeetCode) graphql(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	json.NewDecoder(r.Body).Decode(&request)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data := map[string]interface{}{}
+	switch {
+	case strings.Contains(request.Query, "matchedUser"):
+		username, _ := request.Variables["username"].(string)
+		data["matchedUser"] = nil
+		for user := range f.users {
+			if strings.EqualFold(user, username) {
+				data["matchedUser"] = map[string]interface{}{"username": user}
+			}
+		}
+	case strings.Contains(request.Query, "recentAcSubmissionList"):
+		submissions := f.users[request.Variables["username"].(string)]
+		limit := int(request.Variables["limit"].(float64))
+		data["recentAcSubmissionList"] = submissions[:min(limit, len(submissions))]
+	case strings.Contains(request.Query, "question("):
+		slug := request.Variables["titleSlug"].(string)
+		f.questionHit[slug]++
+		data["question"] = map[string]interface{}{
+			"difficulty": map[string]string{"two-sum": "Easy", "lru-cache": "Medium"}[slug],
+			"topicTags":  []map[string]string{{"name": "Hash Table"}, {"name": "Design"}},
+		}
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []map[string]string{{"message": "unknown query"}}})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func TestImportLeetCodeSubmissions(t *testing.T) {
+	setupLinkedAccountTest()
+	testLeetCode.reset("grinder")
+
+	if status := linkAccount(1, "leetcode", map[string]interface{}{"handle": "nobody"}).Code; status != http.StatusBadRequest {
+		t.Errorf("unknown user: handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	recorder := linkAccount(1, "leetcode", map[string]interface{}{"handle": "Grinder", "group_ids": []int{1}})
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	importAccount := func() int {
+		account, _ := testLinkedAccountModel.GetAccount(1, "leetcode")
+		created, err := testImporter.ImportAccount(context.Background(), account)
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		return created
+	}
+
+	at := time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC)
+	testLeetCode.submit("grinder", "900", "Two Sum", "two-sum", at.Add(-time.Hour))
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected the first import to only start the cursor, created %d", created)
+	}
+
+	testLeetCode.submit("grinder", "901", "Two Sum", "two-sum", at)
+	testLeetCode.submit("grinder", "902", "LRU Cache", "lru-cache", at.Add(time.Minute))
+	testLeetCode.submit("grinder", "903", "Two Sum", "two-sum", at.Add(2*time.Minute))
+	if created := importAccount(); created != 2 {
+		t.Fatalf("Expected 2 imported activities, got %d", created)
+	}
+	if created := importAccount(); created != 0 {
+		t.Errorf("Expected nothing new on the next import, got %d", created)
+	}
+	if hits := testLeetCode.questionHit["two-sum"]; hits != 1 {
+		t.Errorf("Expected the question to be loaded once, got %d", hits)
+	}
+
+	activities := testActivityModel.GetActivitiesByCreatorID(1)
+	sort.Slice(activities, func(i, j int) bool { return activities[i].ID < activities[j].ID })
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(activities))
+	}
+	twoSum := activities[0]
+	if twoSum.Title != "Two Sum" || twoSum.ProblemKey == nil || *twoSum.ProblemKey != "leetcode:two-sum" ||
+		twoSum.Difficulty == nil || *twoSum.Difficulty != activity.DifficultyEasy || twoSum.Rating != nil ||
+		len(twoSum.Tags) != 2 || twoSum.Tags[0] != "hash table" || twoSum.SubmissionID == nil || *twoSum.SubmissionID != "901" {
+		t.Errorf("Unexpected imported activity: %+v", twoSum)
+	}
+	if lru := activities[1]; lru.Difficulty == nil || *lru.Difficulty != activity.DifficultyMedium {
+		t.Errorf("Expected a medium difficulty, got %v", lru.Difficulty)
+	}
+	if groups := testGroupModel.GetActivityGroupIDs(twoSum.ID); len(groups) != 1 {
+		t.Errorf("Expected the activity to be posted to group 1, got %v", groups)
+	}
+}
+
+func TestRelinkingKeepsAccount(t *testing.T) {
+	setupLinkedAccountTest()
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	testLinkedAccountModel.SetCursor(account.ID, account.Handle, "42", time.Now())
+	testLinkedAccountModel.RecordFailure(account.ID, account.Handle, "codeforces is down", time.Now(), nil)
+
+	// Imports running on the account still find it, with its position and failures
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "Tourist", "group_ids": []int{1}}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	relinked, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	if relinked.ID != account.ID || relinked.Cursor != "42" || relinked.Failures != 1 || len(relinked.GroupIDs) != 1 {
+		t.Errorf("Expected account %d to keep cursor 42 and 1 failure and post to group 1, got %+v", account.ID, relinked)
+	}
+
+	// Another handle starts over
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "petr"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	relinked, _ = testLinkedAccountModel.GetAccount(1, "codeforces")
+	if relinked.ID != account.ID || relinked.Handle != "Petr" || relinked.Cursor != "" || relinked.Failures != 0 ||
+		relinked.LastError != nil || relinked.LastImportedAt != nil || relinked.NextSyncAt == nil {
+		t.Errorf("Expected account %d to start over as Petr, got %+v", account.ID, relinked)
+	}
+}
+
+func TestImportOfOldHandleLeavesRelinkedAccount(t *testing.T) {
+	setupLinkedAccountTest()
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "petr"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	// An import of tourist that finishes after the relink must not touch petr's position or schedule
+	now := time.Now()
+	if testLinkedAccountModel.SetCursor(account.ID, account.Handle, "42", now) {
+		t.Errorf("Expected SetCursor for tourist to report no update")
+	}
+	if testLinkedAccountModel.RecordFailure(account.ID, account.Handle, "codeforces is down", now, nil) {
+		t.Errorf("Expected RecordFailure for tourist to report no update")
+	}
+	if testLinkedAccountModel.SetNextSync(account.ID, account.Handle, nil) {
+		t.Errorf("Expected SetNextSync for tourist to report no update")
+	}
+	relinked, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	if relinked.Cursor != "" || relinked.Failures != 0 || relinked.LastError != nil || relinked.NextSyncAt == nil {
+		t.Errorf("Expected Petr to keep starting over, got %+v", relinked)
+	}
+
+	// Imports of the linked handle still update it, whatever its case
+	if !testLinkedAccountModel.SetCursor(account.ID, "PETR", "7", now) {
+		t.Errorf("Expected SetCursor for petr to update the account")
+	}
+	relinked, _ = testLinkedAccountModel.GetAccount(1, "codeforces")
+	if relinked.Cursor != "7" {
+		t.Errorf("Expected cursor 7, got %q", relinked.Cursor)
+	}
+}
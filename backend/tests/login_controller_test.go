@@ -3,13 +3,69 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"backend/auth"
+	"backend/models/user"
 )
 
-func TestLoginSuccess(t *testing.T) {
+func setupLoginTest() {
 	setupUserTest()
+	testSessionModel.Clear()
+}
+
+// login signs in as the seeded user and returns the decoded login response.
+func login(t *testing.T, userAgent string) map[string]interface{} {
+	body, _ := json.Marshal(map[string]interface{}{
+		"email":    "user@example.com",
+		"password": "password123",
+	})
+	req, err := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("login returned wrong status code: got %v want %v", recorder.Code, http.StatusOK)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	return response
+}
+
+func refresh(refreshToken string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{"refresh_token": refreshToken})
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func withToken(method, url, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestLoginSuccess(t *testing.T) {
+	setupLoginTest()
 	loginRequest := map[string]interface{}{
 		"email":    "user@example.com",
 		"password": "password123",
@@ -34,10 +90,22 @@ func TestLoginSuccess(t *testing.T) {
 		t.Fatal("Failed to decode response body")
 	}
 
-	if _, exists := response["token"]; !exists {
+	token, exists := response["token"].(string)
+	if !exists {
 		t.Error("No token in login response")
 	}
 
+	claims, err := testTokenManager.ParseAccessToken(token)
+	if err != nil {
+		t.Errorf("Login token failed verification: %v", err)
+	} else if claims.UserID != 1 {
+		t.Errorf("Expected token subject to be user 1, got %d", claims.UserID)
+	}
+
+	if refreshToken, _ := response["refresh_token"].(string); refreshToken == "" {
+		t.Error("No refresh token in login response")
+	}
+
 	if user, exists := response["user"].(map[string]interface{}); !exists {
 		t.Error("No user info in login response")
 	} else {
@@ -51,7 +119,7 @@ func TestLoginSuccess(t *testing.T) {
 }
 
 func TestLoginInvalidCredentials(t *testing.T) {
-	setupUserTest()
+	setupLoginTest()
 	loginRequest := map[string]interface{}{
 		"email":    "user@example.com",
 		"password": "wrongpassword",
@@ -73,7 +141,7 @@ func TestLoginInvalidCredentials(t *testing.T) {
 }
 
 func TestLoginMissingFields(t *testing.T) {
-	setupUserTest()
+	setupLoginTest()
 	loginRequest := map[string]interface{}{
 		"email": "user@example.com",
 		// Missing password
@@ -93,3 +161,221 @@ func TestLoginMissingFields(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
 	}
 }
+
+func TestLoginTokenRejectsForgery(t *testing.T) {
+	forged := auth.NewTokenManager([]byte("some-other-secret-key-that-is-long"), "codeck-test", time.Hour, time.Hour)
+	token, _, err := forged.IssueAccessToken(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testTokenManager.ParseAccessToken(token); err == nil {
+		t.Error("Token signed with a different key should be rejected")
+	}
+
+	if _, err := testTokenManager.ParseAccessToken("dummy-jwt-token-1"); err == nil {
+		t.Error("Malformed token should be rejected")
+	}
+
+	expired := auth.NewTokenManager([]byte("test-secret-key-that-is-long-enough"), "codeck-test", -time.Minute, time.Hour)
+	token, _, err = expired.IssueAccessToken(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testTokenManager.ParseAccessToken(token); err == nil {
+		t.Error("Expired token should be rejected")
+	}
+}
+
+func TestLoginUpgradesPlaintextPassword(t *testing.T) {
+	setupLoginTest()
+	legacy := user.User{
+		Email:    "legacy@example.com",
+		Name:     "Legacy User",
+		Password: "legacy-plaintext",
+	}
+	if err := testDB.Create(&legacy).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	loginRequest := map[string]interface{}{
+		"email":    "legacy@example.com",
+		"password": "legacy-plaintext",
+	}
+
+	body, _ := json.Marshal(loginRequest)
+	req, err := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	stored, _ := testUserModel.GetUserByEmail("legacy@example.com")
+	if stored.Password == "legacy-plaintext" || !strings.HasPrefix(stored.Password, "$2") {
+		t.Error("Plaintext password should have been replaced by a bcrypt hash")
+	}
+
+	// The upgraded hash must still accept the original password
+	if _, valid := testUserModel.ValidateCredentials("legacy@example.com", "legacy-plaintext"); !valid {
+		t.Error("Upgraded password should still validate")
+	}
+	if _, valid := testUserModel.ValidateCredentials("legacy@example.com", "wrong"); valid {
+		t.Error("Wrong password should not validate after upgrade")
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	setupLoginTest()
+	refreshToken := login(t, "test-agent")["refresh_token"].(string)
+
+	recorder := refresh(refreshToken)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	rotated, _ := response["refresh_token"].(string)
+	if rotated == "" || rotated == refreshToken {
+		t.Error("Refresh should return a new refresh token")
+	}
+	token, _ := response["token"].(string)
+	if claims, err := testTokenManager.ParseAccessToken(token); err != nil || claims.UserID != 1 {
+		t.Errorf("Refreshed access token is invalid: %v", err)
+	}
+
+	// The rotated token keeps working
+	if status := refresh(rotated).Code; status != http.StatusOK {
+		t.Errorf("Rotated refresh token returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	setupLoginTest()
+	response := login(t, "test-agent")
+	refreshToken := response["refresh_token"].(string)
+	accessToken := response["token"].(string)
+
+	first := refresh(refreshToken)
+	if first.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", first.Code, http.StatusOK)
+	}
+	var rotated map[string]interface{}
+	json.NewDecoder(first.Body).Decode(&rotated)
+
+	// Presenting the old token again looks like theft, so the whole session goes
+	if status := refresh(refreshToken).Code; status != http.StatusUnauthorized {
+		t.Errorf("Reused refresh token returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := refresh(rotated["refresh_token"].(string)).Code; status != http.StatusUnauthorized {
+		t.Errorf("Refresh token of revoked session returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := withToken("GET", "/users/me/sessions", accessToken).Code; status != http.StatusUnauthorized {
+		t.Errorf("Access token of revoked session returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestRefreshInvalidToken(t *testing.T) {
+	setupLoginTest()
+	if status := refresh("not-a-real-token").Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := refresh("").Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	setupLoginTest()
+	response := login(t, "test-agent")
+	accessToken := response["token"].(string)
+
+	if status := withToken("POST", "/logout", accessToken).Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+
+	// Neither token of the session may be used after logout
+	if status := withToken("GET", "/users/me/sessions", accessToken).Code; status != http.StatusUnauthorized {
+		t.Errorf("Access token after logout returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := refresh(response["refresh_token"].(string)).Code; status != http.StatusUnauthorized {
+		t.Errorf("Refresh token after logout returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	setupLoginTest()
+	login(t, "laptop")
+	accessToken := login(t, "phone")["token"].(string)
+
+	recorder := withToken("GET", "/users/me/sessions", accessToken)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response map[string][]map[string]interface{}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	sessions := response["sessions"]
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	for _, s := range sessions {
+		if current := s["current"] == true; current != (s["user_agent"] == "phone") {
+			t.Errorf("Session %v has wrong current flag", s["user_agent"])
+		}
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	setupLoginTest()
+	laptop := login(t, "laptop")
+	accessToken := login(t, "phone")["token"].(string)
+
+	laptopClaims, err := testTokenManager.ParseAccessToken(laptop["token"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := fmt.Sprintf("/users/me/sessions/%d", laptopClaims.SessionID)
+	if status := withToken("DELETE", url, accessToken).Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+	if status := withToken("GET", "/users/me/sessions", laptop["token"].(string)).Code; status != http.StatusUnauthorized {
+		t.Errorf("Revoked session returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := withToken("GET", "/users/me/sessions", accessToken).Code; status != http.StatusOK {
+		t.Errorf("Other session returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestRevokeSessionOfOtherUser(t *testing.T) {
+	setupLoginTest()
+	accessToken := login(t, "phone")["token"].(string)
+
+	// Session of another user
+	req, _ := http.NewRequest("GET", "/", nil)
+	authorize(req, 2)
+	otherClaims, err := testTokenManager.ParseAccessToken(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := fmt.Sprintf("/users/me/sessions/%d", otherClaims.SessionID)
+	if status := withToken("DELETE", url, accessToken).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+	if s, _ := testSessionModel.GetSessionByID(otherClaims.SessionID); s.RevokedAt != nil {
+		t.Error("Session of another user should not be revoked")
+	}
+}
@@ -1,32 +1,72 @@
 package tests
 
 import (
+	"fmt"
+	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"backend/auth"
+	"backend/auth/oauth"
 	"backend/controllers"
+	"backend/judge"
+	"backend/mail"
 	"backend/models/activity"
 	"backend/models/comment"
 	"backend/models/group"
+	"backend/models/identity"
+	"backend/models/linkedaccount"
+	"backend/models/pat"
+	"backend/models/problem"
+	"backend/models/reaction"
+	"backend/models/session"
 	"backend/models/user"
+	"backend/ratelimit"
 	"backend/routes"
 
 	"github.com/gorilla/mux"
 )
 
 var (
-	testGroupRouter    *mux.Router
-	testGroupModel     *group.GormGroupModel
-	testActivityRouter *mux.Router
-	testActivityModel  *activity.GormActivityModel
-	testUserRouter     *mux.Router
-	testUserModel      *user.GormUserModel
-	testCommentRouter  *mux.Router
-	testCommentModel   *comment.GormCommentModel
-	testLoginRouter    *mux.Router
+	testDB              *gorm.DB
+	testGroupRouter     *mux.Router
+	testGroupModel      *group.GormGroupModel
+	testActivityRouter  *mux.Router
+	testActivityModel   *activity.GormActivityModel
+	testUserRouter      *mux.Router
+	testUserModel       *user.GormUserModel
+	testCommentRouter   *mux.Router
+	testCommentModel    *comment.GormCommentModel
+	testLoginRouter     *mux.Router
+	testTokenManager    *auth.TokenManager
+	testAuthenticator   *auth.Authenticator
+	testSessionModel    *session.GormSessionModel
+	testAccountRouter   *mux.Router
+	testMailer          *mail.MemoryMailer
+	testOAuthRouter     *mux.Router
+	testIdentityModel   *identity.GormIdentityModel
+	testOIDC            *mockOIDC
+	testTokenRouter     *mux.Router
+	testPATModel        *pat.GormTokenModel
+	testTwoFactorRouter *mux.Router
+	testReactionRouter  *mux.Router
+	testReactionModel   *reaction.GormReactionModel
+	testLimits          *ratelimit.Limits
+
+	testLinkedAccountRouter *mux.Router
+	testLinkedAccountModel  *linkedaccount.GormLinkedAccountModel
+	testImporter            *judge.Importer
+	testScheduler           *judge.Scheduler
+	testCodeforces          *fakeCodeforces
+	testAtCoder             *fakeAtCoderProblems
+	testLeetCode            *fakeLeetCode
+
+	testProblemRouter *mux.Router
+	testProblemModel  *problem.GormProblemModel
 )
 
 func TestMain(m *testing.M) {
@@ -35,31 +75,119 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic("failed to connect database")
 	}
-	db.AutoMigrate(&group.Group{}, &activity.Activity{}, &comment.Comment{}, &user.User{})
+	testDB = db
+	db.AutoMigrate(&group.Group{}, &group.GroupMember{}, &group.GroupInvite{}, &group.OwnershipTransfer{}, &group.GroupActivity{}, &activity.Activity{}, &comment.Comment{}, &reaction.Reaction{}, &user.User{}, &user.UserToken{}, &user.RecoveryCode{}, &user.StreakFreeze{}, &session.Session{}, &session.RefreshToken{}, &identity.Identity{}, &identity.LoginState{}, &pat.PersonalAccessToken{}, &linkedaccount.LinkedAccount{}, &problem.Problem{})
+
+	testUserModel = user.NewGormUserModel(db)
+	testSessionModel = session.NewGormSessionModel(db)
+	testTokenManager = auth.NewTokenManager([]byte("test-secret-key-that-is-long-enough"), "codeck-test", time.Hour, 24*time.Hour)
+	testPATModel = pat.NewGormTokenModel(db)
+	testAuthenticator = auth.NewAuthenticator(testTokenManager, testUserModel, testSessionModel, testPATModel)
+	// Limits start disabled; rate limit tests turn on the ones they exercise
+	testLimits = ratelimit.NewLimits(ratelimit.NewMemoryStore(), ratelimit.Config{})
 
+	testReactionModel = reaction.NewGormReactionModel(db)
 	testGroupModel = group.NewGormGroupModel(db)
-	groupController := controllers.NewGroupController(testGroupModel)
+	groupController := controllers.NewGroupController(testGroupModel, testReactionModel)
 	testGroupRouter = mux.NewRouter()
-	routes.RegisterGroupRoutes(testGroupRouter, groupController)
+	routes.RegisterGroupRoutes(testGroupRouter, groupController, testAuthenticator)
 
 	testActivityModel = activity.NewGormActivityModel(db)
-	activityController := controllers.NewActivityController(testActivityModel)
+	testProblemModel = problem.NewGormProblemModel(db)
+	activityController := controllers.NewActivityController(testActivityModel, testGroupModel, testProblemModel)
 	testActivityRouter = mux.NewRouter()
-	routes.RegisterActivityRoutes(testActivityRouter, activityController)
+	routes.RegisterActivityRoutes(testActivityRouter, activityController, testAuthenticator, testLimits)
 
-	testUserModel = user.NewGormUserModel(db)
 	userController := controllers.NewUserController(testUserModel, testActivityModel)
 	testUserRouter = mux.NewRouter()
-	routes.RegisterUserRoutes(testUserRouter, userController)
+	routes.RegisterUserRoutes(testUserRouter, userController, testAuthenticator, testLimits)
 
-	loginController := controllers.NewLoginController(testUserModel)
+	loginController := controllers.NewLoginController(testUserModel, testSessionModel, testTokenManager)
 	testLoginRouter = mux.NewRouter()
-	routes.RegisterLoginRoutes(testLoginRouter, loginController)
+	routes.RegisterLoginRoutes(testLoginRouter, loginController, testAuthenticator, testLimits)
+
+	twoFactorController := controllers.NewTwoFactorController(testUserModel, loginController)
+	testTwoFactorRouter = mux.NewRouter()
+	routes.RegisterTwoFactorRoutes(testTwoFactorRouter, twoFactorController, testAuthenticator, testLimits)
+
+	testOIDC = newMockOIDC()
+	testIdentityModel = identity.NewGormIdentityModel(db)
+	mockProvider := oauth.NewOIDCProvider("mock", "codeck-client", "codeck-secret", "http://codeck.test/auth/mock/callback", testOIDC.Endpoints())
+	oauthController := controllers.NewOAuthController(oauth.NewRegistry(mockProvider), testIdentityModel, testUserModel, loginController)
+	testOAuthRouter = mux.NewRouter()
+	routes.RegisterOAuthRoutes(testOAuthRouter, oauthController)
+
+	testMailer = mail.NewMemoryMailer()
+	accountController := controllers.NewAccountController(testUserModel, testSessionModel, testMailer, "http://codeck.test")
+	testAccountRouter = mux.NewRouter()
+	routes.RegisterAccountRoutes(testAccountRouter, accountController, testAuthenticator, testLimits)
+
+	tokenController := controllers.NewTokenController(testPATModel)
+	testTokenRouter = mux.NewRouter()
+	routes.RegisterTokenRoutes(testTokenRouter, tokenController, testAuthenticator)
 
 	testCommentModel = comment.NewGormCommentModel(db)
 	commentController := controllers.NewCommentController(testCommentModel, testActivityModel, testGroupModel)
 	testCommentRouter = mux.NewRouter()
-	routes.RegisterCommentRoutes(testCommentRouter, commentController)
+	routes.RegisterCommentRoutes(testCommentRouter, commentController, testAuthenticator, testLimits)
+
+	reactionController := controllers.NewReactionController(testReactionModel, testActivityModel)
+	testReactionRouter = mux.NewRouter()
+	routes.RegisterReactionRoutes(testReactionRouter, reactionController, testAuthenticator)
+
+	testCodeforces = newFakeCodeforces()
+	testAtCoder = newFakeAtCoderProblems()
+	testLeetCode = newFakeLeetCode()
+	testLinkedAccountModel = linkedaccount.NewGormLinkedAccountModel(db)
+	testImporter = judge.NewImporter(testLinkedAccountModel, testActivityModel, testGroupModel, testUserModel, testProblemModel,
+		judge.NewCodeforcesClient(testCodeforces.URL, testCodeforces.Client()),
+		judge.NewAtCoderClient(testAtCoder.URL, testAtCoder.Client()),
+		judge.NewLeetCodeClient(judge.NewHTTPGraphQLClient(testLeetCode.URL, testLeetCode.Client())))
+	testScheduler = judge.NewScheduler(testImporter, testLinkedAccountModel, testSchedulerConfig())
+	linkedAccountController := controllers.NewLinkedAccountController(testLinkedAccountModel, testGroupModel, testImporter, testScheduler)
+	testLinkedAccountRouter = mux.NewRouter()
+	routes.RegisterLinkedAccountRoutes(testLinkedAccountRouter, linkedAccountController, testAuthenticator)
+
+	problemController := controllers.NewProblemController(testProblemModel, testGroupModel, testActivityModel)
+	testProblemRouter = mux.NewRouter()
+	routes.RegisterProblemRoutes(testProblemRouter, problemController, testAuthenticator)
 
-	os.Exit(m.Run())
+	code := m.Run()
+	testOIDC.Close()
+	testCodeforces.Close()
+	testAtCoder.Close()
+	testLeetCode.Close()
+	os.Exit(code)
+}
+
+// ensureUser creates a placeholder account for userID if the user does not exist yet.
+func ensureUser(userID int) {
+	if _, exists := testUserModel.GetUserByID(userID); !exists {
+		testUserModel.CreateUser(user.User{
+			ID:       userID,
+			Email:    fmt.Sprintf("user%d@example.com", userID),
+			Name:     fmt.Sprintf("User %d", userID),
+			Password: "password123",
+		})
+	}
+}
+
+// authorize signs req as userID in a fresh session, creating a placeholder account if the user does not exist yet.
+func authorize(req *http.Request, userID int) {
+	ensureUser(userID)
+
+	_, refreshHash, err := auth.NewSecret()
+	if err != nil {
+		panic(err)
+	}
+	s, ok := testSessionModel.CreateSession(session.Session{UserID: userID, ExpiresAt: testTokenManager.RefreshTokenExpiry()}, refreshHash)
+	if !ok {
+		panic("failed to create test session")
+	}
+
+	token, _, err := testTokenManager.IssueAccessToken(userID, s.ID)
+	if err != nil {
+		panic(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 }
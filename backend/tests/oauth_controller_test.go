@@ -0,0 +1,321 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"backend/auth/oauth"
+)
+
+// mockOIDC is a minimal OpenID Connect provider: codes are handed out by the test, the token endpoint
+// checks the PKCE verifier against the challenge the code was issued for, and userinfo returns the
+// identity set for the test.
+type mockOIDC struct {
+	*httptest.Server
+	mu       sync.Mutex
+	codes    map[string]string
+	identity map[string]interface{}
+}
+
+func newMockOIDC() *mockOIDC {
+	m := &mockOIDC{codes: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/userinfo", m.userinfo)
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockOIDC) Endpoints() oauth.Endpoints {
+	return oauth.Endpoints{
+		AuthURL:     m.URL + "/authorize",
+		TokenURL:    m.URL + "/token",
+		UserInfoURL: m.URL + "/userinfo",
+	}
+}
+
+// issueCode approves a login whose redirect carried challenge and returns the authorization code.
+func (m *mockOIDC) issueCode(challenge string, identity map[string]interface{}) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code := "code-" + challenge[:8]
+	m.codes[code] = challenge
+	m.identity = identity
+	return code
+}
+
+func (m *mockOIDC) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	m.mu.Lock()
+	challenge, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (m *mockOIDC) userinfo(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer mock-access-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.identity)
+}
+
+func setupOAuthTest() {
+	setupLoginTest()
+	testIdentityModel.Clear()
+}
+
+// startOAuthLogin follows /auth/mock/login and returns the state cookie and the redirect's query.
+func startOAuthLogin(t *testing.T) (*http.Cookie, url.Values) {
+	req, _ := http.NewRequest("GET", "/auth/mock/login", nil)
+	recorder := httptest.NewRecorder()
+	testOAuthRouter.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("login returned wrong status code: got %v want %v", recorder.Code, http.StatusFound)
+	}
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected a state cookie, got %d cookies", len(cookies))
+	}
+	return cookies[0], location.Query()
+}
+
+func oauthCallback(cookie *http.Cookie, code, state string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/auth/mock/callback?code="+url.QueryEscape(code)+"&state="+url.QueryEscape(state), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	testOAuthRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestOAuthLoginRedirectsWithPKCE(t *testing.T) {
+	setupOAuthTest()
+	cookie, query := startOAuthLogin(t)
+
+	if query.Get("state") == "" || query.Get("state") != cookie.Value {
+		t.Error("Redirect state should match the state cookie")
+	}
+	if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		t.Error("Redirect should carry an S256 PKCE challenge")
+	}
+	if query.Get("client_id") != "codeck-client" {
+		t.Errorf("Unexpected client_id %q", query.Get("client_id"))
+	}
+}
+
+func TestOAuthCallbackCreatesUser(t *testing.T) {
+	setupOAuthTest()
+	cookie, query := startOAuthLogin(t)
+	code := testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-42",
+		"email":          "octocat@example.com",
+		"email_verified": true,
+		"name":           "Octo Cat",
+	})
+
+	recorder := oauthCallback(cookie, code, query.Get("state"))
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v (%s)", status, http.StatusOK, recorder.Body.String())
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	token, _ := response["token"].(string)
+	claims, err := testTokenManager.ParseAccessToken(token)
+	if err != nil {
+		t.Fatalf("Callback token failed verification: %v", err)
+	}
+
+	created, exists := testUserModel.GetUserByEmail("octocat@example.com")
+	if !exists || created.ID != claims.UserID {
+		t.Fatal("Expected a new user for the external identity")
+	}
+	if created.Name != "Octo Cat" || created.EmailVerifiedAt == nil {
+		t.Error("New user should take the provider's name and verified email")
+	}
+
+	// Logging in again uses the same account
+	cookie, query = startOAuthLogin(t)
+	code = testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-42",
+		"email":          "renamed@example.com",
+		"email_verified": true,
+	})
+	recorder = oauthCallback(cookie, code, query.Get("state"))
+	json.NewDecoder(recorder.Body).Decode(&response)
+	claims, err = testTokenManager.ParseAccessToken(response["token"].(string))
+	if err != nil || claims.UserID != created.ID {
+		t.Error("Second login should sign in the linked user")
+	}
+}
+
+func TestOAuthCallbackLinksExistingUser(t *testing.T) {
+	setupOAuthTest()
+	existing, _ := testUserModel.GetUserByEmail("user@example.com")
+	testUserModel.MarkEmailVerified(existing.ID)
+
+	cookie, query := startOAuthLogin(t)
+	code := testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-7",
+		"email":          "user@example.com",
+		"email_verified": true,
+	})
+
+	recorder := oauthCallback(cookie, code, query.Get("state"))
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	linked, exists := testIdentityModel.GetIdentity("mock", "mock-7")
+	if !exists || linked.UserID != existing.ID {
+		t.Error("Identity should be linked to the user with the same email")
+	}
+}
+
+func TestOAuthCallbackRequiresVerifiedEmail(t *testing.T) {
+	setupOAuthTest()
+
+	// An unverified address must not take over the existing account
+	cookie, query := startOAuthLogin(t)
+	code := testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-8",
+		"email":          "user@example.com",
+		"email_verified": false,
+	})
+
+	recorder := oauthCallback(cookie, code, query.Get("state"))
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+	if _, exists := testIdentityModel.GetIdentity("mock", "mock-8"); exists {
+		t.Error("Identity with unverified email should not be linked")
+	}
+}
+
+func TestOAuthCallbackRefusesUnverifiedExistingUser(t *testing.T) {
+	setupOAuthTest()
+
+	// Anyone could have signed up with the address, so the account's owner is not proven
+	cookie, query := startOAuthLogin(t)
+	code := testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-12",
+		"email":          "user@example.com",
+		"email_verified": true,
+	})
+
+	recorder := oauthCallback(cookie, code, query.Get("state"))
+	if status := recorder.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+	if _, exists := testIdentityModel.GetIdentity("mock", "mock-12"); exists {
+		t.Error("Identity should not be linked to an unverified account")
+	}
+	if existing, _ := testUserModel.GetUserByEmail("user@example.com"); existing.EmailVerifiedAt != nil {
+		t.Error("Refused login should not verify the account's email")
+	}
+}
+
+func TestOAuthCallbackRejectsBadState(t *testing.T) {
+	setupOAuthTest()
+	cookie, query := startOAuthLogin(t)
+	identity := map[string]interface{}{"sub": "mock-9", "email": "x@example.com", "email_verified": true}
+
+	// Missing cookie: the callback did not come from the browser that started the login
+	code := testOIDC.issueCode(query.Get("code_challenge"), identity)
+	if status := oauthCallback(nil, code, query.Get("state")).Code; status != http.StatusBadRequest {
+		t.Errorf("Missing cookie returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	// State the server never issued
+	forged := &http.Cookie{Name: cookie.Name, Value: "forged-state"}
+	if status := oauthCallback(forged, code, "forged-state").Code; status != http.StatusBadRequest {
+		t.Errorf("Forged state returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	// A state can only be used once
+	if status := oauthCallback(cookie, code, query.Get("state")).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if status := oauthCallback(cookie, code, query.Get("state")).Code; status != http.StatusBadRequest {
+		t.Errorf("Reused state returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestOAuthCallbackRejectsWrongVerifier(t *testing.T) {
+	setupOAuthTest()
+	cookie, query := startOAuthLogin(t)
+
+	// Code issued for a different PKCE challenge, as if intercepted from another login
+	code := testOIDC.issueCode("a-challenge-from-another-login", map[string]interface{}{"sub": "mock-10", "email": "y@example.com", "email_verified": true})
+	if status := oauthCallback(cookie, code, query.Get("state")).Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestOAuthUnknownProvider(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/auth/nope/login", nil)
+	recorder := httptest.NewRecorder()
+	testOAuthRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestOAuthCallbackRequiresSecondFactor(t *testing.T) {
+	setupOAuthTest()
+	enableTOTP(t)
+	existing, _ := testUserModel.GetUserByEmail("user@example.com")
+	testUserModel.MarkEmailVerified(existing.ID)
+
+	cookie, query := startOAuthLogin(t)
+	code := testOIDC.issueCode(query.Get("code_challenge"), map[string]interface{}{
+		"sub":            "mock-11",
+		"email":          "user@example.com",
+		"email_verified": true,
+	})
+
+	recorder := oauthCallback(cookie, code, query.Get("state"))
+	if status := recorder.Code; status != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+
+	var response map[string]interface{}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response["challenge"] == nil || response["token"] != nil {
+		t.Error("External login should also require the second factor")
+	}
+}
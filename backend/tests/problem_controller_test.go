@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend/models/activity"
+	"backend/models/problem"
+)
+
+func setupProblemTest() {
+	setupActivityTest()
+	setupGroupTest()
+	testProblemModel.Clear()
+	testGroupModel.AddUserToGroup(1, 2)
+}
+
+func problemRequest(path string, requesterID int) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if requesterID != 0 {
+		authorize(req, requesterID)
+	}
+	recorder := httptest.NewRecorder()
+	testProblemRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func logSolve(t *testing.T, requesterID int, problemURL, date string, verdict string) activity.Activity {
+	t.Helper()
+	payload := map[string]interface{}{"title": "Solved it", "date": date, "problem_url": problemURL}
+	if verdict != "" {
+		payload["verdict"] = verdict
+	}
+	recorder := postActivity(requesterID, payload)
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusCreated, recorder.Body.String())
+	}
+	var created activity.Activity
+	json.NewDecoder(recorder.Body).Decode(&created)
+	return created
+}
+
+func TestActivitiesShareCatalogProblem(t *testing.T) {
+	setupProblemTest()
+
+	first := logSolve(t, 1, "https://codeforces.com/problemset/problem/71/A", "2025-06-02", "")
+	second := logSolve(t, 2, "https://codeforces.com/contest/71/problem/A", "2025-06-03", activity.VerdictAccepted)
+	if first.CatalogProblemID == nil || second.CatalogProblemID == nil || *first.CatalogProblemID != *second.CatalogProblemID {
+		t.Fatalf("Expected both activities to link to the same problem, got %v and %v", first.CatalogProblemID, second.CatalogProblemID)
+	}
+	p, exists := testProblemModel.GetProblemByID(*first.CatalogProblemID)
+	if !exists || p.Key != "codeforces:71A" || p.Judge != activity.JudgeCodeforces || p.ExternalID != "71A" || p.URL != "https://codeforces.com/problemset/problem/71/A" {
+		t.Errorf("Unexpected catalog problem: %+v", p)
+	}
+	// Users' own titles are not problem names
+	if p.Name != "" {
+		t.Errorf("Expected no name from a logged activity, got %q", p.Name)
+	}
+
+	if unidentified := logSolve(t, 1, "https://example.com/problems/71A", "2025-06-02", ""); unidentified.CatalogProblemID != nil {
+		t.Errorf("Expected an unrecognised problem not to be linked, got %v", *unidentified.CatalogProblemID)
+	}
+
+	// Changing the problem moves the activity to the other problem
+	body, _ := json.Marshal(map[string]interface{}{"problem_url": "https://codeforces.com/problemset/problem/4/A", "problem_id": "4A"})
+	req, _ := http.NewRequest("PUT", "/activities/"+strconv.Itoa(first.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	authorize(req, 1)
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	updated, _ := testActivityModel.GetActivityByID(first.ID)
+	if updated.ProblemKey == nil || *updated.ProblemKey != "codeforces:4A" {
+		t.Errorf("Expected problem key codeforces:4A, got %v", updated.ProblemKey)
+	}
+	if updated.CatalogProblemID == nil || *updated.CatalogProblemID == *second.CatalogProblemID {
+		t.Errorf("Expected the activity to link to problem 4A, got %v", updated.CatalogProblemID)
+	}
+}
+
+func TestEnsureProblemFillsInMissingFields(t *testing.T) {
+	setupProblemTest()
+
+	created, ok := testProblemModel.EnsureProblem(problem.Problem{Judge: activity.JudgeCodeforces, ExternalID: "4A", Key: "codeforces:4A"})
+	if !ok {
+		t.Fatal("Failed to create problem")
+	}
+	rating := 800
+	filled, _ := testProblemModel.EnsureProblem(problem.Problem{Judge: activity.JudgeCodeforces, ExternalID: "4A", Key: "codeforces:4A", Name: "Watermelon", Rating: &rating, Tags: activity.Tags{"math"}})
+	otherRating := 900
+	kept, _ := testProblemModel.EnsureProblem(problem.Problem{Judge: activity.JudgeCodeforces, ExternalID: "4A", Key: "codeforces:4A", Name: "Melon", Rating: &otherRating})
+
+	stored, _ := testProblemModel.GetProblemByKey("codeforces:4A")
+	for _, p := range []problem.Problem{filled, kept, stored} {
+		if p.ID != created.ID || p.Name != "Watermelon" || p.Rating == nil || *p.Rating != 800 || len(p.Tags) != 1 {
+			t.Errorf("Expected problem %d named Watermelon rated 800 with one tag, got %+v", created.ID, p)
+		}
+	}
+}
+
+func TestGetProblemSolvers(t *testing.T) {
+	setupProblemTest()
+
+	// User 3 shares no group with user 1, so their solve is not shown to them
+	logSolve(t, 3, "https://codeforces.com/problemset/problem/71/A", "2025-05-01", "")
+	solve := logSolve(t, 1, "https://codeforces.com/problemset/problem/71/A", "2025-06-02", "")
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/71/A", "2025-06-01", activity.VerdictWrongAnswer)
+	firstSolve := logSolve(t, 2, "https://codeforces.com/problemset/problem/71/A", "2025-06-01", "")
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/71/A", "2025-06-05", "")
+	path := "/problems/" + strconv.Itoa(*solve.CatalogProblemID)
+
+	if status := problemRequest(path, 0).Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := problemRequest("/problems/999", 1).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+	if status := problemRequest("/problems/abc", 1).Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	recorder := problemRequest(path, 1)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response struct {
+		Problem     problem.Problem  `json:"problem"`
+		SolveCount  int              `json:"solve_count"`
+		FirstSolver *problem.Solver  `json:"first_solver"`
+		Solvers     []problem.Solver `json:"solvers"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if response.Problem.Key != "codeforces:71A" || response.SolveCount != 2 || len(response.Solvers) != 2 {
+		t.Fatalf("Expected 2 solvers of codeforces:71A, got %+v", response)
+	}
+	if response.Solvers[0].UserID != 2 || response.Solvers[0].ActivityID != firstSolve.ID || response.Solvers[1].UserID != 1 {
+		t.Errorf("Expected user 2 to have solved it first, in activity %d, then user 1, got %+v", firstSolve.ID, response.Solvers)
+	}
+	if response.FirstSolver == nil || response.FirstSolver.UserID != 2 {
+		t.Errorf("Expected user 2 as the first solver, got %+v", response.FirstSolver)
+	}
+}
+
+func TestGetGroupProblems(t *testing.T) {
+	setupProblemTest()
+	testGroupModel.UpdateGroup(1, map[string]interface{}{
+		"start_date": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"end_date":   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+	nickname := "Ace"
+	testGroupModel.SetUserNickname(1, 2, &nickname)
+
+	logSolve(t, 1, "https://codeforces.com/problemset/problem/71/A", "2025-06-02", "")
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/71/A", "2025-06-01", "")
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/4/A", "2025-06-03", "")
+	logSolve(t, 1, "https://atcoder.jp/contests/abc100/tasks/abc100_a", "2025-06-03", activity.VerdictWrongAnswer)
+	logSolve(t, 3, "https://codeforces.com/problemset/problem/1352/A", "2025-06-03", "")
+	// Solves outside the group's dates do not count
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/1352/A", "2024-12-31", "")
+	logSolve(t, 1, "https://codeforces.com/problemset/problem/4/A", "2026-01-01", "")
+
+	if status := problemRequest("/groups/1/problems", 3).Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+	if status := problemRequest("/groups/99/problems", 1).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+
+	recorder := problemRequest("/groups/1/problems", 1)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response struct {
+		Problems []problem.GroupProblem `json:"problems"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if len(response.Problems) != 2 {
+		t.Fatalf("Expected 2 problems solved in the group, got %+v", response.Problems)
+	}
+	most := response.Problems[0]
+	if most.Problem.Key != "codeforces:71A" || most.SolveCount != 2 || most.FirstSolver.UserID != 2 || most.FirstSolver.Name != "Ace" {
+		t.Errorf("Expected codeforces:71A solved by 2 members, first by Ace, got %+v", most)
+	}
+	if least := response.Problems[1]; least.Problem.Key != "codeforces:4A" || least.SolveCount != 1 {
+		t.Errorf("Expected codeforces:4A solved by 1 member, got %+v", least)
+	}
+
+	// Members who leave no longer count
+	testGroupModel.RemoveUserFromGroup(1, 2)
+	recorder = problemRequest("/groups/1/problems", 1)
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if len(response.Problems) != 1 || response.Problems[0].SolveCount != 1 || response.Problems[0].FirstSolver.UserID != 1 {
+		t.Errorf("Expected only user 1's solve, got %+v", response.Problems)
+	}
+}
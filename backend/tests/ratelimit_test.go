@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend/models/user"
+	"backend/ratelimit"
+)
+
+// withRate turns on limiter with rate for the rest of the test and a fresh store, then turns it off again.
+func withRate(t *testing.T, limiter *ratelimit.Limiter, rate ratelimit.Rate) {
+	previousRate, previousStore, previousNow := limiter.Rate, limiter.Store, limiter.Now
+	limiter.Rate = rate
+	limiter.Store = ratelimit.NewMemoryStore()
+	t.Cleanup(func() {
+		limiter.Rate, limiter.Store, limiter.Now = previousRate, previousStore, previousNow
+	})
+}
+
+func attemptLogin(password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{"email": "user@example.com", "password": password})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.7:51234"
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func retryAfter(t *testing.T, recorder *httptest.ResponseRecorder) int {
+	seconds, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("Expected a Retry-After header, got %q", recorder.Header().Get("Retry-After"))
+	}
+	return seconds
+}
+
+func TestLoginRateLimitPerAccount(t *testing.T) {
+	setupLoginTest()
+	withRate(t, testLimits.LoginPerAccount, ratelimit.Rate{Burst: 2, Per: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if status := attemptLogin("wrong").Code; status != http.StatusUnauthorized {
+			t.Fatalf("attempt %d returned wrong status code: got %v want %v", i+1, status, http.StatusUnauthorized)
+		}
+	}
+
+	recorder := attemptLogin("password123")
+	if status := recorder.Code; status != http.StatusTooManyRequests {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+	if seconds := retryAfter(t, recorder); seconds < 1 || seconds > 30 {
+		t.Errorf("Expected Retry-After of at most half a minute, got %d", seconds)
+	}
+}
+
+func TestLoginRateLimitRefills(t *testing.T) {
+	setupLoginTest()
+	withRate(t, testLimits.LoginPerIP, ratelimit.Rate{Burst: 1, Per: time.Minute})
+	now := time.Now()
+	testLimits.LoginPerIP.Now = func() time.Time { return now }
+
+	if status := attemptLogin("password123").Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if status := attemptLogin("password123").Code; status != http.StatusTooManyRequests {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+
+	// One token is back after a minute
+	now = now.Add(time.Minute)
+	if status := attemptLogin("password123").Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code after refill: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	setupLoginTest()
+
+	for i := 0; i < user.LockoutThreshold; i++ {
+		attemptLogin("wrong")
+	}
+
+	// Locked even with the right password
+	recorder := attemptLogin("password123")
+	if status := recorder.Code; status != http.StatusTooManyRequests {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+	if seconds := retryAfter(t, recorder); seconds < 25 || seconds > 30 {
+		t.Errorf("Expected the first lockout to last about 30s, got %d", seconds)
+	}
+
+	// Another failure once the lock runs out doubles the lockout
+	testDB.Model(&user.User{}).Where("email = ?", "user@example.com").Update("locked_until", time.Now().Add(-time.Second))
+	attemptLogin("wrong")
+	recorder = attemptLogin("password123")
+	if seconds := retryAfter(t, recorder); seconds < 55 || seconds > 60 {
+		t.Errorf("Expected the second lockout to last about 60s, got %d", seconds)
+	}
+
+	// A successful login after the lock resets the count
+	testDB.Model(&user.User{}).Where("email = ?", "user@example.com").Update("locked_until", time.Now().Add(-time.Second))
+	if status := attemptLogin("password123").Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	attemptLogin("wrong")
+	if status := attemptLogin("password123").Code; status != http.StatusOK {
+		t.Errorf("A single failure after a reset should not lock: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestSignupRateLimit(t *testing.T) {
+	setupUserTest()
+	withRate(t, testLimits.SignupPerIP, ratelimit.Rate{Burst: 1, Per: time.Hour})
+
+	signup := func(email string) int {
+		body, _ := json.Marshal(map[string]interface{}{"email": email, "name": "Spammer", "password": "password123"})
+		req, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.7:51234"
+		recorder := httptest.NewRecorder()
+		testUserRouter.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if status := signup("first@example.com"); status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if status := signup("second@example.com"); status != http.StatusTooManyRequests {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+}
+
+func TestCommentRateLimitPerUser(t *testing.T) {
+	setupCommentTest()
+	withRate(t, testLimits.Comments, ratelimit.Rate{Burst: 2, Per: time.Minute})
+
+	comment := func(userID int) int {
+		body, _ := json.Marshal(map[string]interface{}{"content": "first!"})
+		req, _ := http.NewRequest("POST", "/activities/1/comments", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		authorize(req, userID)
+		recorder := httptest.NewRecorder()
+		testCommentRouter.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if status := comment(2); status != http.StatusCreated {
+			t.Fatalf("comment %d returned wrong status code: got %v want %v", i+1, status, http.StatusCreated)
+		}
+	}
+	if status := comment(2); status != http.StatusTooManyRequests {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+
+	// Other users have their own bucket
+	if status := comment(1); status != http.StatusCreated {
+		t.Errorf("handler returned wrong status code for another user: got %v want %v", status, http.StatusCreated)
+	}
+}
+
+func TestActivityRateLimit(t *testing.T) {
+	setupActivityTest()
+	withRate(t, testLimits.Activities, ratelimit.Rate{Burst: 1, Per: time.Minute})
+
+	create := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"title": "Spam", "date": "2025-12-31"})
+		req, _ := http.NewRequest("POST", "/activities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		authorize(req, 1)
+		recorder := httptest.NewRecorder()
+		testActivityRouter.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if status := create().Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	recorder := create()
+	if status := recorder.Code; status != http.StatusTooManyRequests {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+	}
+	if seconds := retryAfter(t, recorder); seconds < 55 || seconds > 60 {
+		t.Errorf("Expected Retry-After of about a minute, got %d", seconds)
+	}
+}
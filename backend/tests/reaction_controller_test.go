@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/models/reaction"
+)
+
+func setupReactionTest() {
+	setupActivityTest()
+	testReactionModel.Clear()
+}
+
+func react(method, path string, requesterID int) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	if requesterID != 0 {
+		authorize(req, requesterID)
+	}
+	recorder := httptest.NewRecorder()
+	testReactionRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeReactions(t *testing.T, recorder *httptest.ResponseRecorder) []reaction.Summary {
+	t.Helper()
+	var response struct {
+		Reactions []reaction.Summary `json:"reactions"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body:", err)
+	}
+	return response.Reactions
+}
+
+func TestAddReaction(t *testing.T) {
+	setupReactionTest()
+
+	react("PUT", "/activities/1/reactions/fire", 2)
+	react("PUT", "/activities/1/reactions/like", 2)
+	// Reacting twice with the same kind counts once
+	react("PUT", "/activities/1/reactions/fire", 3)
+	recorder := react("PUT", "/activities/1/reactions/fire", 3)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	want := []reaction.Summary{
+		{Kind: reaction.KindLike, Count: 1, Reacted: false},
+		{Kind: reaction.KindFire, Count: 2, Reacted: true},
+	}
+	got := decodeReactions(t, recorder)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], got[i])
+		}
+	}
+
+	if status := react("GET", "/activities/1/reactions", 0).Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+func TestAddReactionInvalid(t *testing.T) {
+	setupReactionTest()
+
+	cases := []struct {
+		path        string
+		requesterID int
+		status      int
+	}{
+		{"/activities/1/reactions/heart_eyes", 2, http.StatusBadRequest},
+		{"/activities/999/reactions/fire", 2, http.StatusNotFound},
+		{"/activities/1/reactions/fire", 0, http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		if status := react("PUT", c.path, c.requesterID).Code; status != c.status {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", c.path, status, c.status)
+		}
+	}
+}
+
+func TestRemoveReaction(t *testing.T) {
+	setupReactionTest()
+	react("PUT", "/activities/1/reactions/clap", 2)
+
+	if status := react("DELETE", "/activities/1/reactions/clap", 3).Code; status != http.StatusNotFound {
+		t.Errorf("removed another user's reaction: got %v want %v", status, http.StatusNotFound)
+	}
+
+	recorder := react("DELETE", "/activities/1/reactions/clap", 2)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if got := decodeReactions(t, recorder); len(got) != 0 {
+		t.Errorf("Expected no reactions left, got %+v", got)
+	}
+}
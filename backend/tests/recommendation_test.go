@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"backend/models/activity"
+	"backend/models/problem"
+)
+
+func catalogProblem(contest string, rating int, tags ...string) problem.Problem {
+	p := problem.Problem{
+		Judge:      activity.JudgeCodeforces,
+		ExternalID: contest + "A",
+		Key:        "codeforces:" + contest + "A",
+		Name:       "Problem " + contest + "A",
+		URL:        "https://codeforces.com/problemset/problem/" + contest + "/A",
+		Tags:       tags,
+	}
+	if rating > 0 {
+		p.Rating = &rating
+	}
+	return p
+}
+
+func logRatedSolve(t *testing.T, requesterID int, contest string, rating int, tags ...string) {
+	t.Helper()
+	payload := map[string]interface{}{
+		"title":       "Solved it",
+		"date":        "2025-06-02",
+		"problem_url": "https://codeforces.com/problemset/problem/" + contest + "/A",
+		"rating":      rating,
+		"tags":        tags,
+	}
+	if status := postActivity(requesterID, payload).Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+}
+
+type recommendationsResponse struct {
+	EstimatedRating int                      `json:"estimated_rating"`
+	Seed            int64                    `json:"seed"`
+	Recommendations []problem.Recommendation `json:"recommendations"`
+}
+
+func getRecommendations(t *testing.T, query string) recommendationsResponse {
+	t.Helper()
+	recorder := problemRequest("/users/me/recommendations"+query, 1)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v: %s", status, http.StatusOK, recorder.Body.String())
+	}
+	var response recommendationsResponse
+	json.NewDecoder(recorder.Body).Decode(&response)
+	return response
+}
+
+func recommendedKeys(response recommendationsResponse) []string {
+	keys := []string{}
+	for _, r := range response.Recommendations {
+		keys = append(keys, r.Problem.Key)
+	}
+	return keys
+}
+
+func setupRecommendationTest(t *testing.T) {
+	setupProblemTest()
+	_, err := testProblemModel.ImportProblems([]problem.Problem{
+		catalogProblem("1", 1200, "math"),
+		catalogProblem("2", 1400, "math"),
+		catalogProblem("3", 1400, "math"),
+		catalogProblem("4", 1400, "graphs"),
+		catalogProblem("5", 1400, "math"),
+		catalogProblem("6", 2500, "graphs"),
+		catalogProblem("7", 0, "graphs"),
+	}, 500)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	// User 1 solved two math problems, so they are rated 1300 and weak at graphs
+	logRatedSolve(t, 1, "1", 1200, "math")
+	logRatedSolve(t, 1, "2", 1400, "math")
+	// Failed attempts leave a problem unsolved
+	logSolve(t, 1, "https://codeforces.com/problemset/problem/3/A", "2025-06-03", activity.VerdictWrongAnswer)
+	// User 2 is in a group with user 1, user 3 is not
+	logSolve(t, 2, "https://codeforces.com/problemset/problem/5/A", "2025-06-03", "")
+	logSolve(t, 3, "https://codeforces.com/problemset/problem/3/A", "2025-06-03", "")
+}
+
+func TestGetRecommendations(t *testing.T) {
+	setupRecommendationTest(t)
+
+	response := getRecommendations(t, "?seed=42")
+	if response.EstimatedRating != 1300 || response.Seed != 42 {
+		t.Errorf("Expected rating 1300 and seed 42, got %d and %d", response.EstimatedRating, response.Seed)
+	}
+	// The weak tag beats a groupmate's solve, which beats neither
+	keys := recommendedKeys(response)
+	if len(keys) != 3 || keys[0] != "codeforces:4A" || keys[1] != "codeforces:5A" || keys[2] != "codeforces:3A" {
+		t.Fatalf("Expected codeforces:4A, 5A and 3A, got %v", keys)
+	}
+	if response.Recommendations[1].GroupmateSolves != 1 || response.Recommendations[2].GroupmateSolves != 0 {
+		t.Errorf("Expected only user 2's solve to count, got %+v", response.Recommendations)
+	}
+
+	if keys := recommendedKeys(getRecommendations(t, "?seed=42&count=2")); len(keys) != 2 || keys[0] != "codeforces:4A" {
+		t.Errorf("Expected the best 2 recommendations, got %v", keys)
+	}
+	if keys := recommendedKeys(getRecommendations(t, "?seed=42&tags=Graphs,dp")); len(keys) != 1 || keys[0] != "codeforces:4A" {
+		t.Errorf("Expected only codeforces:4A with graphs or dp, got %v", keys)
+	}
+	if daily := getRecommendations(t, ""); daily.Seed == 0 || len(daily.Recommendations) != 3 {
+		t.Errorf("Expected a default seed and 3 recommendations, got %+v", daily)
+	}
+}
+
+func TestRecommendationsAreDeterministic(t *testing.T) {
+	setupRecommendationTest(t)
+
+	first := getRecommendations(t, "?seed=7")
+	second := getRecommendations(t, "?seed=7")
+	if len(first.Recommendations) != len(second.Recommendations) {
+		t.Fatalf("Expected the same recommendations, got %v and %v", recommendedKeys(first), recommendedKeys(second))
+	}
+	for i := range first.Recommendations {
+		if first.Recommendations[i].Problem.ID != second.Recommendations[i].Problem.ID || first.Recommendations[i].Score != second.Recommendations[i].Score {
+			t.Errorf("Expected the same recommendation %d for the same seed, got %+v and %+v", i, first.Recommendations[i], second.Recommendations[i])
+		}
+	}
+
+	// Without rated solves, new users get problems near the easiest rating
+	history := []activity.Activity{}
+	candidates := []problem.Problem{catalogProblem("1", 800), catalogProblem("2", 800), catalogProblem("3", 800)}
+	for i := range candidates {
+		candidates[i].ID = i + 1
+	}
+	if rating := problem.EstimateRating(history); rating != 800 {
+		t.Errorf("Expected a default rating of 800, got %d", rating)
+	}
+	reversed := []problem.Problem{candidates[2], candidates[1], candidates[0]}
+	for seed := int64(0); seed < 5; seed++ {
+		a := problem.Recommend(history, candidates, nil, 3, seed)
+		b := problem.Recommend(history, reversed, nil, 3, seed)
+		for i := range a {
+			if a[i].Problem.ID != b[i].Problem.ID {
+				t.Errorf("Expected the order of candidates not to matter for seed %d, got %+v and %+v", seed, a, b)
+				break
+			}
+		}
+	}
+}
+
+func TestGetRecommendationsInvalid(t *testing.T) {
+	setupRecommendationTest(t)
+
+	if status := problemRequest("/users/me/recommendations", 0).Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	for _, query := range []string{"?count=0", "?count=51", "?count=many", "?seed=abc", "?tags=dp,,greedy"} {
+		if status := problemRequest("/users/me/recommendations"+query, 1).Code; status != http.StatusBadRequest {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", query, status, http.StatusBadRequest)
+		}
+	}
+}
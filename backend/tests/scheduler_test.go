@@ -0,0 +1,303 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/judge"
+	"backend/models/activity"
+	"backend/models/linkedaccount"
+)
+
+// testSchedulerConfig has no jitter, so tests can tell exactly when accounts are due.
+func testSchedulerConfig() judge.SchedulerConfig {
+	config := judge.DefaultSchedulerConfig()
+	config.Jitter = 0
+	return config
+}
+
+// stubFetcher counts the imports running at once. Imports wait for release when it is set and
+// fail with err when it is set, and otherwise return submissions.
+type stubFetcher struct {
+	judge       string
+	release     chan struct{}
+	err         error
+	submissions []judge.Submission
+
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (f *stubFetcher) Judge() string {
+	return f.judge
+}
+
+func (f *stubFetcher) ResolveHandle(ctx context.Context, handle string) (string, error) {
+	return handle, nil
+}
+
+func (f *stubFetcher) Submissions(ctx context.Context, handle, cursor string) ([]judge.Submission, string, error) {
+	f.mu.Lock()
+	f.running++
+	f.peak = max(f.peak, f.running)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	if f.release != nil {
+		<-f.release
+	}
+	if f.err != nil {
+		return nil, cursor, f.err
+	}
+	return f.submissions, "1", nil
+}
+
+func (f *stubFetcher) counts() (running, peak int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.running, f.peak
+}
+
+// failingActivityModel fails to create the activity for submission failID.
+type failingActivityModel struct {
+	*activity.GormActivityModel
+	failID string
+}
+
+func (m *failingActivityModel) CreateActivity(a activity.Activity) activity.Activity {
+	if a.SubmissionID != nil && *a.SubmissionID == m.failID {
+		return activity.Activity{}
+	}
+	return m.GormActivityModel.CreateActivity(a)
+}
+
+func newStubScheduler(config judge.SchedulerConfig, fetchers ...judge.Fetcher) *judge.Scheduler {
+	importer := judge.NewImporter(testLinkedAccountModel, testActivityModel, testGroupModel, testUserModel, testProblemModel, fetchers...)
+	return judge.NewScheduler(importer, testLinkedAccountModel, config)
+}
+
+func saveDueAccount(t *testing.T, userID int, judgeName, handle string) linkedaccount.LinkedAccount {
+	now := time.Now()
+	account, ok := testLinkedAccountModel.SaveAccount(linkedaccount.LinkedAccount{UserID: userID, Judge: judgeName, Handle: handle, NextSyncAt: &now})
+	if !ok {
+		t.Fatalf("Failed to save %s account %q", judgeName, handle)
+	}
+	return account
+}
+
+func assertAround(t *testing.T, name string, got *time.Time, want time.Time) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("Expected %s around %v, got nil", name, want)
+	} else if d := got.Sub(want); d < -5*time.Second || d > 5*time.Second {
+		t.Errorf("Expected %s around %v, got %v", name, want, *got)
+	}
+}
+
+func TestSchedulerLimitsConcurrencyPerJudge(t *testing.T) {
+	setupLinkedAccountTest()
+	codeforces := &stubFetcher{judge: activity.JudgeCodeforces, release: make(chan struct{})}
+	atcoder := &stubFetcher{judge: activity.JudgeAtCoder, release: make(chan struct{})}
+	scheduler := newStubScheduler(testSchedulerConfig(), codeforces, atcoder)
+
+	var accounts []linkedaccount.LinkedAccount
+	for userID := 1; userID <= 3; userID++ {
+		accounts = append(accounts, saveDueAccount(t, userID, activity.JudgeCodeforces, "user"+strconv.Itoa(userID)))
+	}
+	saveDueAccount(t, 1, activity.JudgeAtCoder, "user1")
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.SyncDue(context.Background())
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cf, _ := codeforces.counts()
+		ac, _ := atcoder.counts()
+		if cf == 2 && ac == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 2 codeforces and 1 atcoder import running, got %d and %d", cf, ac)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The third codeforces account waits for a slot instead of starting
+	time.Sleep(100 * time.Millisecond)
+	if cf, _ := codeforces.counts(); cf != 2 {
+		t.Errorf("Expected 2 codeforces imports at once, got %d", cf)
+	}
+	for _, account := range accounts {
+		if !scheduler.Syncing(account.ID) {
+			t.Errorf("Expected account %d to be syncing", account.ID)
+		}
+	}
+
+	close(codeforces.release)
+	close(atcoder.release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SyncDue did not return")
+	}
+	if _, peak := codeforces.counts(); peak != 2 {
+		t.Errorf("Expected at most 2 codeforces imports at once, got %d", peak)
+	}
+	for _, account := range accounts {
+		if scheduler.Syncing(account.ID) {
+			t.Errorf("Expected account %d to be done syncing", account.ID)
+		}
+	}
+	if due := testLinkedAccountModel.GetDueAccounts(time.Now()); len(due) != 0 {
+		t.Errorf("Expected no accounts due after syncing, got %d", len(due))
+	}
+}
+
+func TestSchedulerBacksOffOnFailure(t *testing.T) {
+	setupLinkedAccountTest()
+	fetcher := &stubFetcher{judge: activity.JudgeCodeforces, err: errors.New("codeforces is down")}
+	config := testSchedulerConfig()
+	config.Backoff = time.Minute
+	config.MaxBackoff = 4 * time.Minute
+	scheduler := newStubScheduler(config, fetcher)
+	account := saveDueAccount(t, 1, activity.JudgeCodeforces, "tourist")
+
+	for i, wait := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		scheduler.SyncDue(context.Background())
+		account, _ = testLinkedAccountModel.GetAccount(1, activity.JudgeCodeforces)
+		if account.Failures != i+1 {
+			t.Errorf("Expected %d failures, got %d", i+1, account.Failures)
+		}
+		if account.LastError == nil || *account.LastError != "codeforces is down" || account.LastErrorAt == nil {
+			t.Errorf("Expected the last error to be recorded, got %v", account.LastError)
+		}
+		assertAround(t, "next sync", account.NextSyncAt, time.Now().Add(wait))
+
+		// Not due again until the backoff is over
+		scheduler.SyncDue(context.Background())
+		if again, _ := testLinkedAccountModel.GetAccount(1, activity.JudgeCodeforces); again.Failures != i+1 {
+			t.Errorf("Expected no import before the backoff is over, got %d failures", again.Failures)
+		}
+		now := time.Now()
+		testLinkedAccountModel.SetNextSync(account.ID, account.Handle, &now)
+	}
+
+	fetcher.err = nil
+	scheduler.SyncDue(context.Background())
+	account, _ = testLinkedAccountModel.GetAccount(1, activity.JudgeCodeforces)
+	if account.Failures != 0 || account.Cursor != "1" {
+		t.Errorf("Expected a successful import to reset failures and move the cursor, got %d failures and cursor %q", account.Failures, account.Cursor)
+	}
+	assertAround(t, "last success", account.LastImportedAt, time.Now())
+	assertAround(t, "next sync", account.NextSyncAt, time.Now().Add(config.Interval))
+}
+
+func getSyncStatus(t *testing.T, requesterID int) []linkedaccount.SyncStatus {
+	t.Helper()
+	recorder := linkedAccountRequest("GET", "/users/me/sync-status", requesterID, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response struct {
+		Accounts []linkedaccount.SyncStatus `json:"accounts"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	return response.Accounts
+}
+
+func TestSyncEndpoints(t *testing.T) {
+	setupLinkedAccountTest()
+
+	if status := linkedAccountRequest("POST", "/users/me/sync", 0, nil).Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if status := linkedAccountRequest("POST", "/users/me/sync", 1, nil).Code; status != http.StatusNotFound {
+		t.Errorf("no accounts: handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+	if statuses := getSyncStatus(t, 1); len(statuses) != 0 {
+		t.Errorf("Expected no sync status without accounts, got %v", statuses)
+	}
+
+	if status := linkAccount(1, "codeforces", map[string]interface{}{"handle": "tourist"}).Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	statuses := getSyncStatus(t, 1)
+	if len(statuses) != 1 || statuses[0].Judge != "codeforces" || statuses[0].Handle != "tourist" || statuses[0].LastSuccessAt != nil {
+		t.Fatalf("Expected a codeforces account not synced yet, got %+v", statuses)
+	}
+	// Linked accounts are due right away
+	assertAround(t, "next sync", statuses[0].NextSyncAt, time.Now())
+
+	testScheduler.SyncDue(context.Background())
+	statuses = getSyncStatus(t, 1)
+	assertAround(t, "last success", statuses[0].LastSuccessAt, time.Now())
+	assertAround(t, "next sync", statuses[0].NextSyncAt, time.Now().Add(testSchedulerConfig().Interval))
+	if statuses[0].Failures != 0 || statuses[0].LastError != nil || statuses[0].Syncing {
+		t.Errorf("Expected a clean sync status, got %+v", statuses[0])
+	}
+
+	// Just synced, so a manual sync leaves the schedule alone
+	recorder := linkedAccountRequest("POST", "/users/me/sync", 1, nil)
+	if status := recorder.Code; status != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+	var triggered struct {
+		Accounts []linkedaccount.SyncStatus `json:"accounts"`
+	}
+	json.NewDecoder(recorder.Body).Decode(&triggered)
+	if len(triggered.Accounts) != 1 {
+		t.Fatalf("Expected 1 account, got %d", len(triggered.Accounts))
+	}
+	assertAround(t, "next sync", triggered.Accounts[0].NextSyncAt, time.Now().Add(testSchedulerConfig().Interval))
+
+	account, _ := testLinkedAccountModel.GetAccount(1, "codeforces")
+	testLinkedAccountModel.SetCursor(account.ID, account.Handle, account.Cursor, time.Now().Add(-2*time.Minute))
+	if status := linkedAccountRequest("POST", "/users/me/sync", 1, nil).Code; status != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusAccepted)
+	}
+	if due := testLinkedAccountModel.GetDueAccounts(time.Now()); len(due) != 1 || due[0].ID != account.ID {
+		t.Errorf("Expected the account to be due after a manual sync, got %v", due)
+	}
+}
+
+func TestImportKeepsCursorOnFailure(t *testing.T) {
+	setupLinkedAccountTest()
+	fetcher := &stubFetcher{judge: activity.JudgeCodeforces}
+	for i, id := range []string{"4A", "71A"} {
+		p, _ := judge.Canonical(activity.JudgeCodeforces, id)
+		fetcher.submissions = append(fetcher.submissions, judge.Submission{ID: strconv.Itoa(i + 1), Problem: p, SubmittedAt: time.Now()})
+	}
+	account := saveDueAccount(t, 1, activity.JudgeCodeforces, "tourist")
+
+	failing := &failingActivityModel{GormActivityModel: testActivityModel, failID: "2"}
+	importer := judge.NewImporter(testLinkedAccountModel, failing, testGroupModel, testUserModel, testProblemModel, fetcher)
+	if created, err := importer.ImportAccount(context.Background(), account); err == nil || created != 1 {
+		t.Errorf("Expected an error after 1 imported activity, got %d, %v", created, err)
+	}
+	account, _ = testLinkedAccountModel.GetAccount(1, activity.JudgeCodeforces)
+	if account.Cursor != "" {
+		t.Fatalf("Expected the cursor to stay put, got %q", account.Cursor)
+	}
+
+	// The next import picks up the failed submission without importing the other one again
+	importer = judge.NewImporter(testLinkedAccountModel, testActivityModel, testGroupModel, testUserModel, testProblemModel, fetcher)
+	if created, err := importer.ImportAccount(context.Background(), account); err != nil || created != 1 {
+		t.Errorf("Expected the failed submission to be imported, got %d, %v", created, err)
+	}
+	if activities := testActivityModel.GetActivitiesByCreatorID(1); len(activities) != 2 {
+		t.Errorf("Expected 2 imported activities, got %d", len(activities))
+	}
+}
@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/models/activity"
+)
+
+func setupTokenTest() {
+	setupLoginTest()
+	setupActivityTest()
+	testPATModel.Clear()
+}
+
+// createPAT creates a personal access token for userID through the API and returns the secret and its ID.
+func createPAT(t *testing.T, userID int, payload map[string]interface{}) (string, int) {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/users/me/tokens", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	authorize(req, userID)
+
+	recorder := httptest.NewRecorder()
+	testTokenRouter.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("token creation returned wrong status code: got %v want %v (%s)", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+
+	var response struct {
+		Token   string                 `json:"token"`
+		Details map[string]interface{} `json:"details"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	return response.Token, int(response.Details["id"].(float64))
+}
+
+func createActivityWithToken(token string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{
+		"title": "Solved from a script",
+		"date":  "2025-12-31",
+	})
+	req, _ := http.NewRequest("POST", "/activities", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	testActivityRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCreateTokenValid(t *testing.T) {
+	setupTokenTest()
+	token, _ := createPAT(t, 1, map[string]interface{}{
+		"name":   "vim plugin",
+		"scopes": []string{"activities:write", "activities:write"},
+	})
+
+	if !strings.HasPrefix(token, "cdk_") {
+		t.Errorf("Expected token to start with cdk_, got %q", token)
+	}
+
+	// The secret is shown once: listing only returns metadata
+	req, _ := http.NewRequest("GET", "/users/me/tokens", nil)
+	authorize(req, 1)
+	recorder := httptest.NewRecorder()
+	testTokenRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if strings.Contains(recorder.Body.String(), token) {
+		t.Error("Token secret should not be listed")
+	}
+
+	var response map[string][]map[string]interface{}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if len(response["tokens"]) != 1 {
+		t.Fatalf("Expected 1 token, got %d", len(response["tokens"]))
+	}
+	if scopes, _ := response["tokens"][0]["scopes"].([]interface{}); len(scopes) != 1 {
+		t.Errorf("Duplicate scopes should be collapsed, got %v", scopes)
+	}
+}
+
+func TestCreateTokenInvalid(t *testing.T) {
+	setupTokenTest()
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	cases := []map[string]interface{}{
+		{"name": "no scopes"},
+		{"scopes": []string{"groups:read"}},
+		{"name": "bad scope", "scopes": []string{"admin"}},
+		{"name": "expired", "scopes": []string{"groups:read"}, "expires_at": past},
+	}
+
+	for _, payload := range cases {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/users/me/tokens", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		authorize(req, 1)
+
+		recorder := httptest.NewRecorder()
+		testTokenRouter.ServeHTTP(recorder, req)
+		if status := recorder.Code; status != http.StatusBadRequest {
+			t.Errorf("payload %v returned wrong status code: got %v want %v", payload, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestTokenAuthenticatesScopedRoute(t *testing.T) {
+	setupTokenTest()
+	token, _ := createPAT(t, 2, map[string]interface{}{
+		"name":   "solve logger",
+		"scopes": []string{"activities:write"},
+	})
+
+	recorder := createActivityWithToken(token)
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created activity.Activity
+	json.NewDecoder(recorder.Body).Decode(&created)
+	if created.CreatorID != 2 {
+		t.Errorf("Activity should belong to the token owner, got creator %d", created.CreatorID)
+	}
+}
+
+func TestTokenMissingScope(t *testing.T) {
+	setupTokenTest()
+	token, _ := createPAT(t, 1, map[string]interface{}{
+		"name":   "read only",
+		"scopes": []string{"groups:read"},
+	})
+
+	recorder := createActivityWithToken(token)
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+	if challenge := recorder.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, `scope="activities:write"`) {
+		t.Errorf("Expected insufficient_scope challenge, got %q", challenge)
+	}
+}
+
+func TestTokenCannotManageTokens(t *testing.T) {
+	setupTokenTest()
+	token, _ := createPAT(t, 1, map[string]interface{}{
+		"name":   "everything",
+		"scopes": []string{"activities:write", "groups:read", "groups:write", "comments:write"},
+	})
+
+	// Token management needs a login session so a leaked token cannot mint more
+	req, _ := http.NewRequest("GET", "/users/me/tokens", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	testTokenRouter.ServeHTTP(recorder, req)
+
+	if status := recorder.Code; status != http.StatusForbidden {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	setupTokenTest()
+	token, id := createPAT(t, 1, map[string]interface{}{
+		"name":       "short lived",
+		"scopes":     []string{"activities:write"},
+		"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+
+	testDB.Exec("UPDATE personal_access_tokens SET expires_at = ? WHERE id = ?", time.Now().Add(-time.Minute), id)
+
+	if status := createActivityWithToken(token).Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestDeleteToken(t *testing.T) {
+	setupTokenTest()
+	token, id := createPAT(t, 1, map[string]interface{}{
+		"name":   "to delete",
+		"scopes": []string{"activities:write"},
+	})
+
+	// Another user cannot delete it
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/me/tokens/%d", id), nil)
+	authorize(req, 2)
+	recorder := httptest.NewRecorder()
+	testTokenRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/users/me/tokens/%d", id), nil)
+	authorize(req, 1)
+	recorder = httptest.NewRecorder()
+	testTokenRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusNoContent {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+
+	if status := createActivityWithToken(token).Code; status != http.StatusUnauthorized {
+		t.Errorf("Deleted token returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
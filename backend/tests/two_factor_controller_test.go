@@ -0,0 +1,209 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/auth/totp"
+)
+
+func setupTwoFactorTest() {
+	setupLoginTest()
+}
+
+func postTwoFactor(method, path string, payload map[string]interface{}, userID int) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != 0 {
+		authorize(req, userID)
+	}
+
+	recorder := httptest.NewRecorder()
+	testTwoFactorRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	code, err := totp.Code(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// enableTOTP enrolls and confirms TOTP for the seeded user 1 and returns the secret and recovery codes.
+func enableTOTP(t *testing.T) (string, []string) {
+	recorder := postTwoFactor("POST", "/users/me/2fa/totp", nil, 1)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("enrollment returned wrong status code: got %v want %v", recorder.Code, http.StatusOK)
+	}
+	var enrollment map[string]string
+	json.NewDecoder(recorder.Body).Decode(&enrollment)
+	secret := enrollment["secret"]
+
+	recorder = postTwoFactor("POST", "/users/me/2fa/totp/confirm", map[string]interface{}{"code": totpCode(t, secret, time.Now())}, 1)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("confirmation returned wrong status code: got %v want %v", recorder.Code, http.StatusOK)
+	}
+	var confirmation map[string][]string
+	json.NewDecoder(recorder.Body).Decode(&confirmation)
+	return secret, confirmation["recovery_codes"]
+}
+
+// passwordStep logs in as the seeded user with TOTP enabled and returns the challenge.
+func passwordStep(t *testing.T) string {
+	body, _ := json.Marshal(map[string]interface{}{"email": "user@example.com", "password": "password123"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("login returned wrong status code: got %v want %v", recorder.Code, http.StatusAccepted)
+	}
+
+	var response map[string]interface{}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if _, issued := response["token"]; issued {
+		t.Error("No access token should be issued before the second factor")
+	}
+	challenge, _ := response["challenge"].(string)
+	return challenge
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	setupTwoFactorTest()
+
+	recorder := postTwoFactor("POST", "/users/me/2fa/totp", nil, 1)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response map[string]string
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body")
+	}
+	if !strings.HasPrefix(response["otpauth_uri"], "otpauth://totp/CODECK:user@example.com?") {
+		t.Errorf("Unexpected otpauth URI %q", response["otpauth_uri"])
+	}
+	png, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(response["qr_code"], "data:image/png;base64,"))
+	if err != nil || !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Error("Expected a base64 PNG QR code")
+	}
+
+	// Not enabled until confirmed, so login still works with the password alone
+	login(t, "before-confirmation")
+}
+
+func TestConfirmTOTPInvalidCode(t *testing.T) {
+	setupTwoFactorTest()
+	postTwoFactor("POST", "/users/me/2fa/totp", nil, 1)
+
+	recorder := postTwoFactor("POST", "/users/me/2fa/totp/confirm", map[string]interface{}{"code": "000000"}, 1)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestTwoStepLogin(t *testing.T) {
+	setupTwoFactorTest()
+	secret, recoveryCodes := enableTOTP(t)
+	if len(recoveryCodes) != 10 {
+		t.Fatalf("Expected 10 recovery codes, got %d", len(recoveryCodes))
+	}
+
+	challenge := passwordStep(t)
+
+	// The challenge is not an access token
+	req, _ := http.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+challenge)
+	recorder := httptest.NewRecorder()
+	testLoginRouter.ServeHTTP(recorder, req)
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("Challenge used as access token returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	code := totpCode(t, secret, time.Now().Add(totp.Period))
+	recorder = postTwoFactor("POST", "/login/2fa", map[string]interface{}{"challenge": challenge, "code": code}, 0)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response map[string]interface{}
+	json.NewDecoder(recorder.Body).Decode(&response)
+	if _, err := testTokenManager.ParseAccessToken(response["token"].(string)); err != nil {
+		t.Errorf("Expected a valid access token: %v", err)
+	}
+
+	// A code cannot be replayed
+	recorder = postTwoFactor("POST", "/login/2fa", map[string]interface{}{"challenge": passwordStep(t), "code": code}, 0)
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("Replayed code returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestTwoStepLoginWithRecoveryCode(t *testing.T) {
+	setupTwoFactorTest()
+	_, recoveryCodes := enableTOTP(t)
+
+	recorder := postTwoFactor("POST", "/login/2fa", map[string]interface{}{"challenge": passwordStep(t), "code": strings.ToUpper(recoveryCodes[0])}, 0)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	// Recovery codes are single-use
+	recorder = postTwoFactor("POST", "/login/2fa", map[string]interface{}{"challenge": passwordStep(t), "code": recoveryCodes[0]}, 0)
+	if status := recorder.Code; status != http.StatusUnauthorized {
+		t.Errorf("Reused recovery code returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+	if remaining := testUserModel.CountRecoveryCodes(1); remaining != 9 {
+		t.Errorf("Expected 9 unused recovery codes, got %d", remaining)
+	}
+}
+
+func TestTwoStepLoginInvalidChallenge(t *testing.T) {
+	setupTwoFactorTest()
+	secret, _ := enableTOTP(t)
+
+	for _, challenge := range []string{"not-a-jwt", issueSessionToken(t)} {
+		code := totpCode(t, secret, time.Now().Add(totp.Period))
+		recorder := postTwoFactor("POST", "/login/2fa", map[string]interface{}{"challenge": challenge, "code": code}, 0)
+		if status := recorder.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	setupTwoFactorTest()
+	secret, _ := enableTOTP(t)
+
+	recorder := postTwoFactor("DELETE", "/users/me/2fa/totp", map[string]interface{}{"code": "123"}, 1)
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	code := totpCode(t, secret, time.Now().Add(totp.Period))
+	recorder = postTwoFactor("DELETE", "/users/me/2fa/totp", map[string]interface{}{"code": code}, 1)
+	if status := recorder.Code; status != http.StatusNoContent {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+
+	// Password alone is enough again
+	if login(t, "after-disable")["token"] == nil {
+		t.Error("Expected an access token after disabling two-factor login")
+	}
+}
+
+// issueSessionToken returns a normal access token for user 1, which must not pass as a challenge.
+func issueSessionToken(t *testing.T) string {
+	req, _ := http.NewRequest("GET", "/", nil)
+	authorize(req, 1)
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
@@ -3,12 +3,16 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"backend/models/activity"
+	"backend/models/user"
 )
 
 func setupUserTest() {
@@ -50,6 +54,11 @@ func TestCreateUserValid(t *testing.T) {
 	if _, exists := createdUser["password"]; exists {
 		t.Error("Password should not be returned in response")
 	}
+
+	stored, _ := testUserModel.GetUserByEmail("newuser@example.com")
+	if stored.Password == "securepassword123" {
+		t.Error("Password should not be stored in plaintext")
+	}
 }
 
 func TestCreateUserInvalid(t *testing.T) {
@@ -176,3 +185,292 @@ func TestGetUserActivitiesUserNotFound(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func userRequest(method, path string, requesterID int, payload interface{}) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	if payload != nil {
+		json.NewEncoder(&body).Encode(payload)
+	}
+	req, _ := http.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	if requesterID != 0 {
+		authorize(req, requesterID)
+	}
+	recorder := httptest.NewRecorder()
+	testUserRouter.ServeHTTP(recorder, req)
+	return recorder
+}
+
+type streakResponse struct {
+	CurrentStreak int        `json:"current_streak"`
+	LongestStreak int        `json:"longest_streak"`
+	LastActiveDay *time.Time `json:"last_active_day"`
+}
+
+func getStreak(t *testing.T, userID int) streakResponse {
+	t.Helper()
+	recorder := userRequest("GET", fmt.Sprintf("/users/%d/streak", userID), 0, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var streak streakResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&streak); err != nil {
+		t.Fatal("Failed to decode response body:", err)
+	}
+	return streak
+}
+
+// logActivitiesOn creates one activity for userID on each day, given as offsets from today (UTC).
+func logActivitiesOn(userID int, offsets ...int) {
+	today := activity.Today(time.Now(), time.UTC)
+	for _, offset := range offsets {
+		testActivityModel.CreateActivity(activity.Activity{
+			CreatorID: userID,
+			Title:     "Daily problem",
+			Date:      today.AddDate(0, 0, offset),
+		})
+	}
+}
+
+func TestGetUserStreak(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	if streak := getStreak(t, 1); streak.CurrentStreak != 0 || streak.LongestStreak != 0 || streak.LastActiveDay != nil {
+		t.Errorf("Expected an empty streak, got %+v", streak)
+	}
+
+	logActivitiesOn(1, -14, -13, -12, -11, -10, -2, -1, 0, 0)
+	logActivitiesOn(2, -3)
+
+	streak := getStreak(t, 1)
+	if streak.CurrentStreak != 3 || streak.LongestStreak != 5 {
+		t.Errorf("Expected current streak 3 and longest 5, got %+v", streak)
+	}
+	today := activity.Today(time.Now(), time.UTC)
+	if streak.LastActiveDay == nil || !streak.LastActiveDay.Equal(today) {
+		t.Errorf("Expected last active day %v, got %v", today, streak.LastActiveDay)
+	}
+
+	if status := userRequest("GET", "/users/999/streak", 0, nil).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestStreakSurvivesUntilTheDayEnds(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	// Nothing logged today yet, but yesterday still counts
+	logActivitiesOn(1, -2, -1)
+	if streak := getStreak(t, 1); streak.CurrentStreak != 2 {
+		t.Errorf("Expected current streak 2, got %d", streak.CurrentStreak)
+	}
+
+	testActivityModel.Clear()
+	logActivitiesOn(1, -3, -2)
+	if streak := getStreak(t, 1); streak.CurrentStreak != 0 || streak.LongestStreak != 2 {
+		t.Errorf("Expected a broken streak with longest 2, got %+v", streak)
+	}
+}
+
+func TestStreakFreezeBridgesGap(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	today := activity.Today(time.Now(), time.UTC)
+	logActivitiesOn(1, -4, -2)
+	if streak := getStreak(t, 1); streak.CurrentStreak != 0 {
+		t.Fatalf("Expected the missed day to break the streak, got %d", streak.CurrentStreak)
+	}
+
+	// Freezing yesterday keeps the streak alive; frozen days do not add to it
+	yesterday := today.AddDate(0, 0, -1).Format(activity.DayLayout)
+	recorder := userRequest("POST", "/users/me/streak/freezes", 1, map[string]string{"date": yesterday})
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if streak := getStreak(t, 1); streak.CurrentStreak != 1 {
+		t.Errorf("Expected current streak 1, got %d", streak.CurrentStreak)
+	}
+
+	// Older gaps can only be covered through the model, not the API
+	threeDaysAgo := today.AddDate(0, 0, -3)
+	recorder = userRequest("POST", "/users/me/streak/freezes", 1, map[string]string{"date": threeDaysAgo.Format(activity.DayLayout)})
+	if status := recorder.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	testUserModel.AddStreakFreeze(user.StreakFreeze{UserID: 1, Day: threeDaysAgo})
+	if streak := getStreak(t, 1); streak.CurrentStreak != 2 || streak.LongestStreak != 2 {
+		t.Errorf("Expected current and longest streak 2, got %+v", streak)
+	}
+}
+
+func TestStreakFreezeLimits(t *testing.T) {
+	setupUserTest()
+
+	nextMonth := activity.Today(time.Now(), time.UTC).AddDate(0, 1, 0)
+	day := func(d int) string {
+		return time.Date(nextMonth.Year(), nextMonth.Month(), d, 0, 0, 0, 0, time.UTC).Format(activity.DayLayout)
+	}
+	freeze := func(date string) int {
+		return userRequest("POST", "/users/me/streak/freezes", 1, map[string]string{"date": date}).Code
+	}
+
+	if status := freeze(day(1)); status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if status := freeze(day(1)); status != http.StatusConflict {
+		t.Errorf("froze the same day twice: got %v want %v", status, http.StatusConflict)
+	}
+	if status := freeze(day(2)); status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if status := freeze(day(3)); status != http.StatusConflict {
+		t.Errorf("froze more days than allowed per month: got %v want %v", status, http.StatusConflict)
+	}
+	if status := freeze("next friday"); status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	if status := userRequest("DELETE", "/users/me/streak/freezes/"+day(2), 1, nil).Code; status != http.StatusNoContent {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+	if status := userRequest("DELETE", "/users/me/streak/freezes/"+day(2), 1, nil).Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+	if status := freeze(day(3)); status != http.StatusCreated {
+		t.Errorf("removing a freeze should free up the month: got %v want %v", status, http.StatusCreated)
+	}
+}
+
+func TestStreakUsesUserTimezone(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	// UTC+14 and UTC-11 are always on different calendar days
+	ahead, _ := time.LoadLocation("Pacific/Kiritimati")
+	behind, _ := time.LoadLocation("Pacific/Pago_Pago")
+	today := activity.Today(time.Now(), ahead)
+	testActivityModel.CreateActivity(activity.Activity{CreatorID: 1, Title: "Early bird", Date: today})
+
+	setTimezone := func(timezone string) int {
+		return userRequest("PUT", "/users/me/timezone", 1, map[string]string{"timezone": timezone}).Code
+	}
+
+	if status := setTimezone(ahead.String()); status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if streak := getStreak(t, 1); streak.CurrentStreak != 1 {
+		t.Errorf("Expected current streak 1 in %s, got %d", ahead, streak.CurrentStreak)
+	}
+
+	if status := setTimezone(behind.String()); status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if streak := getStreak(t, 1); streak.CurrentStreak != 0 {
+		t.Errorf("Expected no current streak in %s before the day starts, got %d", behind, streak.CurrentStreak)
+	}
+
+	for _, timezone := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		if status := setTimezone(timezone); status != http.StatusBadRequest {
+			t.Errorf("accepted time zone %q: got %v want %v", timezone, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetUserCalendar(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	for _, d := range []int{1, 1, 3, 9} {
+		testActivityModel.CreateActivity(activity.Activity{CreatorID: 1, Title: "Problem", Date: time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC)})
+	}
+	testUserModel.AddStreakFreeze(user.StreakFreeze{UserID: 1, Day: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)})
+
+	recorder := userRequest("GET", "/users/1/calendar?from=2025-07-01&to=2025-07-05", 0, nil)
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response struct {
+		Days []activity.CalendarDay `json:"days"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal("Failed to decode response body:", err)
+	}
+
+	want := []int{2, 0, 1, 0, 0}
+	if len(response.Days) != len(want) {
+		t.Fatalf("Expected %d days, got %d", len(want), len(response.Days))
+	}
+	for i, day := range response.Days {
+		if day.Count != want[i] {
+			t.Errorf("Day %s: expected %d activities, got %d", day.Date.Format(activity.DayLayout), want[i], day.Count)
+		}
+		if day.Frozen != (i == 1) {
+			t.Errorf("Day %s: unexpected frozen=%v", day.Date.Format(activity.DayLayout), day.Frozen)
+		}
+	}
+
+	for _, query := range []string{"from=2025-07-05&to=2025-07-01", "from=2024-01-01&to=2025-07-01", "from=yesterday"} {
+		if status := userRequest("GET", "/users/1/calendar?"+query, 0, nil).Code; status != http.StatusBadRequest {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", query, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetUserActivitiesFilters(t *testing.T) {
+	setupUserTest()
+	testActivityModel.Clear()
+
+	judge := func(j string) *string { return &j }
+	rating := func(r int) *int { return &r }
+	for _, a := range []activity.Activity{
+		{Title: "Watermelon", Judge: judge(activity.JudgeCodeforces), Rating: rating(800), Tags: activity.Tags{"math", "brute force"}},
+		{Title: "Knapsack", Judge: judge(activity.JudgeAtCoder), Rating: rating(1600), Tags: activity.Tags{"dp"}},
+		{Title: "Vacation", Judge: judge(activity.JudgeCodeforces), Rating: rating(1900), Tags: activity.Tags{"dp", "greedy"}},
+		{Title: "Journal"},
+	} {
+		a.CreatorID = 1
+		a.Date = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		testActivityModel.CreateActivity(a)
+	}
+
+	titles := func(query string) []string {
+		recorder := userRequest("GET", "/users/1/activities?"+query, 0, nil)
+		if status := recorder.Code; status != http.StatusOK {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", query, status, http.StatusOK)
+		}
+		var activities []activity.Activity
+		json.NewDecoder(recorder.Body).Decode(&activities)
+		var list []string
+		for _, a := range activities {
+			list = append(list, a.Title)
+		}
+		sort.Strings(list)
+		return list
+	}
+
+	cases := map[string][]string{
+		"":                                {"Journal", "Knapsack", "Vacation", "Watermelon"},
+		"judge=codeforces":                {"Vacation", "Watermelon"},
+		"tag=DP":                          {"Knapsack", "Vacation"},
+		"tag=brute%20force":               {"Watermelon"},
+		"min_rating=1600":                 {"Knapsack", "Vacation"},
+		"judge=codeforces&tag=dp":         {"Vacation"},
+		"judge=atcoder&min_rating=1700":   nil,
+		"judge=codeforces&min_rating=800": {"Vacation", "Watermelon"},
+	}
+	for query, want := range cases {
+		if got := titles(query); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%q: expected %v, got %v", query, want, got)
+		}
+	}
+
+	for _, query := range []string{"judge=hackerrank", "tag=%25", "min_rating=high"} {
+		if status := userRequest("GET", "/users/1/activities?"+query, 0, nil).Code; status != http.StatusBadRequest {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", query, status, http.StatusBadRequest)
+		}
+	}
+}